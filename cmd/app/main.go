@@ -8,6 +8,7 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
@@ -15,11 +16,16 @@ import (
 	"syscall"
 	"time"
 
+	"effective-mobile-task/internal/auth"
+	"effective-mobile-task/internal/cache"
 	"effective-mobile-task/internal/config"
 	httpHandler "effective-mobile-task/internal/handler/http"
+	"effective-mobile-task/internal/notify"
+	"effective-mobile-task/internal/ratelimit"
 	"effective-mobile-task/internal/repository/postgres"
 	"effective-mobile-task/internal/service"
 	"effective-mobile-task/pkg/logger"
+	"effective-mobile-task/pkg/resp"
 	_ "effective-mobile-task/docs"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -42,7 +48,7 @@ func main() {
 	for i := 0; i < maxRetries; i++ {
 		log.Info("попытка подключения к БД", "attempt", i+1)
 		dbPool, err = postgres.NewPostgresDB(cfg.Postgres)
-		if err == nil {
+		if err == nil || errors.Is(err, postgres.ErrRoleBypassesRLS) {
 			break
 		}
 
@@ -56,11 +62,90 @@ func main() {
 	}
 	defer dbPool.Close()
 	log.Info("успешное подключение к базе данных")
+	db := postgres.NewDB(dbPool)
 
 
-	subRepo := postgres.NewSubscriptionRepository(dbPool)
-	subService := service.NewSubscriptionService(subRepo)
-	handler := httpHandler.NewHandler(subService, log)
+	organisationRepo := postgres.NewOrganisationRepository(db)
+	policy := service.NewPolicy(organisationRepo)
+
+	serviceRepo := postgres.NewServiceRepository(db)
+	catalogService := service.NewCatalogService(serviceRepo, policy)
+
+	var defaultRole auth.Role
+	if cfg.Auth.DefaultRole != "" {
+		role, ok := auth.ParseRole(cfg.Auth.DefaultRole)
+		if !ok {
+			log.Error("неизвестная роль по умолчанию", "role", cfg.Auth.DefaultRole)
+			os.Exit(1)
+		}
+		defaultRole = role
+	}
+
+	subRepo := postgres.NewSubscriptionRepository(db)
+	var subscriptions service.SubscriptionRepository = subRepo
+	var subCache *cache.SubscriptionRepository
+	switch cfg.Cache.Backend {
+	case "none":
+	case "memory":
+		subCache = cache.NewSubscriptionRepository(subRepo, cache.NewLRU(cfg.Cache.Size), cfg.Cache.TTL, log)
+	case "redis":
+		redis := resp.NewClient(cfg.Cache.RedisAddr, cfg.Cache.RedisPassword, 16)
+		defer redis.Close()
+		subCache = cache.NewSubscriptionRepository(subRepo, cache.NewRedis(redis), cfg.Cache.TTL, log)
+	default:
+		log.Error("неизвестное хранилище кэша", "backend", cfg.Cache.Backend)
+		os.Exit(1)
+	}
+	if subCache != nil {
+		subscriptions = subCache
+	}
+	subService := service.NewSubscriptionService(subscriptions,
+		service.WithAllowOverlap(cfg.Subscriptions.AllowOverlap),
+		service.WithServiceCatalog(serviceRepo),
+		service.WithPolicy(policy),
+	)
+	notificationRepo := postgres.NewNotificationRepository(db)
+	notifier := notify.NewLogNotifier(log)
+	trialReminder := service.NewTrialReminder(subscriptions, notificationRepo, notifier, cfg.Trials.NotifyBefore)
+	budgetRepo := postgres.NewBudgetRepository(db)
+	budgetService := service.NewBudgetService(budgetRepo, subscriptions, policy)
+	budgetAlerter := service.NewBudgetAlerter(budgetRepo, subscriptions, notificationRepo, notifier)
+	rollupRefresher := service.NewRollupRefresher(subRepo)
+	trialReminder.SetTenants(db)
+	budgetAlerter.SetTenants(db)
+	rollupRefresher.SetTenants(db)
+	organisationService := service.NewOrganisationService(organisationRepo, subRepo, policy)
+	if cfg.Erasure.ReceiptSecret == "" {
+		log.Error("не задан ключ квитанций об удалении ERASURE_RECEIPT_SECRET")
+		os.Exit(1)
+	}
+	userDataRepo := postgres.NewUserDataRepository(db, []byte(cfg.Erasure.ReceiptSecret))
+	userDataService := service.NewUserDataService(userDataRepo, policy)
+
+	limiter, err := newRateLimiter(cfg.RateLimit, db)
+	if err != nil {
+		log.Error("неверная настройка лимитов запросов", "error", err)
+		os.Exit(1)
+	}
+
+	handler, err := httpHandler.NewHandler(httpHandler.Services{
+		Subscriptions: subService,
+		UserData:      userDataService,
+		Catalog:       catalogService,
+		Budgets:       budgetService,
+		Organisations: organisationService,
+		Tenants:       db,
+	}, log,
+		httpHandler.WithTenantRequired(cfg.Tenancy.Required),
+		httpHandler.WithTenantHeader(cfg.Tenancy.TrustHeader),
+		httpHandler.WithUserHeaders(cfg.Auth.TrustHeader),
+		httpHandler.WithDefaultRole(defaultRole),
+		httpHandler.WithRateLimiter(limiter),
+	)
+	if err != nil {
+		log.Error("не удалось инициализировать обработчики", "error", err)
+		os.Exit(1)
+	}
 
 
 	srv := &http.Server{
@@ -72,6 +157,27 @@ func main() {
 	}
 
 
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go trialReminder.Run(jobsCtx, cfg.Trials.CheckInterval, func(err error) {
+		log.Error("не удалось разослать напоминания о пробных периодах", "error", err)
+	})
+	go budgetAlerter.Run(jobsCtx, cfg.Budgets.CheckInterval, func(err error) {
+		log.Error("не удалось проверить бюджеты", "error", err)
+	})
+	go rollupRefresher.Run(jobsCtx, cfg.Rollup.RefreshInterval, func(err error) {
+		log.Error("не удалось обновить стоимость подписок по месяцам", "error", err)
+	})
+	if subCache != nil {
+		go db.Listen(jobsCtx, "subscription_changes", subCache.HandleNotification, func(err error) {
+			log.Error("потеряна подписка на изменения подписок", "error", err)
+		})
+	}
+	if cfg.RateLimit.Store == "postgres" && limiter != nil && limiter.MaxPeriod() > 0 {
+		go sweepRateLimits(jobsCtx, postgres.NewRateLimitStore(db), limiter.MaxPeriod(), log)
+	}
+
+
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
 
@@ -87,6 +193,7 @@ func main() {
 	<-stop
 
 	log.Info("сервер останавливается...")
+	stopJobs()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -99,3 +206,47 @@ func main() {
 	log.Info("сервер успешно остановлен")
 }
 
+// newRateLimiter собирает ограничение частоты запросов из конфигурации.
+// Возвращает nil, если ни один лимит не задан.
+func newRateLimiter(cfg config.RateLimitConfig, db *postgres.DB) (*ratelimit.Limiter, error) {
+	def, err := ratelimit.ParseLimit(cfg.Default)
+	if err != nil {
+		return nil, err
+	}
+	routes, err := ratelimit.ParseRoutes(cfg.Routes)
+	if err != nil {
+		return nil, err
+	}
+	if def.IsZero() && len(routes) == 0 {
+		return nil, nil
+	}
+
+	var store ratelimit.Store
+	switch cfg.Store {
+	case "memory":
+		store = ratelimit.NewMemoryStore()
+	case "postgres":
+		store = postgres.NewRateLimitStore(db)
+	default:
+		return nil, fmt.Errorf("неизвестное хранилище лимитов %q", cfg.Store)
+	}
+
+	return ratelimit.New(store, def, routes), nil
+}
+
+// sweepRateLimits раз в idle удаляет вёдра, которые наполнились целиком.
+func sweepRateLimits(ctx context.Context, store *postgres.RateLimitStore, idle time.Duration, log *slog.Logger) {
+	ticker := time.NewTicker(idle)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := store.DeleteIdle(ctx, idle); err != nil {
+				log.Error("не удалось удалить неактивные вёдра лимитов", "error", err)
+			}
+		}
+	}
+}
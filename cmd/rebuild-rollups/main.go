@@ -0,0 +1,70 @@
+// Команда rebuild-rollups строит заново таблицу стоимости подписок по
+// месяцам, из которой читаются сводки. Нужна после загрузки данных в обход
+// приложения и после восстановления базы из резервной копии.
+//
+//	go run ./cmd/rebuild-rollups [-tenant <id>]
+//
+// Без -tenant таблица строится для всех арендаторов.
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"os"
+
+	"effective-mobile-task/internal/config"
+	"effective-mobile-task/internal/repository/postgres"
+	"effective-mobile-task/internal/service"
+	"effective-mobile-task/pkg/logger"
+	"github.com/google/uuid"
+)
+
+func main() {
+	tenant := flag.String("tenant", "", "ID арендатора; без него таблица строится для всех арендаторов")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig(".")
+	if err != nil {
+		slog.Error("не удалось загрузить конфигурацию", "error", err)
+		os.Exit(1)
+	}
+
+	log := logger.New("local")
+
+	dbPool, err := postgres.NewPostgresDB(cfg.Postgres)
+	if err != nil {
+		log.Error("не удалось подключиться к базе данных", "error", err)
+		os.Exit(1)
+	}
+	defer dbPool.Close()
+	db := postgres.NewDB(dbPool)
+
+	refresher := service.NewRollupRefresher(postgres.NewSubscriptionRepository(db))
+
+	ctx := context.Background()
+	if *tenant != "" {
+		tenantID, err := uuid.Parse(*tenant)
+		if err != nil {
+			log.Error("неверный ID арендатора", "tenant", *tenant, "error", err)
+			os.Exit(1)
+		}
+		tenantCtx, err := db.WithTenant(ctx, tenantID)
+		if err != nil {
+			log.Error("не удалось выбрать арендатора", "tenant", tenantID, "error", err)
+			os.Exit(1)
+		}
+		ctx = tenantCtx
+	} else {
+		refresher.SetTenants(db)
+	}
+
+	err = refresher.Rebuild(ctx, func(ctx context.Context, refresh *postgres.MonthlySpendRefresh) {
+		tenantID, _ := postgres.TenantFromContext(ctx)
+		log.Info("стоимость подписок по месяцам построена", "tenant", tenantID, "horizon", refresh.Horizon.String())
+	})
+	if err != nil {
+		log.Error("не удалось построить стоимость подписок по месяцам", "error", err)
+		os.Exit(1)
+	}
+}
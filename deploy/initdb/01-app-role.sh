@@ -0,0 +1,14 @@
+#!/bin/sh
+# Образ postgres создаёт базу от имени суперпользователя POSTGRES_USER, на
+# которого не действуют политики RLS. Он владеет таблицами и выполняет
+# миграции, а приложение подключается отдельным пользователем без прав
+# суперпользователя и BYPASSRLS. Права на таблицы роль subscriptions_app
+# получает в миграции 000023.
+set -e
+
+psql -v ON_ERROR_STOP=1 --username "$POSTGRES_USER" --dbname "$POSTGRES_DB" \
+    -v app_user="$APP_DB_USER" -v app_password="$APP_DB_PASSWORD" <<'EOSQL'
+CREATE ROLE subscriptions_app NOLOGIN NOSUPERUSER NOBYPASSRLS;
+CREATE ROLE :"app_user" LOGIN NOSUPERUSER NOBYPASSRLS NOCREATEDB NOCREATEROLE
+    PASSWORD :'app_password' IN ROLE subscriptions_app;
+EOSQL
@@ -0,0 +1,51 @@
+// Package auth описывает, от чьего имени выполняется запрос.
+package auth
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Role — роль пользователя в арендаторе.
+type Role string
+
+const (
+	// RoleAdmin может всё, в том числе удалять подписки.
+	RoleAdmin Role = "admin"
+	// RoleManager читает все данные и меняет подписки пользователей своих
+	// организаций.
+	RoleManager Role = "manager"
+	// RoleViewer читает данные и строит сводки.
+	RoleViewer Role = "viewer"
+)
+
+// ParseRole возвращает роль по названию.
+func ParseRole(s string) (Role, bool) {
+	switch r := Role(s); r {
+	case RoleAdmin, RoleManager, RoleViewer:
+		return r, true
+	}
+	return "", false
+}
+
+// Principal — тот, от чьего имени выполняется запрос. UserID пуст у
+// токена арендатора и у запросов без токена.
+type Principal struct {
+	TenantID uuid.UUID
+	UserID   *uuid.UUID
+	Role     Role
+}
+
+type principalKey struct{}
+
+// WithPrincipal сохраняет в контексте, от чьего имени выполняется запрос.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext возвращает, от чьего имени выполняется запрос.
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
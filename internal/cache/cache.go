@@ -0,0 +1,24 @@
+// Package cache кэширует ответы репозитория подписок.
+//
+// Записи не удаляются по одной: ключ каждой записи содержит номера
+// поколений — общий и арендатора. Чтобы сбросить кэш арендатора, достаточно
+// увеличить его поколение, и старые записи больше никто не прочитает; они
+// вытесняются сами по TTL или LRU.
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Backend хранит записи кэша и счётчики поколений.
+type Backend interface {
+	// Get возвращает запись или false, если её нет или она устарела.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Generations возвращает значения счётчиков; отсутствующий счётчик
+	// равен нулю.
+	Generations(ctx context.Context, keys ...string) ([]int64, error)
+	// Bump увеличивает счётчик на единицу.
+	Bump(ctx context.Context, key string) error
+}
@@ -0,0 +1,159 @@
+package cache
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"effective-mobile-task/internal/models"
+	"effective-mobile-task/internal/repository/postgres"
+	"effective-mobile-task/internal/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(2)
+
+	require.NoError(t, c.Set(ctx, "a", []byte("1"), time.Minute))
+	require.NoError(t, c.Set(ctx, "b", []byte("2"), time.Minute))
+	_, ok, _ := c.Get(ctx, "a")
+	require.True(t, ok)
+	require.NoError(t, c.Set(ctx, "c", []byte("3"), time.Minute))
+
+	_, ok, _ = c.Get(ctx, "b")
+	assert.False(t, ok, "b прочитан давнее всех и должен быть вытеснен")
+	_, ok, _ = c.Get(ctx, "a")
+	assert.True(t, ok)
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestLRU_TTL(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	c := NewLRU(10)
+	c.now = func() time.Time { return now }
+
+	require.NoError(t, c.Set(ctx, "a", []byte("1"), time.Minute))
+	now = now.Add(time.Minute)
+
+	_, ok, _ := c.Get(ctx, "a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+}
+
+// fakeRepo считает обращения к GetByID и GetSummary.
+type fakeRepo struct {
+	service.SubscriptionRepository
+	sub       models.Subscription
+	byID      int
+	summaries int
+}
+
+func (f *fakeRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Subscription, error) {
+	f.byID++
+	sub := f.sub
+	return &sub, nil
+}
+
+func (f *fakeRepo) GetSummary(ctx context.Context, filter postgres.GetSummaryFilter) (*models.SummaryTotals, error) {
+	f.summaries++
+	return &models.SummaryTotals{Net: 100 * f.summaries}, nil
+}
+
+func (f *fakeRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+type tenantKey struct{}
+
+func newTestRepository(repo service.SubscriptionRepository) *SubscriptionRepository {
+	r := NewSubscriptionRepository(repo, NewLRU(100), time.Minute, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	r.tenant = func(ctx context.Context) (uuid.UUID, bool) {
+		id, ok := ctx.Value(tenantKey{}).(uuid.UUID)
+		return id, ok
+	}
+	return r
+}
+
+func TestSubscriptionRepository_GetByID(t *testing.T) {
+	id := uuid.New()
+	repo := &fakeRepo{sub: models.Subscription{ID: id, ServiceName: "Netflix", StartDate: models.NewMonth(2025, time.July)}}
+	r := newTestRepository(repo)
+	ctx := context.WithValue(context.Background(), tenantKey{}, uuid.New())
+
+	first, err := r.GetByID(ctx, id)
+	require.NoError(t, err)
+	// Изменения возвращённой подписки не должны попадать в кэш.
+	first.ServiceName = "changed"
+
+	second, err := r.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "Netflix", second.ServiceName)
+	assert.Equal(t, models.NewMonth(2025, time.July), second.StartDate)
+	assert.Equal(t, 1, repo.byID)
+
+	// Без арендатора запросы не кэшируются.
+	_, err = r.GetByID(context.Background(), id)
+	require.NoError(t, err)
+	assert.Equal(t, 2, repo.byID)
+}
+
+func TestSubscriptionRepository_GetSummary_Invalidation(t *testing.T) {
+	repo := &fakeRepo{}
+	r := newTestRepository(repo)
+	tenantA, tenantB := uuid.New(), uuid.New()
+	ctxA := context.WithValue(context.Background(), tenantKey{}, tenantA)
+	ctxB := context.WithValue(context.Background(), tenantKey{}, tenantB)
+	userID := uuid.New()
+
+	totals, _ := r.GetSummary(ctxA, postgres.GetSummaryFilter{})
+	assert.Equal(t, 100, totals.Net)
+	totals, _ = r.GetSummary(ctxA, postgres.GetSummaryFilter{})
+	assert.Equal(t, 100, totals.Net)
+
+	// Другой фильтр и другой арендатор — другие записи.
+	r.GetSummary(ctxA, postgres.GetSummaryFilter{UserID: &userID})
+	r.GetSummary(ctxB, postgres.GetSummaryFilter{})
+	assert.Equal(t, 3, repo.summaries)
+
+	// Удаление сбрасывает кэш своего арендатора.
+	require.NoError(t, r.Delete(ctxA, uuid.New()))
+	r.GetSummary(ctxA, postgres.GetSummaryFilter{})
+	r.GetSummary(ctxB, postgres.GetSummaryFilter{})
+	assert.Equal(t, 4, repo.summaries)
+
+	// Уведомление из другой реплики.
+	r.HandleNotification(tenantB.String())
+	r.GetSummary(ctxB, postgres.GetSummaryFilter{})
+	assert.Equal(t, 5, repo.summaries)
+
+	// Уведомление без арендатора сбрасывает всё.
+	r.HandleNotification("")
+	r.GetSummary(ctxA, postgres.GetSummaryFilter{})
+	r.GetSummary(ctxB, postgres.GetSummaryFilter{})
+	assert.Equal(t, 7, repo.summaries)
+}
+
+func TestSubscriptionRepository_GetSummary_NewMonth(t *testing.T) {
+	repo := &fakeRepo{}
+	r := newTestRepository(repo)
+	ctx := context.WithValue(context.Background(), tenantKey{}, uuid.New())
+	now := time.Date(2025, time.July, 31, 23, 59, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+
+	r.GetSummary(ctx, postgres.GetSummaryFilter{})
+	r.GetSummary(ctx, postgres.GetSummaryFilter{})
+	assert.Equal(t, 1, repo.summaries)
+
+	// Сводка без конца периода в новом месяце считается заново.
+	now = now.Add(2 * time.Minute)
+	totals, err := r.GetSummary(ctx, postgres.GetSummaryFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, 200, totals.Net)
+	assert.Equal(t, 2, repo.summaries)
+}
@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"effective-mobile-task/pkg/resp"
+)
+
+// Redis хранит записи в Redis-совместимом хранилище, общем для всех реплик.
+// У записей есть TTL, у счётчиков поколений — нет, поэтому хранилищу нужна
+// политика вытеснения volatile-*, которая не трогает ключи без TTL.
+type Redis struct {
+	client *resp.Client
+}
+
+func NewRedis(client *resp.Client) *Redis {
+	return &Redis{client: client}
+}
+
+func (c *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	reply, err := c.client.Do(ctx, "GET", key)
+	if err != nil {
+		if errors.Is(err, resp.ErrNil) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("Redis.Get: %w", err)
+	}
+
+	value, ok := reply.(string)
+	if !ok {
+		return nil, false, fmt.Errorf("Redis.Get: неожиданный ответ %T", reply)
+	}
+	return []byte(value), true, nil
+}
+
+func (c *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	_, err := c.client.Do(ctx, "SET", key, string(value), "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	if err != nil {
+		return fmt.Errorf("Redis.Set: %w", err)
+	}
+	return nil
+}
+
+func (c *Redis) Generations(ctx context.Context, keys ...string) ([]int64, error) {
+	reply, err := c.client.Do(ctx, append([]string{"MGET"}, keys...)...)
+	if err != nil {
+		return nil, fmt.Errorf("Redis.Generations: %w", err)
+	}
+
+	values, ok := reply.([]any)
+	if !ok || len(values) != len(keys) {
+		return nil, fmt.Errorf("Redis.Generations: неожиданный ответ %v", reply)
+	}
+
+	gens := make([]int64, len(keys))
+	for i, v := range values {
+		if v == nil {
+			continue
+		}
+		s, _ := v.(string)
+		if gens[i], err = strconv.ParseInt(s, 10, 64); err != nil {
+			return nil, fmt.Errorf("Redis.Generations: %w", err)
+		}
+	}
+	return gens, nil
+}
+
+func (c *Redis) Bump(ctx context.Context, key string) error {
+	if _, err := c.client.Do(ctx, "INCR", key); err != nil {
+		return fmt.Errorf("Redis.Bump: %w", err)
+	}
+	return nil
+}
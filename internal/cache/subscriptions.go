@@ -0,0 +1,245 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"effective-mobile-task/internal/models"
+	"effective-mobile-task/internal/repository/postgres"
+	"effective-mobile-task/internal/service"
+	"github.com/google/uuid"
+)
+
+// globalGeneration — поколение, общее для всех арендаторов.
+const globalGeneration = "subscriptions:gen"
+
+// SubscriptionRepository кэширует GetByID и GetSummary репозитория подписок.
+// Любое изменение подписок через него сбрасывает кэш арендатора; изменения
+// в других репликах и в обход репозитория приходят через Invalidate.
+// Запросы без арендатора не кэшируются. Ошибки кэша не мешают запросам:
+// они пишутся в лог, а данные читаются из репозитория.
+type SubscriptionRepository struct {
+	service.SubscriptionRepository
+	backend Backend
+	ttl     time.Duration
+	log     *slog.Logger
+	// tenant возвращает арендатора, от имени которого выполняется ctx.
+	tenant func(ctx context.Context) (uuid.UUID, bool)
+	now    func() time.Time
+}
+
+func NewSubscriptionRepository(repo service.SubscriptionRepository, backend Backend, ttl time.Duration, log *slog.Logger) *SubscriptionRepository {
+	return &SubscriptionRepository{
+		SubscriptionRepository: repo,
+		backend:                backend,
+		ttl:                    ttl,
+		log:                    log,
+		tenant:                 postgres.TenantFromContext,
+		now:                    time.Now,
+	}
+}
+
+func (r *SubscriptionRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Subscription, error) {
+	key, ok := r.key(ctx, "id", id.String())
+
+	var sub models.Subscription
+	if ok && r.load(ctx, key, &sub) {
+		return &sub, nil
+	}
+
+	found, err := r.SubscriptionRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		r.store(ctx, key, found)
+	}
+	return found, nil
+}
+
+func (r *SubscriptionRepository) GetSummary(ctx context.Context, filter postgres.GetSummaryFilter) (*models.SummaryTotals, error) {
+	raw, err := json.Marshal(filter)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось построить ключ кэша сводки: %w", err)
+	}
+	sum := sha256.Sum256(raw)
+	// Сводка зависит от текущего месяца: без конца периода он ограничивает
+	// расчёт, а цены и состояния подписок берутся на него. С началом нового
+	// месяца ключ меняется.
+	month := models.MonthOf(r.now().UTC()).String()
+	key, ok := r.key(ctx, "summary", month+":"+hex.EncodeToString(sum[:]))
+
+	var totals models.SummaryTotals
+	if ok && r.load(ctx, key, &totals) {
+		return &totals, nil
+	}
+
+	found, err := r.SubscriptionRepository.GetSummary(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		r.store(ctx, key, found)
+	}
+	return found, nil
+}
+
+// Invalidate сбрасывает кэш арендатора tenantID.
+func (r *SubscriptionRepository) Invalidate(ctx context.Context, tenantID uuid.UUID) {
+	if err := r.backend.Bump(ctx, tenantGeneration(tenantID)); err != nil {
+		r.log.Error("не удалось сбросить кэш подписок", "tenant_id", tenantID, "error", err)
+	}
+}
+
+// InvalidateAll сбрасывает кэш всех арендаторов.
+func (r *SubscriptionRepository) InvalidateAll(ctx context.Context) {
+	if err := r.backend.Bump(ctx, globalGeneration); err != nil {
+		r.log.Error("не удалось сбросить кэш подписок", "error", err)
+	}
+}
+
+// HandleNotification сбрасывает кэш по уведомлению об изменении подписок:
+// содержимое — ID арендатора или пустая строка, если арендатор неизвестен.
+func (r *SubscriptionRepository) HandleNotification(payload string) {
+	ctx := context.Background()
+	if tenantID, err := uuid.Parse(payload); err == nil {
+		r.Invalidate(ctx, tenantID)
+		return
+	}
+	r.InvalidateAll(ctx)
+}
+
+func (r *SubscriptionRepository) Create(ctx context.Context, sub *models.Subscription) error {
+	defer r.invalidate(ctx)
+	return r.SubscriptionRepository.Create(ctx, sub)
+}
+
+func (r *SubscriptionRepository) Update(ctx context.Context, sub *models.Subscription) error {
+	defer r.invalidate(ctx)
+	return r.SubscriptionRepository.Update(ctx, sub)
+}
+
+func (r *SubscriptionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	defer r.invalidate(ctx)
+	return r.SubscriptionRepository.Delete(ctx, id)
+}
+
+func (r *SubscriptionRepository) AddTags(ctx context.Context, subscriptionID uuid.UUID, tags []string) ([]string, error) {
+	defer r.invalidate(ctx)
+	return r.SubscriptionRepository.AddTags(ctx, subscriptionID, tags)
+}
+
+func (r *SubscriptionRepository) RemoveTag(ctx context.Context, subscriptionID uuid.UUID, tag string) ([]string, error) {
+	defer r.invalidate(ctx)
+	return r.SubscriptionRepository.RemoveTag(ctx, subscriptionID, tag)
+}
+
+func (r *SubscriptionRepository) ChangeStatus(ctx context.Context, t postgres.StatusTransition) error {
+	defer r.invalidate(ctx)
+	return r.SubscriptionRepository.ChangeStatus(ctx, t)
+}
+
+func (r *SubscriptionRepository) SchedulePrice(ctx context.Context, subscriptionID uuid.UUID, from models.Month, unitPrice int) error {
+	defer r.invalidate(ctx)
+	return r.SubscriptionRepository.SchedulePrice(ctx, subscriptionID, from, unitPrice)
+}
+
+func (r *SubscriptionRepository) ScheduleSeats(ctx context.Context, subscriptionID uuid.UUID, from models.Month, quantity int) error {
+	defer r.invalidate(ctx)
+	return r.SubscriptionRepository.ScheduleSeats(ctx, subscriptionID, from, quantity)
+}
+
+func (r *SubscriptionRepository) AddDiscount(ctx context.Context, d *models.Discount) error {
+	defer r.invalidate(ctx)
+	return r.SubscriptionRepository.AddDiscount(ctx, d)
+}
+
+func (r *SubscriptionRepository) DeleteDiscount(ctx context.Context, subscriptionID, discountID uuid.UUID) error {
+	defer r.invalidate(ctx)
+	return r.SubscriptionRepository.DeleteDiscount(ctx, subscriptionID, discountID)
+}
+
+func (r *SubscriptionRepository) SetMember(ctx context.Context, m *models.SubscriptionMember) error {
+	defer r.invalidate(ctx)
+	return r.SubscriptionRepository.SetMember(ctx, m)
+}
+
+func (r *SubscriptionRepository) RemoveMember(ctx context.Context, subscriptionID, userID uuid.UUID) error {
+	defer r.invalidate(ctx)
+	return r.SubscriptionRepository.RemoveMember(ctx, subscriptionID, userID)
+}
+
+func (r *SubscriptionRepository) SetCostCentre(ctx context.Context, id uuid.UUID, costCentreID *uuid.UUID) error {
+	defer r.invalidate(ctx)
+	return r.SubscriptionRepository.SetCostCentre(ctx, id, costCentreID)
+}
+
+func (r *SubscriptionRepository) ApplyBatch(ctx context.Context, ops []postgres.BatchOperation, atomic bool) ([]error, error) {
+	defer r.invalidate(ctx)
+	return r.SubscriptionRepository.ApplyBatch(ctx, ops, atomic)
+}
+
+func (r *SubscriptionRepository) ImportSubscriptions(ctx context.Context, next func() (*postgres.ImportedSubscription, error), checks postgres.ImportChecks) (int64, error) {
+	defer r.invalidate(ctx)
+	return r.SubscriptionRepository.ImportSubscriptions(ctx, next, checks)
+}
+
+// invalidate сбрасывает кэш арендатора, от имени которого выполняется ctx.
+func (r *SubscriptionRepository) invalidate(ctx context.Context) {
+	if tenantID, ok := r.tenant(ctx); ok {
+		r.Invalidate(ctx, tenantID)
+	}
+}
+
+// key возвращает ключ записи в текущих поколениях арендатора или false,
+// если запрос не кэшируется.
+func (r *SubscriptionRepository) key(ctx context.Context, kind, arg string) (string, bool) {
+	tenantID, ok := r.tenant(ctx)
+	if !ok {
+		return "", false
+	}
+
+	gens, err := r.backend.Generations(ctx, globalGeneration, tenantGeneration(tenantID))
+	if err != nil {
+		r.log.Error("не удалось прочитать поколение кэша подписок", "error", err)
+		return "", false
+	}
+
+	return fmt.Sprintf("subscriptions:%s:%d.%d:%s:%s", tenantID, gens[0], gens[1], kind, arg), true
+}
+
+func (r *SubscriptionRepository) load(ctx context.Context, key string, v any) bool {
+	raw, ok, err := r.backend.Get(ctx, key)
+	if err != nil {
+		r.log.Error("не удалось прочитать кэш подписок", "error", err)
+		return false
+	}
+	if !ok {
+		return false
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		r.log.Error("не удалось разобрать запись кэша подписок", "key", key, "error", err)
+		return false
+	}
+	return true
+}
+
+func (r *SubscriptionRepository) store(ctx context.Context, key string, v any) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		r.log.Error("не удалось сохранить запись кэша подписок", "key", key, "error", err)
+		return
+	}
+	if err := r.backend.Set(ctx, key, raw, r.ttl); err != nil {
+		r.log.Error("не удалось сохранить запись кэша подписок", "key", key, "error", err)
+	}
+}
+
+func tenantGeneration(tenantID uuid.UUID) string {
+	return "subscriptions:" + tenantID.String() + ":gen"
+}
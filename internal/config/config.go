@@ -2,13 +2,120 @@ package config
 
 import (
 	"fmt"
+	"time"
+
 	"github.com/spf13/viper"
 )
 
 
 type Config struct {
-	HTTPPort string
-	Postgres PostgresConfig
+	HTTPPort      string
+	Postgres      PostgresConfig
+	Subscriptions SubscriptionsConfig
+	Trials        TrialsConfig
+	Budgets       BudgetsConfig
+	Tenancy       TenancyConfig
+	Auth          AuthConfig
+	RateLimit     RateLimitConfig
+	Cache         CacheConfig
+	Rollup        RollupConfig
+	Erasure       ErasureConfig
+}
+
+
+type SubscriptionsConfig struct {
+	// AllowOverlap разрешает пересекающиеся подписки пользователя на один сервис.
+	AllowOverlap bool
+}
+
+
+// TrialsConfig настраивает напоминания об окончании пробных периодов.
+type TrialsConfig struct {
+	// NotifyBefore — за сколько до перехода подписки на оплату предупреждать
+	// пользователя.
+	NotifyBefore time.Duration
+	// CheckInterval — как часто искать заканчивающиеся пробные периоды.
+	CheckInterval time.Duration
+}
+
+
+// BudgetsConfig настраивает уведомления о превышении порогов бюджетов.
+type BudgetsConfig struct {
+	// CheckInterval — как часто сверять расходы с бюджетами.
+	CheckInterval time.Duration
+}
+
+
+// TenancyConfig настраивает определение арендатора запроса.
+type TenancyConfig struct {
+	// Required запрещает запросы без арендатора. Иначе такие запросы
+	// относятся к арендатору по умолчанию.
+	Required bool
+	// TrustHeader разрешает указывать арендатора заголовком X-Tenant-ID.
+	// Заголовок стоит принимать, только если его выставляет шлюз перед
+	// сервисом, а не клиент, поэтому по умолчанию он не принимается.
+	TrustHeader bool
+}
+
+
+// AuthConfig настраивает права запросов.
+type AuthConfig struct {
+	// DefaultRole — роль запросов без токена доступа: admin, manager или
+	// viewer. Пустая роль запрещает такие запросы.
+	DefaultRole string
+	// TrustHeader разрешает указывать пользователя и роль заголовками
+	// X-User-ID и X-User-Role. Как и X-Tenant-ID, их стоит принимать,
+	// только если их выставляет шлюз, поэтому по умолчанию они не
+	// принимаются.
+	TrustHeader bool
+}
+
+
+// RollupConfig настраивает таблицу стоимости подписок по месяцам, из
+// которой читаются сводки.
+type RollupConfig struct {
+	// RefreshInterval — как часто пересчитывать изменившиеся подписки.
+	// Сводка и сама пересчитывает их перед чтением, поэтому интервал
+	// влияет только на то, сколько работы останется запросам.
+	RefreshInterval time.Duration
+}
+
+
+// ErasureConfig настраивает квитанции об удалении персональных данных.
+type ErasureConfig struct {
+	// ReceiptSecret — ключ HMAC, которым в квитанции заменяется user_id.
+	// Обязателен: ключ должен быть одинаковым во всех репликах и не
+	// меняться, иначе квитанцию нельзя будет найти по user_id.
+	ReceiptSecret string
+}
+
+
+// RateLimitConfig настраивает ограничение частоты запросов.
+type RateLimitConfig struct {
+	// Default — лимит маршрутов без своего лимита, например «100/1m».
+	// Пустой лимит не ограничивает запросы.
+	Default string
+	// Routes — лимиты отдельных маршрутов, например
+	// «GET /subscriptions/summary=10/1m, /subscriptions/search=30/1m».
+	Routes string
+	// Store — где хранить вёдра: memory (в каждой реплике отдельно) или
+	// postgres (общие для всех реплик).
+	Store string
+}
+
+
+// CacheConfig настраивает кэш подписок и сводок.
+type CacheConfig struct {
+	// Backend — где хранить записи: none (без кэша), memory (в памяти
+	// каждой реплики) или redis (в Redis-совместимом хранилище).
+	Backend string
+	// TTL — сколько живёт запись.
+	TTL time.Duration
+	// Size — сколько записей держит кэш в памяти.
+	Size int
+	// RedisAddr и RedisPassword — адрес и пароль хранилища для backend redis.
+	RedisAddr     string
+	RedisPassword string
 }
 
 
@@ -19,6 +126,11 @@ type PostgresConfig struct {
 	Password string
 	DBName   string
 	SSLMode  string
+	// MaxConns — размер пула соединений; 0 — по умолчанию pgxpool
+	// (больше из 4 и числа процессоров). Соединение занято на время
+	// запроса к базе или транзакции, потоковые выгрузка и импорт держат его
+	// до конца передачи.
+	MaxConns int32
 }
 
 
@@ -55,6 +167,43 @@ func LoadConfig(path string) (*Config, error) {
 			Password: viper.GetString("POSTGRES_PASSWORD"),
 			DBName:   viper.GetString("POSTGRES_DB"),
 			SSLMode:  viper.GetString("POSTGRES_SSLMODE"),
+			MaxConns: viper.GetInt32("POSTGRES_MAX_CONNS"),
+		},
+		Subscriptions: SubscriptionsConfig{
+			AllowOverlap: viper.GetBool("SUBSCRIPTIONS_ALLOW_OVERLAP"),
+		},
+		Trials: TrialsConfig{
+			NotifyBefore:  viper.GetDuration("TRIALS_NOTIFY_BEFORE"),
+			CheckInterval: viper.GetDuration("TRIALS_CHECK_INTERVAL"),
+		},
+		Budgets: BudgetsConfig{
+			CheckInterval: viper.GetDuration("BUDGETS_CHECK_INTERVAL"),
+		},
+		Tenancy: TenancyConfig{
+			Required:    viper.GetBool("TENANCY_REQUIRED"),
+			TrustHeader: viper.GetBool("TENANCY_TRUST_HEADER"),
+		},
+		Auth: AuthConfig{
+			DefaultRole: viper.GetString("AUTH_DEFAULT_ROLE"),
+			TrustHeader: viper.GetBool("AUTH_TRUST_HEADER"),
+		},
+		RateLimit: RateLimitConfig{
+			Default: viper.GetString("RATE_LIMIT_DEFAULT"),
+			Routes:  viper.GetString("RATE_LIMIT_ROUTES"),
+			Store:   viper.GetString("RATE_LIMIT_STORE"),
+		},
+		Cache: CacheConfig{
+			Backend:       viper.GetString("CACHE_BACKEND"),
+			TTL:           viper.GetDuration("CACHE_TTL"),
+			Size:          viper.GetInt("CACHE_SIZE"),
+			RedisAddr:     viper.GetString("CACHE_REDIS_ADDR"),
+			RedisPassword: viper.GetString("CACHE_REDIS_PASSWORD"),
+		},
+		Rollup: RollupConfig{
+			RefreshInterval: viper.GetDuration("ROLLUP_REFRESH_INTERVAL"),
+		},
+		Erasure: ErasureConfig{
+			ReceiptSecret: viper.GetString("ERASURE_RECEIPT_SECRET"),
 		},
 	}
 	
@@ -62,6 +211,39 @@ func LoadConfig(path string) (*Config, error) {
 	if cfg.HTTPPort == "" {
 		cfg.HTTPPort = "8080"
 	}
+	if cfg.Trials.NotifyBefore <= 0 {
+		cfg.Trials.NotifyBefore = 72 * time.Hour
+	}
+	if cfg.Trials.CheckInterval <= 0 {
+		cfg.Trials.CheckInterval = time.Hour
+	}
+	if cfg.Budgets.CheckInterval <= 0 {
+		cfg.Budgets.CheckInterval = time.Hour
+	}
+	if !viper.IsSet("AUTH_DEFAULT_ROLE") {
+		cfg.Auth.DefaultRole = "viewer"
+	}
+	if !viper.IsSet("RATE_LIMIT_ROUTES") {
+		cfg.RateLimit.Routes = "GET /subscriptions/summary=60/1m, GET /subscriptions/summary/categories=60/1m"
+	}
+	if cfg.RateLimit.Store == "" {
+		cfg.RateLimit.Store = "memory"
+	}
+	if cfg.Cache.Backend == "" {
+		cfg.Cache.Backend = "memory"
+	}
+	if cfg.Cache.TTL <= 0 {
+		cfg.Cache.TTL = 30 * time.Second
+	}
+	if cfg.Cache.Size <= 0 {
+		cfg.Cache.Size = 10000
+	}
+	if cfg.Cache.RedisAddr == "" {
+		cfg.Cache.RedisAddr = "localhost:6379"
+	}
+	if cfg.Rollup.RefreshInterval <= 0 {
+		cfg.Rollup.RefreshInterval = time.Minute
+	}
 
 	return cfg, nil
 }
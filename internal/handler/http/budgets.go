@@ -0,0 +1,218 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"effective-mobile-task/internal/i18n"
+	"effective-mobile-task/internal/repository/postgres"
+	"effective-mobile-task/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// CreateBudget обрабатывает запрос на создание бюджета пользователя.
+// @Summary Create a monthly budget
+// @Description Creates a monthly budget for all subscriptions of the user (scope=overall), for one catalogue category (scope=category) or for one service (scope=service). When spending of the current month reaches a threshold (percent of the amount, 80 and 100 by default), the user is notified once per threshold and month.
+// @Tags budgets
+// @Accept  json
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   user_id  path      string             true  "User ID"
+// @Param   budget   body      service.BudgetDTO  true  "Budget"
+// @Success 201      {object}  models.Budget
+// @Failure 400      {string}  string "Неверный формат JSON или неверные данные"
+// @Failure 403      {object}  problem "Недостаточно прав"
+// @Failure 409      {string}  string "Бюджет на эту область уже есть"
+// @Failure 500      {string}  string "Внутренняя ошибка сервера"
+// @Router /users/{user_id}/budgets [post]
+func (h *Handler) CreateBudget(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidUserID)
+		return
+	}
+
+	var dto service.BudgetDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidJSON)
+		return
+	}
+
+	if err := h.validate.Struct(dto); err != nil {
+		h.log.Warn("неверные данные", "error", err)
+		h.respondValidationError(w, r, err)
+		return
+	}
+
+	budget, err := h.budgets.Create(r.Context(), userID, dto)
+	if err != nil {
+		if h.respondBudgetError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось создать бюджет", "user_id", userID, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, budget)
+}
+
+// ListBudgets обрабатывает запрос на получение бюджетов пользователя.
+// @Summary List budgets of a user
+// @Tags budgets
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   user_id  path      string  true  "User ID"
+// @Success 200      {array}   models.Budget
+// @Failure 400      {string}  string "Неверный формат user_id"
+// @Failure 500      {string}  string "Внутренняя ошибка сервера"
+// @Router /users/{user_id}/budgets [get]
+func (h *Handler) ListBudgets(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidUserID)
+		return
+	}
+
+	budgets, err := h.budgets.List(r.Context(), userID)
+	if err != nil {
+		h.log.Error("не удалось получить бюджеты", "user_id", userID, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, budgets)
+}
+
+// GetBudgetStatus обрабатывает запрос на получение состояния бюджетов пользователя.
+// @Summary Get budget status of a user
+// @Description For every budget of the user returns the spending of the current month (computed like /subscriptions/summary, after discounts), the projected spending of the next month with scheduled price, seat and discount changes, and the thresholds already reached.
+// @Tags budgets
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   user_id  path      string  true  "User ID"
+// @Success 200      {array}   models.BudgetStatus
+// @Failure 400      {string}  string "Неверный формат user_id"
+// @Failure 500      {string}  string "Внутренняя ошибка сервера"
+// @Router /users/{user_id}/budgets/status [get]
+func (h *Handler) GetBudgetStatus(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidUserID)
+		return
+	}
+
+	statuses, err := h.budgets.Status(r.Context(), userID)
+	if err != nil {
+		h.log.Error("не удалось получить состояние бюджетов", "user_id", userID, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, statuses)
+}
+
+// UpdateBudget обрабатывает запрос на изменение бюджета пользователя.
+// @Summary Update a budget
+// @Tags budgets
+// @Accept  json
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   user_id    path      string             true  "User ID"
+// @Param   budget_id  path      string             true  "Budget ID"
+// @Param   budget     body      service.BudgetDTO  true  "Budget"
+// @Success 200        {object}  models.Budget
+// @Failure 400        {string}  string "Неверный формат JSON или неверные данные"
+// @Failure 403        {object}  problem "Недостаточно прав"
+// @Failure 404        {string}  string "Бюджет не найден"
+// @Failure 409        {string}  string "Бюджет на эту область уже есть"
+// @Failure 500        {string}  string "Внутренняя ошибка сервера"
+// @Router /users/{user_id}/budgets/{budget_id} [put]
+func (h *Handler) UpdateBudget(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidUserID)
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "budget_id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+
+	var dto service.BudgetDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidJSON)
+		return
+	}
+
+	if err := h.validate.Struct(dto); err != nil {
+		h.log.Warn("неверные данные", "error", err)
+		h.respondValidationError(w, r, err)
+		return
+	}
+
+	budget, err := h.budgets.Update(r.Context(), userID, id, dto)
+	if err != nil {
+		if h.respondBudgetError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось обновить бюджет", "user_id", userID, "id", id, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, budget)
+}
+
+// DeleteBudget обрабатывает запрос на удаление бюджета пользователя.
+// @Summary Delete a budget
+// @Tags budgets
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   user_id    path      string  true  "User ID"
+// @Param   budget_id  path      string  true  "Budget ID"
+// @Success 204
+// @Failure 400        {string}  string "Неверный формат ID"
+// @Failure 403        {object}  problem "Недостаточно прав"
+// @Failure 404        {string}  string "Бюджет не найден"
+// @Failure 500        {string}  string "Внутренняя ошибка сервера"
+// @Router /users/{user_id}/budgets/{budget_id} [delete]
+func (h *Handler) DeleteBudget(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidUserID)
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "budget_id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+
+	if err := h.budgets.Delete(r.Context(), userID, id); err != nil {
+		if h.respondBudgetError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось удалить бюджет", "user_id", userID, "id", id, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respondBudgetError отвечает клиенту на ошибки бюджетов.
+// Возвращает false, если ошибка к ним не относится.
+func (h *Handler) respondBudgetError(w http.ResponseWriter, r *http.Request, err error) bool {
+	switch {
+	case errors.Is(err, postgres.ErrBudgetNotFound):
+		h.respondError(w, r, http.StatusNotFound, i18n.BudgetNotFound)
+		return true
+	case errors.Is(err, postgres.ErrBudgetConflict):
+		h.respondError(w, r, http.StatusConflict, i18n.BudgetConflict)
+		return true
+	}
+	return h.respondDomainError(w, r, err)
+}
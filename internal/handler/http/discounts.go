@@ -0,0 +1,142 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"effective-mobile-task/internal/i18n"
+	"effective-mobile-task/internal/repository/postgres"
+	"effective-mobile-task/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// AddDiscount обрабатывает запрос на добавление скидки на подписку.
+// @Summary Add a discount to a subscription
+// @Description Adds a percentage or fixed-amount discount valid from start_month to end_month inclusive. start_month defaults to the first billed month (after the free trial); the term is set by end_month or by the number of months, and without either the discount lasts until the subscription ends. Discounts active in the same month add up. An optional promo_code labels the discount.
+// @Tags subscriptions
+// @Accept  json
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   id        path      string               true  "Subscription ID"
+// @Param   discount  body      service.DiscountDTO  true  "Discount"
+// @Success 201       {object}  models.Discount
+// @Failure 400       {string}  string "Неверный формат JSON или неверные данные"
+// @Failure 403       {object}  problem "Недостаточно прав"
+// @Failure 404       {string}  string "Подписка не найдена"
+// @Failure 500       {string}  string "Внутренняя ошибка сервера"
+// @Router /subscriptions/{id}/discounts [post]
+func (h *Handler) AddDiscount(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+
+	var dto service.DiscountDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidJSON)
+		return
+	}
+
+	if err := h.validate.Struct(dto); err != nil {
+		h.log.Warn("неверные данные", "error", err)
+		h.respondValidationError(w, r, err)
+		return
+	}
+
+	discount, err := h.service.AddDiscount(r.Context(), id, dto)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			h.respondError(w, r, http.StatusNotFound, i18n.NotFound)
+			return
+		}
+		if h.respondDomainError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось добавить скидку", "id", id, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, discount)
+}
+
+// ListDiscounts обрабатывает запрос на получение скидок подписки.
+// @Summary List discounts of a subscription
+// @Description Returns the discounts of the subscription ordered by start month.
+// @Tags subscriptions
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   id   path      string  true  "Subscription ID"
+// @Success 200  {array}   models.Discount
+// @Failure 400  {string}  string "Неверный формат ID"
+// @Failure 404  {string}  string "Подписка не найдена"
+// @Failure 500  {string}  string "Внутренняя ошибка сервера"
+// @Router /subscriptions/{id}/discounts [get]
+func (h *Handler) ListDiscounts(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+
+	discounts, err := h.service.ListDiscounts(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			h.respondError(w, r, http.StatusNotFound, i18n.NotFound)
+			return
+		}
+		h.log.Error("не удалось получить скидки", "id", id, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, discounts)
+}
+
+// DeleteDiscount обрабатывает запрос на удаление скидки подписки.
+// @Summary Delete a discount
+// @Description Removes the discount from the subscription. Summaries no longer apply it, including in past months.
+// @Tags subscriptions
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   id           path      string  true  "Subscription ID"
+// @Param   discount_id  path      string  true  "Discount ID"
+// @Success 204
+// @Failure 400          {string}  string "Неверный формат ID"
+// @Failure 403          {object}  problem "Недостаточно прав"
+// @Failure 404          {string}  string "Скидка не найдена"
+// @Failure 500          {string}  string "Внутренняя ошибка сервера"
+// @Router /subscriptions/{id}/discounts/{discount_id} [delete]
+func (h *Handler) DeleteDiscount(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+	discountID, err := uuid.Parse(chi.URLParam(r, "discount_id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+
+	if err := h.service.DeleteDiscount(r.Context(), id, discountID); err != nil {
+		if errors.Is(err, postgres.ErrDiscountNotFound) {
+			h.respondError(w, r, http.StatusNotFound, i18n.DiscountNotFound)
+			return
+		}
+		if errors.Is(err, postgres.ErrNotFound) {
+			h.respondError(w, r, http.StatusNotFound, i18n.NotFound)
+			return
+		}
+		if h.respondDomainError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось удалить скидку", "id", id, "discount_id", discountID, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,298 @@
+package http
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"effective-mobile-task/internal/i18n"
+	"effective-mobile-task/internal/models"
+	"effective-mobile-task/pkg/xlsx"
+)
+
+// exportTimeout — сколько времени даётся на выгрузку одного файла.
+const exportTimeout = 30 * time.Minute
+
+const (
+	exportFormatCSV    = "csv"
+	exportFormatNDJSON = "ndjson"
+	exportFormatXLSX   = "xlsx"
+)
+
+var exportContentTypes = map[string]string{
+	exportFormatCSV:    "text/csv; charset=utf-8",
+	exportFormatNDJSON: "application/x-ndjson",
+	exportFormatXLSX:   "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+type exportColumn struct {
+	name  string
+	value func(*models.Subscription) any
+}
+
+// exportColumns перечисляет колонки выгрузки в порядке по умолчанию.
+var exportColumns = []exportColumn{
+	{"id", func(s *models.Subscription) any { return s.ID.String() }},
+	{"user_id", func(s *models.Subscription) any { return s.UserID.String() }},
+	{"service_name", func(s *models.Subscription) any { return s.ServiceName }},
+	{"service_id", func(s *models.Subscription) any {
+		if s.ServiceID == nil {
+			return nil
+		}
+		return s.ServiceID.String()
+	}},
+	{"price", func(s *models.Subscription) any { return s.Price }},
+	{"unit_price", func(s *models.Subscription) any { return s.UnitPrice }},
+	{"quantity", func(s *models.Subscription) any { return s.Quantity }},
+	{"start_date", func(s *models.Subscription) any { return s.StartDate.String() }},
+	{"end_date", func(s *models.Subscription) any {
+		if s.EndDate == nil {
+			return nil
+		}
+		return s.EndDate.String()
+	}},
+	{"trial_end_date", func(s *models.Subscription) any {
+		if s.TrialEndDate == nil {
+			return nil
+		}
+		return s.TrialEndDate.String()
+	}},
+	{"status", func(s *models.Subscription) any { return string(s.Status) }},
+}
+
+// ExportSubscriptions выгружает подписки в файл.
+// @Summary Export subscriptions as CSV, NDJSON or XLSX
+// @Description Streams subscriptions matching the same filters as /subscriptions/summary directly from the database cursor to the response.
+// @Tags subscriptions
+// @Produce  text/csv
+// @Produce  application/x-ndjson
+// @Produce  application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   format        query     string  false  "File format (default csv)"  Enums(csv, ndjson, xlsx)
+// @Param   columns       query     string  false  "Comma-separated columns: id,user_id,service_name,service_id,price,unit_price,quantity,start_date,end_date,trial_end_date,status (default all)"
+// @Param   user_id       query     string  false  "Filter by User ID (UUID format)"
+// @Param   service_name  query     string  false  "Filter by Service Name"
+// @Param   service_name_like  query  string  false  "Filter by service name prefix or similar spelling"
+// @Param   category      query     string  false  "Filter by service catalogue category"
+// @Param   tags          query     string  false  "Filter by comma-separated tags"
+// @Param   tags_match    query     string  false  "Whether a subscription must have any (default) or all of the tags"  Enums(any, all)
+// @Param   start_date    query     string  false  "Filter by start month (MM-YYYY, YYYY-MM or YYYY-MM-DD)"
+// @Param   end_date      query     string  false  "Filter by end month (MM-YYYY, YYYY-MM or YYYY-MM-DD)"
+// @Param   as_of         query     string  false  "Read subscription versions that were current at this moment (RFC 3339)"
+// @Success 200  {file}    file
+// @Failure 400  {string}  string "Неверный фильтр, формат или список колонок"
+// @Failure 500  {string}  string "Внутренняя ошибка сервера"
+// @Router /subscriptions/export [get]
+func (h *Handler) ExportSubscriptions(w http.ResponseWriter, r *http.Request) {
+	filter, key, ok := parseSummaryFilter(r)
+	if !ok {
+		h.respondError(w, r, http.StatusBadRequest, key)
+		return
+	}
+
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = exportFormatCSV
+	}
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		h.respondError(w, r, http.StatusBadRequest, i18n.ExportUnsupportedFormat)
+		return
+	}
+
+	columns, unknown := selectExportColumns(r.URL.Query().Get("columns"))
+	if unknown != "" {
+		loc := i18n.FromContext(r.Context())
+		http.Error(w, i18n.Translate(loc, i18n.ExportUnknownColumn, unknown), http.StatusBadRequest)
+		return
+	}
+
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(exportTimeout)); err != nil {
+		h.log.Debug("не удалось продлить таймаут записи", "error", err)
+	}
+
+	// Файл создаётся при первой строке: если запрос к БД упадёт сразу,
+	// клиент ещё может получить нормальный ответ 500.
+	var out exportWriter
+	start := func() {
+		filename := "subscriptions-" + time.Now().UTC().Format("20060102-150405") + "." + format
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+		w.WriteHeader(http.StatusOK)
+		names := make([]string, len(columns))
+		for i, col := range columns {
+			names[i] = col.name
+		}
+		out = newExportWriter(w, format, "Subscriptions", names)
+	}
+
+	values := make([]any, len(columns))
+	var rows int
+	err := h.service.Export(r.Context(), filter, func(sub *models.Subscription) error {
+		if out == nil {
+			start()
+		}
+		for i, col := range columns {
+			values[i] = col.value(sub)
+		}
+		rows++
+		return out.WriteRow(values)
+	})
+	if err != nil {
+		if out == nil {
+			h.log.Error("не удалось выгрузить подписки", "error", err)
+			h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+			return
+		}
+		// Заголовки уже отправлены: обрываем соединение, чтобы клиент
+		// не принял неполный файл за успешную выгрузку.
+		h.log.Error("выгрузка подписок прервана", "rows", rows, "error", err)
+		panic(http.ErrAbortHandler)
+	}
+
+	if out == nil {
+		start()
+	}
+	if err := out.Close(); err != nil {
+		h.log.Error("не удалось завершить выгрузку", "rows", rows, "error", err)
+		panic(http.ErrAbortHandler)
+	}
+
+	h.log.Info("выгрузка подписок завершена", "format", format, "rows", rows)
+}
+
+// selectExportColumns возвращает колонки из списка через запятую или все
+// колонки, если список пуст. Вторым значением возвращается неизвестная колонка.
+func selectExportColumns(list string) ([]exportColumn, string) {
+	if strings.TrimSpace(list) == "" {
+		return exportColumns, ""
+	}
+
+	var selected []exportColumn
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		found := false
+		for _, col := range exportColumns {
+			if col.name == name {
+				selected = append(selected, col)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, name
+		}
+	}
+
+	if len(selected) == 0 {
+		return exportColumns, ""
+	}
+	return selected, ""
+}
+
+// exportWriter пишет строки выгрузки в выбранном формате.
+type exportWriter interface {
+	WriteRow(values []any) error
+	Close() error
+}
+
+// newExportWriter создаёт файл с колонками names. sheet — название листа
+// XLSX.
+func newExportWriter(w io.Writer, format, sheet string, names []string) exportWriter {
+	switch format {
+	case exportFormatNDJSON:
+		return newNDJSONExportWriter(w, names)
+	case exportFormatXLSX:
+		return xlsx.NewWriter(w, sheet, names)
+	default:
+		cw := csv.NewWriter(w)
+		cw.Write(names)
+		return &csvExportWriter{w: cw, record: make([]string, len(names))}
+	}
+}
+
+type csvExportWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+func (c *csvExportWriter) WriteRow(values []any) error {
+	for i, v := range values {
+		switch v := v.(type) {
+		case nil:
+			c.record[i] = ""
+		case string:
+			c.record[i] = v
+		case int:
+			c.record[i] = strconv.Itoa(v)
+		default:
+			c.record[i] = fmt.Sprint(v)
+		}
+	}
+	return c.w.Write(c.record)
+}
+
+func (c *csvExportWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// ndjsonExportWriter пишет объект каждой строки сам, а не через map, чтобы
+// ключи шли в порядке колонок из параметра columns.
+type ndjsonExportWriter struct {
+	bw   *bufio.Writer
+	keys [][]byte
+	buf  []byte
+}
+
+func newNDJSONExportWriter(w io.Writer, names []string) *ndjsonExportWriter {
+	keys := make([][]byte, len(names))
+	for i, name := range names {
+		key, _ := json.Marshal(name)
+		keys[i] = append(key, ':')
+	}
+	return &ndjsonExportWriter{bw: bufio.NewWriter(w), keys: keys}
+}
+
+func (n *ndjsonExportWriter) WriteRow(values []any) error {
+	buf := append(n.buf[:0], '{')
+	for i, v := range values {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, n.keys[i]...)
+
+		switch v := v.(type) {
+		case nil:
+			buf = append(buf, "null"...)
+		case int:
+			buf = strconv.AppendInt(buf, int64(v), 10)
+		default:
+			value, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			buf = append(buf, value...)
+		}
+	}
+	buf = append(buf, '}', '\n')
+	n.buf = buf
+
+	_, err := n.bw.Write(buf)
+	return err
+}
+
+func (n *ndjsonExportWriter) Close() error {
+	return n.bw.Flush()
+}
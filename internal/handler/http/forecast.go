@@ -0,0 +1,48 @@
+package http
+
+import (
+	"net/http"
+
+	"effective-mobile-task/internal/i18n"
+)
+
+// GetForecast обрабатывает запрос на прогноз расходов на подписки.
+// @Summary Forecast future spending
+// @Description Projects the monthly spending of every user for the given number of months starting with the next month. Each month is billed like in /subscriptions/summary: subscriptions stop at their end month, scheduled price, seat and discount changes apply from their month, trial months are free and paused subscriptions are not billed. Accepts the same filters as /subscriptions/summary except start_date and end_date.
+// @Tags subscriptions
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   months        query     int     false  "Number of months to forecast (default 12, max 60)"
+// @Param   user_id       query     string  false  "Filter by User ID (UUID format)"
+// @Param   service_name  query     string  false  "Filter by Service Name"
+// @Param   service_name_like  query  string  false  "Filter by service name prefix or similar spelling"
+// @Param   category      query     string  false  "Filter by service catalogue category"
+// @Param   tags          query     string  false  "Filter by comma-separated tags"
+// @Param   tags_match    query     string  false  "Whether a subscription must have any (default) or all of the tags"  Enums(any, all)
+// @Param   as_of         query     string  false  "Read subscription versions that were current at this moment (RFC 3339)"
+// @Success 200           {object}  models.Forecast
+// @Failure 400           {string}  string "Неверный срок или формат фильтра"
+// @Failure 500           {string}  string "Внутренняя ошибка сервера"
+// @Router /subscriptions/forecast [get]
+func (h *Handler) GetForecast(w http.ResponseWriter, r *http.Request) {
+	months, ok := queryInt(r, "months", 1)
+	if !ok {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidMonths)
+		return
+	}
+
+	filter, key, ok := parseSummaryFilter(r)
+	if !ok {
+		h.respondError(w, r, http.StatusBadRequest, key)
+		return
+	}
+
+	forecast, err := h.service.Forecast(r.Context(), filter, months)
+	if err != nil {
+		h.log.Error("не удалось спрогнозировать расходы", "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, forecast)
+}
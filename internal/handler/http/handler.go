@@ -4,15 +4,22 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"effective-mobile-task/internal/auth"
+	"effective-mobile-task/internal/i18n"
 	"effective-mobile-task/internal/models"
+	"effective-mobile-task/internal/ratelimit"
 	"effective-mobile-task/internal/repository/postgres"
 	"effective-mobile-task/internal/service"
 	"github.com/go-chi/chi/v5"
-	"github.com/go-playground/validator/v10" 
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 )
 
@@ -20,25 +27,173 @@ import (
 type SubscriptionService interface {
 	Create(ctx context.Context, dto service.CreateSubscriptionDTO) (*models.Subscription, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Subscription, error)
+	GetByIDAsOf(ctx context.Context, id uuid.UUID, asOf time.Time) (*models.Subscription, error)
 	Update(ctx context.Context, id uuid.UUID, dto service.UpdateSubscriptionDTO) error
 	Delete(ctx context.Context, id uuid.UUID) error
-	GetSummary(ctx context.Context, filter postgres.GetSummaryFilter) (int, error)
+	GetSummary(ctx context.Context, filter postgres.GetSummaryFilter) (*models.SummaryTotals, error)
+	GetSummaryByCategory(ctx context.Context, filter postgres.GetSummaryFilter) ([]models.CategorySummary, error)
+	Forecast(ctx context.Context, filter postgres.GetSummaryFilter, months int) (*models.Forecast, error)
+	Search(ctx context.Context, text string, filter postgres.GetSummaryFilter, limit int) ([]models.SubscriptionMatch, error)
+	List(ctx context.Context, filter postgres.GetSummaryFilter, limit, offset int) ([]models.Subscription, error)
+	AddTags(ctx context.Context, id uuid.UUID, dto service.TagsDTO) ([]string, error)
+	RemoveTag(ctx context.Context, id uuid.UUID, tag string) ([]string, error)
+	ChangeStatus(ctx context.Context, id uuid.UUID, action service.LifecycleAction, dto service.StatusChangeDTO) (*models.Subscription, error)
+	StatusHistory(ctx context.Context, id uuid.UUID) ([]models.StatusChange, error)
+	ConvertingTrials(ctx context.Context, days int, userID *uuid.UUID) ([]models.TrialConversion, error)
+	SchedulePriceChange(ctx context.Context, id uuid.UUID, dto service.PriceChangeDTO) ([]models.PricePeriod, error)
+	ChangeSeats(ctx context.Context, id uuid.UUID, dto service.SeatChangeDTO) ([]models.PricePeriod, error)
+	PriceHistory(ctx context.Context, id uuid.UUID) ([]models.PricePeriod, error)
+	AddDiscount(ctx context.Context, id uuid.UUID, dto service.DiscountDTO) (*models.Discount, error)
+	ListDiscounts(ctx context.Context, id uuid.UUID) ([]models.Discount, error)
+	DeleteDiscount(ctx context.Context, id, discountID uuid.UUID) error
+	SetMember(ctx context.Context, id, userID uuid.UUID, dto service.MemberDTO) (*models.SubscriptionMember, error)
+	ListMembers(ctx context.Context, id uuid.UUID) ([]models.SubscriptionMember, error)
+	RemoveMember(ctx context.Context, id, userID uuid.UUID) error
+	Settlements(ctx context.Context, month *models.Month, userID *uuid.UUID) ([]models.Settlement, error)
+	SetCostCentre(ctx context.Context, id uuid.UUID, dto service.CostCentreAssignmentDTO) (*models.Subscription, error)
+	Batch(ctx context.Context, dto service.BatchRequestDTO) (*service.BatchResult, error)
+	Import(ctx context.Context, src service.RowSource, dryRun bool) (*service.ImportReport, error)
+	Export(ctx context.Context, filter postgres.GetSummaryFilter, fn func(*models.Subscription) error) error
+}
+
+
+type UserDataService interface {
+	CheckUserData(ctx context.Context, userID uuid.UUID) error
+	ExportUserData(ctx context.Context, userID uuid.UUID, w io.Writer) error
+	EraseUserData(ctx context.Context, userID uuid.UUID) (*models.ErasureReceipt, error)
+}
+
+
+type CatalogService interface {
+	Create(ctx context.Context, dto service.ServiceDTO) (*models.Service, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Service, error)
+	List(ctx context.Context, category *string) ([]models.Service, error)
+	Update(ctx context.Context, id uuid.UUID, dto service.ServiceDTO) (*models.Service, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+
+type BudgetService interface {
+	Create(ctx context.Context, userID uuid.UUID, dto service.BudgetDTO) (*models.Budget, error)
+	List(ctx context.Context, userID uuid.UUID) ([]models.Budget, error)
+	Update(ctx context.Context, userID, id uuid.UUID, dto service.BudgetDTO) (*models.Budget, error)
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+	Status(ctx context.Context, userID uuid.UUID) ([]models.BudgetStatus, error)
+}
+
+
+type OrganisationService interface {
+	Create(ctx context.Context, dto service.OrganisationDTO) (*models.Organisation, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Organisation, error)
+	List(ctx context.Context) ([]models.Organisation, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	CreateCostCentre(ctx context.Context, orgID uuid.UUID, dto service.CostCentreDTO) (*models.CostCentre, error)
+	ListCostCentres(ctx context.Context, orgID uuid.UUID) ([]models.CostCentre, error)
+	DeleteCostCentre(ctx context.Context, orgID, id uuid.UUID) error
+	AddUser(ctx context.Context, orgID, userID uuid.UUID) (*models.OrganisationUser, error)
+	ListUsers(ctx context.Context, orgID uuid.UUID) ([]models.OrganisationUser, error)
+	RemoveUser(ctx context.Context, orgID, userID uuid.UUID) error
+	Chargeback(ctx context.Context, orgID uuid.UUID, filter postgres.GetSummaryFilter) ([]models.ChargebackRow, error)
+}
+
+
+// Services — сервисы, к которым обращаются обработчики.
+type Services struct {
+	Subscriptions SubscriptionService
+	UserData      UserDataService
+	Catalog       CatalogService
+	Budgets       BudgetService
+	Organisations OrganisationService
+	// Tenants привязывает запросы к арендаторам. Без него запросы
+	// выполняются без арендатора.
+	Tenants TenantBinder
+}
+
+
+// Option настраивает Handler.
+type Option func(*Handler)
+
+// WithTenantRequired запрещает запросы без арендатора. Иначе они относятся
+// к арендатору по умолчанию.
+func WithTenantRequired(required bool) Option {
+	return func(h *Handler) {
+		h.tenantRequired = required
+	}
+}
+
+// WithTenantHeader разрешает указывать арендатора заголовком X-Tenant-ID.
+func WithTenantHeader(trust bool) Option {
+	return func(h *Handler) {
+		h.trustTenantHeader = trust
+	}
+}
+
+// WithUserHeaders разрешает указывать пользователя и роль заголовками
+// X-User-ID и X-User-Role.
+func WithUserHeaders(trust bool) Option {
+	return func(h *Handler) {
+		h.trustUserHeaders = trust
+	}
+}
+
+// WithDefaultRole задаёт роль запросов без токена доступа. Пустая роль
+// запрещает такие запросы.
+func WithDefaultRole(role auth.Role) Option {
+	return func(h *Handler) {
+		h.defaultRole = role
+	}
+}
+
+// WithRateLimiter включает ограничение частоты запросов.
+func WithRateLimiter(limiter *ratelimit.Limiter) Option {
+	return func(h *Handler) {
+		h.limiter = limiter
+	}
 }
 
 
 type Handler struct {
-	service  SubscriptionService
-	log      *slog.Logger
-	validate *validator.Validate 
+	service       SubscriptionService
+	userData      UserDataService
+	catalog       CatalogService
+	budgets       BudgetService
+	organisations OrganisationService
+	tenants       TenantBinder
+	limiter       *ratelimit.Limiter
+	log           *slog.Logger
+	validate      *validator.Validate
+	translator    *ut.UniversalTranslator
+
+	tenantRequired    bool
+	trustTenantHeader bool
+	trustUserHeaders  bool
+	defaultRole       auth.Role
 }
 
 
-func NewHandler(service SubscriptionService, log *slog.Logger) *Handler {
-	return &Handler{
-		service:  service,
-		log:      log,
-		validate: validator.New(), 
+func NewHandler(services Services, log *slog.Logger, opts ...Option) (*Handler, error) {
+	validate, translator, err := newValidator()
+	if err != nil {
+		return nil, err
+	}
+
+	h := &Handler{
+		service:       services.Subscriptions,
+		userData:      services.UserData,
+		catalog:       services.Catalog,
+		budgets:       services.Budgets,
+		organisations: services.Organisations,
+		tenants:       services.Tenants,
+		log:           log,
+		validate:      validate,
+		translator:    translator,
+		defaultRole:   auth.RoleViewer,
+	}
+	for _, opt := range opts {
+		opt(h)
 	}
+
+	return h, nil
 }
 
 // CreateSubscription обрабатывает запрос на создание новой подписки.
@@ -47,16 +202,19 @@ func NewHandler(service SubscriptionService, log *slog.Logger) *Handler {
 // @Tags subscriptions
 // @Accept  json
 // @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
 // @Param   subscription  body      service.CreateSubscriptionDTO  true  "Subscription Info"
 // @Success 201           {object}  models.Subscription
 // @Failure 400           {string}  string "Неверный формат JSON или неверные данные"
+// @Failure 403           {object}  problem "Недостаточно прав"
+// @Failure 409           {string}  string "Подписка пересекается с уже существующей"
 // @Failure 500           {string}  string "Внутренняя ошибка сервера"
 // @Router /subscriptions [post]
 func (h *Handler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
 	var dto service.CreateSubscriptionDTO
 	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
 		h.log.Warn("не удалось декодировать тело запроса", "error", err)
-		http.Error(w, "Неверный формат JSON", http.StatusBadRequest)
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidJSON)
 		return
 	}
 
@@ -64,15 +222,18 @@ func (h *Handler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
 	if err := h.validate.Struct(dto); err != nil {
 		h.log.Warn("неверные данные", "error", err)
 
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		h.respondValidationError(w, r, err)
 		return
 	}
 
 
 	sub, err := h.service.Create(r.Context(), dto)
 	if err != nil {
+		if h.respondDomainError(w, r, err) {
+			return
+		}
 		h.log.Error("не удалось создать подписку", "error", err)
-		http.Error(w, "Внутренняя ошибка сервера", http.StatusInternalServerError)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
 		return
 	}
 
@@ -84,9 +245,11 @@ func (h *Handler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
 // @Description Get details of a specific subscription
 // @Tags subscriptions
 // @Produce  json
-// @Param   id   path      string  true  "Subscription ID"
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   id     path      string  true   "Subscription ID"
+// @Param   as_of  query     string  false  "Return the version of the subscription that was current at this moment (RFC 3339)"
 // @Success 200  {object}  models.Subscription
-// @Failure 400  {string}  string "Неверный формат ID"
+// @Failure 400  {string}  string "Неверный формат ID или as_of"
 // @Failure 404  {string}  string "Подписка не найдена"
 // @Failure 500  {string}  string "Внутренняя ошибка сервера"
 // @Router /subscriptions/{id} [get]
@@ -94,18 +257,29 @@ func (h *Handler) GetSubscriptionByID(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		http.Error(w, "Неверный формат ID", http.StatusBadRequest)
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
 		return
 	}
 
-	sub, err := h.service.GetByID(r.Context(), id)
+	asOf, ok := parseAsOf(r)
+	if !ok {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidAsOf)
+		return
+	}
+
+	var sub *models.Subscription
+	if asOf != nil {
+		sub, err = h.service.GetByIDAsOf(r.Context(), id, *asOf)
+	} else {
+		sub, err = h.service.GetByID(r.Context(), id)
+	}
 	if err != nil {
 		if errors.Is(err, postgres.ErrNotFound) {
-			http.Error(w, "Подписка не найдена", http.StatusNotFound)
+			h.respondError(w, r, http.StatusNotFound, i18n.NotFound)
 			return
 		}
 		h.log.Error("не удалось получить подписку", "id", id, "error", err)
-		http.Error(w, "Внутренняя ошибка сервера", http.StatusInternalServerError)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
 		return
 	}
 
@@ -114,35 +288,38 @@ func (h *Handler) GetSubscriptionByID(w http.ResponseWriter, r *http.Request) {
 
 // UpdateSubscription обрабатывает запрос на обновление подписки.
 // @Summary Update an existing subscription
-// @Description Update details of an existing subscription by its ID
+// @Description Update details of an existing subscription by its ID. A new price applies from the current month; past months keep the previous price. Use /subscriptions/{id}/prices to schedule a change from a future month.
 // @Tags subscriptions
 // @Accept  json
 // @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
 // @Param   id            path      string                       true  "Subscription ID"
 // @Param   subscription  body      service.UpdateSubscriptionDTO  true  "Subscription data to update"
 // @Success 200           {string}  string "OK"
 // @Failure 400           {string}  string "Неверный формат JSON или неверные данные"
+// @Failure 403           {object}  problem "Недостаточно прав"
 // @Failure 404           {string}  string "Подписка не найдена"
+// @Failure 409           {string}  string "Подписка пересекается с уже существующей"
 // @Failure 500           {string}  string "Внутренняя ошибка сервера"
 // @Router /subscriptions/{id} [put]
 func (h *Handler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		http.Error(w, "Неверный формат ID", http.StatusBadRequest)
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
 		return
 	}
 
 	var dto service.UpdateSubscriptionDTO
 	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
-		http.Error(w, "Неверный формат JSON", http.StatusBadRequest)
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidJSON)
 		return
 	}
 
 
 	if err := h.validate.Struct(dto); err != nil {
 		h.log.Warn("неверные данные", "error", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		h.respondValidationError(w, r, err)
 		return
 	}
 
@@ -150,11 +327,14 @@ func (h *Handler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
 	err = h.service.Update(r.Context(), id, dto)
 	if err != nil {
 		if errors.Is(err, postgres.ErrNotFound) {
-			http.Error(w, "Подписка не найдена", http.StatusNotFound)
+			h.respondError(w, r, http.StatusNotFound, i18n.NotFound)
+			return
+		}
+		if h.respondDomainError(w, r, err) {
 			return
 		}
 		h.log.Error("не удалось обновить подписку", "id", id, "error", err)
-		http.Error(w, "Внутренняя ошибка сервера", http.StatusInternalServerError)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
 		return
 	}
 
@@ -166,9 +346,11 @@ func (h *Handler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
 // @Description Delete a subscription by its ID
 // @Tags subscriptions
 // @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
 // @Param   id   path      string  true  "Subscription ID"
 // @Success 204  {string}  string "No Content"
 // @Failure 400  {string}  string "Invalid ID format"
+// @Failure 403  {object}  problem "Недостаточно прав"
 // @Failure 404  {string}  string "Subscription not found"
 // @Failure 500  {string}  string "Internal server error"
 // @Router /subscriptions/{id} [delete]
@@ -176,18 +358,21 @@ func (h *Handler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := uuid.Parse(idStr)
 	if err != nil {
-		http.Error(w, "Неверный формат ID", http.StatusBadRequest)
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
 		return
 	}
 
 	err = h.service.Delete(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, postgres.ErrNotFound) {
-			http.Error(w, "Подписка не найдена", http.StatusNotFound)
+			h.respondError(w, r, http.StatusNotFound, i18n.NotFound)
+			return
+		}
+		if h.respondDomainError(w, r, err) {
 			return
 		}
 		h.log.Error("не удалось удалить подписку", "id", id, "error", err)
-		http.Error(w, "Внутренняя ошибка сервера", http.StatusInternalServerError)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
 		return
 	}
 
@@ -196,27 +381,255 @@ func (h *Handler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
 
 // GetSummary обрабатывает запрос на получение суммарной стоимости.
 // @Summary Get summary price of subscriptions
-// @Description Calculates the total price of subscriptions based on optional filters
+// @Description Calculates the total cost of subscriptions as price times billed months within the period (start_date..end_date, end defaults to the current month). Free trial and paused months are not billed. Discounts active in a month reduce its price: percentages first, then fixed amounts, never below zero. With breakdown=true the response also reports the cost before discounts and the discount amount. With user_id, shared subscriptions count only the share of the user.
 // @Tags subscriptions
 // @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
 // @Param   user_id       query     string  false  "Filter by User ID (UUID format)"
 // @Param   service_name  query     string  false  "Filter by Service Name"
-// @Param   start_date    query     string  false  "Filter by start date (YYYY-MM-DD)"
-// @Param   end_date      query     string  false  "Filter by end date (YYYY-MM-DD)"
-// @Success 200           {object}  map[string]int
+// @Param   service_name_like  query  string  false  "Filter by service name prefix or similar spelling"
+// @Param   category      query     string  false  "Filter by service catalogue category"
+// @Param   tags          query     string  false  "Filter by comma-separated tags"
+// @Param   tags_match    query     string  false  "Whether a subscription must have any (default) or all of the tags"  Enums(any, all)
+// @Param   start_date    query     string  false  "Filter by start month (MM-YYYY, YYYY-MM or YYYY-MM-DD)"
+// @Param   end_date      query     string  false  "Filter by end month (MM-YYYY, YYYY-MM or YYYY-MM-DD)"
+// @Param   as_of         query     string  false  "Read subscription versions that were current at this moment (RFC 3339)"
+// @Param   breakdown     query     bool    false  "Report gross_price and discount along with total_price"
+// @Success 200           {object}  models.SummaryTotals
 // @Failure 400           {string}  string "Invalid filter format"
+// @Failure 403           {object}  problem "Недостаточно прав"
 // @Failure 500           {string}  string "Internal server error"
 // @Router /subscriptions/summary [get]
 func (h *Handler) GetSummary(w http.ResponseWriter, r *http.Request) {
+	filter, key, ok := parseSummaryFilter(r)
+	if !ok {
+		h.respondError(w, r, http.StatusBadRequest, key)
+		return
+	}
+
+	breakdown := false
+	if raw := r.URL.Query().Get("breakdown"); raw != "" {
+		var err error
+		if breakdown, err = strconv.ParseBool(raw); err != nil {
+			h.respondError(w, r, http.StatusBadRequest, i18n.InvalidBreakdown)
+			return
+		}
+	}
+
+	totals, err := h.service.GetSummary(r.Context(), filter)
+	if err != nil {
+		if h.respondDomainError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось получить сводку", "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	if breakdown {
+		respondWithJSON(w, http.StatusOK, totals)
+		return
+	}
+	respondWithJSON(w, http.StatusOK, map[string]int{"total_price": totals.Net})
+}
+
+// GetSummaryByCategory обрабатывает запрос на получение стоимости подписок по категориям.
+// @Summary Get summary price of subscriptions by category
+// @Description Calculates the total price of subscriptions grouped by service catalogue category. Subscriptions without a catalogue service or category are reported with a null category.
+// @Tags subscriptions
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   user_id       query     string  false  "Filter by User ID (UUID format)"
+// @Param   service_name  query     string  false  "Filter by Service Name"
+// @Param   service_name_like  query  string  false  "Filter by service name prefix or similar spelling"
+// @Param   category      query     string  false  "Filter by service catalogue category"
+// @Param   tags          query     string  false  "Filter by comma-separated tags"
+// @Param   tags_match    query     string  false  "Whether a subscription must have any (default) or all of the tags"  Enums(any, all)
+// @Param   start_date    query     string  false  "Filter by start month (MM-YYYY, YYYY-MM or YYYY-MM-DD)"
+// @Param   end_date      query     string  false  "Filter by end month (MM-YYYY, YYYY-MM or YYYY-MM-DD)"
+// @Param   as_of         query     string  false  "Read subscription versions that were current at this moment (RFC 3339)"
+// @Success 200           {array}   models.CategorySummary
+// @Failure 400           {string}  string "Неверный формат фильтра"
+// @Failure 500           {string}  string "Внутренняя ошибка сервера"
+// @Router /subscriptions/summary/categories [get]
+func (h *Handler) GetSummaryByCategory(w http.ResponseWriter, r *http.Request) {
+	filter, key, ok := parseSummaryFilter(r)
+	if !ok {
+		h.respondError(w, r, http.StatusBadRequest, key)
+		return
+	}
+
+	summaries, err := h.service.GetSummaryByCategory(r.Context(), filter)
+	if err != nil {
+		h.log.Error("не удалось получить сводку по категориям", "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, summaries)
+}
+
+
+// SearchSubscriptions обрабатывает запрос на нечёткий поиск подписок по названию сервиса.
+// @Summary Fuzzy search of subscriptions by service name
+// @Description Finds subscriptions whose service name starts with the query or is similar to it (pg_trgm), case-insensitive. Prefix matches come first, then the rest by descending similarity. Accepts the same filters as /subscriptions/summary.
+// @Tags subscriptions
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   q             query     string  true   "Search text"
+// @Param   limit         query     int     false  "Maximum number of results (default 20, max 100)"
+// @Param   user_id       query     string  false  "Filter by User ID (UUID format)"
+// @Param   category      query     string  false  "Filter by service catalogue category"
+// @Param   tags          query     string  false  "Filter by comma-separated tags"
+// @Param   tags_match    query     string  false  "Whether a subscription must have any (default) or all of the tags"  Enums(any, all)
+// @Param   start_date    query     string  false  "Filter by start month (MM-YYYY, YYYY-MM or YYYY-MM-DD)"
+// @Param   end_date      query     string  false  "Filter by end month (MM-YYYY, YYYY-MM or YYYY-MM-DD)"
+// @Param   as_of         query     string  false  "Read subscription versions that were current at this moment (RFC 3339)"
+// @Success 200           {array}   models.SubscriptionMatch
+// @Failure 400           {string}  string "Не указана строка поиска или неверный фильтр"
+// @Failure 500           {string}  string "Внутренняя ошибка сервера"
+// @Router /subscriptions/search [get]
+func (h *Handler) SearchSubscriptions(w http.ResponseWriter, r *http.Request) {
+	text := strings.TrimSpace(r.URL.Query().Get("q"))
+	if text == "" {
+		h.respondError(w, r, http.StatusBadRequest, i18n.SearchQueryRequired)
+		return
+	}
+
+	limit, ok := queryInt(r, "limit", 1)
+	if !ok {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidLimit)
+		return
+	}
+
+	filter, key, ok := parseSummaryFilter(r)
+	if !ok {
+		h.respondError(w, r, http.StatusBadRequest, key)
+		return
+	}
+
+	matches, err := h.service.Search(r.Context(), text, filter, limit)
+	if err != nil {
+		if h.respondDomainError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось выполнить поиск подписок", "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, matches)
+}
+
+
+// ListSubscriptions обрабатывает запрос на получение списка подписок.
+// @Summary List subscriptions
+// @Description Returns a page of subscriptions matching the same filters as /subscriptions/summary, ordered by user, start month and ID.
+// @Tags subscriptions
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   limit         query     int     false  "Page size (default 100, max 1000)"
+// @Param   offset        query     int     false  "Number of subscriptions to skip"
+// @Param   user_id       query     string  false  "Filter by User ID (UUID format)"
+// @Param   service_name  query     string  false  "Filter by Service Name"
+// @Param   service_name_like  query  string  false  "Filter by service name prefix or similar spelling"
+// @Param   category      query     string  false  "Filter by service catalogue category"
+// @Param   tags          query     string  false  "Filter by comma-separated tags"
+// @Param   tags_match    query     string  false  "Whether a subscription must have any (default) or all of the tags"  Enums(any, all)
+// @Param   start_date    query     string  false  "Filter by start month (MM-YYYY, YYYY-MM or YYYY-MM-DD)"
+// @Param   end_date      query     string  false  "Filter by end month (MM-YYYY, YYYY-MM or YYYY-MM-DD)"
+// @Param   as_of         query     string  false  "Read subscription versions that were current at this moment (RFC 3339)"
+// @Success 200           {array}   models.Subscription
+// @Failure 400           {string}  string "Неверный фильтр или параметры страницы"
+// @Failure 500           {string}  string "Внутренняя ошибка сервера"
+// @Router /subscriptions [get]
+func (h *Handler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	limit, ok := queryInt(r, "limit", 1)
+	if !ok {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidLimit)
+		return
+	}
+
+	offset, ok := queryInt(r, "offset", 0)
+	if !ok {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidOffset)
+		return
+	}
+
+	filter, key, ok := parseSummaryFilter(r)
+	if !ok {
+		h.respondError(w, r, http.StatusBadRequest, key)
+		return
+	}
+
+	subs, err := h.service.List(r.Context(), filter, limit, offset)
+	if err != nil {
+		h.log.Error("не удалось получить список подписок", "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, subs)
+}
+
+// queryInt читает из строки запроса целое число не меньше min. Отсутствующий
+// параметр даёт 0.
+func queryInt(r *http.Request, name string, min int) (int, bool) {
+	str := r.URL.Query().Get(name)
+	if str == "" {
+		return 0, true
+	}
+
+	n, err := strconv.Atoi(str)
+	if err != nil || n < min {
+		return 0, false
+	}
+	return n, true
+}
+
+
+// respondError отправляет клиенту сообщение об ошибке на его языке.
+func (h *Handler) respondError(w http.ResponseWriter, r *http.Request, code int, key i18n.Key) {
+	http.Error(w, i18n.Translate(i18n.FromContext(r.Context()), key), code)
+}
+
+// problem — описание ошибки в формате RFC 9457 (application/problem+json).
+type problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail"`
+	Instance string `json:"instance"`
+}
+
+// respondProblem отправляет клиенту ошибку в формате application/problem+json.
+func (h *Handler) respondProblem(w http.ResponseWriter, r *http.Request, code int, key i18n.Key) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(code),
+		Status:   code,
+		Detail:   i18n.Translate(i18n.FromContext(r.Context()), key),
+		Instance: r.URL.Path,
+	})
+}
+
+// respondValidationError отправляет клиенту переведённые ошибки валидации полей.
+func (h *Handler) respondValidationError(w http.ResponseWriter, r *http.Request, err error) {
+	http.Error(w, h.translateValidationError(i18n.FromContext(r.Context()), err), http.StatusBadRequest)
+}
+
+// parseSummaryFilter разбирает параметры фильтра сводки из строки запроса.
+// При ошибке возвращает ключ сообщения для клиента.
+func parseSummaryFilter(r *http.Request) (postgres.GetSummaryFilter, i18n.Key, bool) {
 	var filter postgres.GetSummaryFilter
 	q := r.URL.Query()
-	const layout = "2006-01-02" // Формат для парсинга YYYY-MM-DD
 
 	if userIDStr := q.Get("user_id"); userIDStr != "" {
 		userID, err := uuid.Parse(userIDStr)
 		if err != nil {
-			http.Error(w, "Неверный формат user_id", http.StatusBadRequest)
-			return
+			return filter, i18n.InvalidUserID, false
 		}
 		filter.UserID = &userID
 	}
@@ -225,34 +638,212 @@ func (h *Handler) GetSummary(w http.ResponseWriter, r *http.Request) {
 		filter.ServiceName = &serviceName
 	}
 
+	if serviceNameLike := strings.TrimSpace(q.Get("service_name_like")); serviceNameLike != "" {
+		filter.ServiceNameLike = &serviceNameLike
+	}
+
+	if category := q.Get("category"); category != "" {
+		filter.Category = &category
+	}
+
+	if tagsStr := q.Get("tags"); tagsStr != "" {
+		tags, err := service.NormalizeTags(strings.Split(tagsStr, ","))
+		if err != nil {
+			return filter, i18n.TagLength, false
+		}
+		filter.Tags = tags
+	}
+
+	switch match := postgres.TagsMatch(q.Get("tags_match")); match {
+	case "", postgres.TagsMatchAny, postgres.TagsMatchAll:
+		filter.TagsMatch = match
+	default:
+		return filter, i18n.InvalidTagsMatch, false
+	}
+
 	if startDateStr := q.Get("start_date"); startDateStr != "" {
-		startDate, err := time.Parse(layout, startDateStr)
+		startDate, err := models.ParseMonth(startDateStr)
 		if err != nil {
-			http.Error(w, "Неверный формат start_date, используйте YYYY-MM-DD", http.StatusBadRequest)
-			return
+			return filter, i18n.InvalidStartDate, false
 		}
 		filter.StartDate = &startDate
 	}
 
 	if endDateStr := q.Get("end_date"); endDateStr != "" {
-		endDate, err := time.Parse(layout, endDateStr)
+		endDate, err := models.ParseMonth(endDateStr)
 		if err != nil {
-			http.Error(w, "Неверный формат end_date, используйте YYYY-MM-DD", http.StatusBadRequest)
-			return
+			return filter, i18n.InvalidEndDate, false
 		}
 		filter.EndDate = &endDate
 	}
 
-	total, err := h.service.GetSummary(r.Context(), filter)
+	asOf, ok := parseAsOf(r)
+	if !ok {
+		return filter, i18n.InvalidAsOf, false
+	}
+	filter.AsOf = asOf
+
+	return filter, "", true
+}
+
+// parseAsOf разбирает момент, на который нужно читать данные, из параметра
+// as_of. Отсутствующий параметр даёт nil.
+func parseAsOf(r *http.Request) (*time.Time, bool) {
+	str := r.URL.Query().Get("as_of")
+	if str == "" {
+		return nil, true
+	}
+
+	asOf, err := time.Parse(time.RFC3339Nano, str)
 	if err != nil {
-		h.log.Error("не удалось получить сводку", "error", err)
-		http.Error(w, "Внутренняя ошибка сервера", http.StatusInternalServerError)
+		return nil, false
+	}
+	return &asOf, true
+}
+
+// BatchSubscriptions обрабатывает пакетный запрос на создание, обновление и удаление подписок.
+// @Summary Create, update and delete subscriptions in bulk
+// @Description Executes an array of operations in a single transaction. In "atomic" mode (default) any failed operation rolls back the whole batch, in "best_effort" mode failed operations are skipped.
+// @Tags subscriptions
+// @Accept  json
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   batch  body      service.BatchRequestDTO  true  "Batch operations"
+// @Success 200    {object}  service.BatchResult "Все операции выполнены"
+// @Success 207    {object}  service.BatchResult "Часть операций не выполнена (best_effort)"
+// @Failure 400    {string}  string "Неверный формат JSON или неверные данные"
+// @Failure 422    {object}  service.BatchResult "Пакет отменён (atomic)"
+// @Failure 500    {string}  string "Внутренняя ошибка сервера"
+// @Router /subscriptions/batch [post]
+func (h *Handler) BatchSubscriptions(w http.ResponseWriter, r *http.Request) {
+	var dto service.BatchRequestDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		h.log.Warn("не удалось декодировать тело запроса", "error", err)
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidJSON)
+		return
+	}
+
+	if err := h.validate.Struct(dto); err != nil {
+		h.log.Warn("неверные данные", "error", err)
+		h.respondValidationError(w, r, err)
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, map[string]int{"total_price": total})
+	result, err := h.service.Batch(r.Context(), dto)
+	if err != nil {
+		if h.respondDomainError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось выполнить пакет операций", "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	loc := i18n.FromContext(r.Context())
+	for i := range result.Items {
+		item := &result.Items[i]
+		if item.Err != nil {
+			item.Error = h.itemErrorMessage(loc, item.Err)
+		}
+	}
+
+	code := http.StatusOK
+	switch {
+	case result.Failed > 0 && result.Mode == service.BatchModeAtomic:
+		code = http.StatusUnprocessableEntity
+	case result.Failed > 0:
+		code = http.StatusMultiStatus
+	}
+
+	respondWithJSON(w, code, result)
+}
+
+// itemErrorMessage переводит ошибку отдельной операции пакета на язык клиента.
+func (h *Handler) itemErrorMessage(loc i18n.Locale, err error) string {
+	switch {
+	case errors.Is(err, postgres.ErrNotFound):
+		return i18n.Translate(loc, i18n.NotFound)
+	case errors.Is(err, postgres.ErrBatchRolledBack):
+		return i18n.Translate(loc, i18n.BatchRolledBack)
+	}
+
+	if _, key, ok := domainErrorMessage(err); ok {
+		return i18n.Translate(loc, key)
+	}
+
+	h.log.Error("ошибка операции в пакете", "error", err)
+	return i18n.Translate(loc, i18n.Internal)
+}
+
+// ruleMessages сопоставляет нарушенные бизнес-правила с сообщениями для клиента.
+var ruleMessages = map[string]i18n.Key{
+	service.RuleRequired:              i18n.InvalidData,
+	service.RuleEndBeforeStart:        i18n.EndBeforeStart,
+	service.RuleServiceNameLength:     i18n.ServiceNameLength,
+	service.RuleServiceNameSymbols:    i18n.ServiceNameSymbols,
+	service.RuleInvalidFormat:         i18n.InvalidFormat,
+	service.RulePricePositive:         i18n.PricePositive,
+	service.RulePriceFraction:         i18n.PriceFraction,
+	service.RuleTagLength:             i18n.TagLength,
+	service.RuleTrialOutsidePeriod:    i18n.TrialOutsidePeriod,
+	service.RuleChangeInPast:          i18n.ChangeInPast,
+	service.RuleChangeOutsidePeriod:   i18n.ChangeOutsidePeriod,
+	service.RulePriceMismatch:         i18n.PriceMismatch,
+	service.RuleDiscountPercent:       i18n.DiscountPercent,
+	service.RuleDiscountTerm:          i18n.DiscountTerm,
+	service.RuleDiscountOutsidePeriod: i18n.DiscountOutsidePeriod,
+	service.RuleBudgetScope:           i18n.BudgetScope,
+	service.RuleBudgetThreshold:       i18n.BudgetThreshold,
+	service.RuleMemberShare:           i18n.MemberShare,
+	service.RuleCostCentreParent:      i18n.CostCentreParent,
+}
+
+// respondDomainError отвечает клиенту на нарушение бизнес-правил сервиса.
+// Возвращает false, если ошибка к ним не относится.
+func (h *Handler) respondDomainError(w http.ResponseWriter, r *http.Request, err error) bool {
+	if errors.Is(err, service.ErrForbidden) {
+		h.log.Warn("действие запрещено", "error", err)
+		h.respondProblem(w, r, http.StatusForbidden, i18n.Forbidden)
+		return true
+	}
+
+	code, key, ok := domainErrorMessage(err)
+	if !ok {
+		return false
+	}
+
+	h.log.Warn("нарушено бизнес-правило", "error", err)
+	h.respondError(w, r, code, key)
+	return true
 }
 
+// domainErrorMessage подбирает HTTP-код и сообщение для ошибки бизнес-правил.
+func domainErrorMessage(err error) (int, i18n.Key, bool) {
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		key, ok := ruleMessages[validationErr.Rule]
+		if !ok {
+			key = i18n.InvalidData
+		}
+		return http.StatusBadRequest, key, true
+	}
+
+	var overlapErr *service.OverlapError
+	if errors.As(err, &overlapErr) {
+		return http.StatusConflict, i18n.OverlappingSubscription, true
+	}
+
+	var transitionErr *service.TransitionError
+	if errors.As(err, &transitionErr) {
+		return http.StatusConflict, i18n.InvalidTransition, true
+	}
+
+	if errors.Is(err, service.ErrForbidden) {
+		return http.StatusForbidden, i18n.Forbidden, true
+	}
+
+	return 0, "", false
+}
 
 func respondWithJSON(w http.ResponseWriter, code int, payload interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,176 @@
+package http
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"effective-mobile-task/internal/i18n"
+	"effective-mobile-task/internal/service"
+)
+
+// importTimeout — сколько времени даётся на загрузку и обработку одного
+// файла импорта. Общие таймауты сервера рассчитаны на короткие запросы.
+const importTimeout = 10 * time.Minute
+
+const (
+	importFormatCSV    = "csv"
+	importFormatNDJSON = "ndjson"
+)
+
+// ImportSubscriptions обрабатывает загрузку подписок из CSV или NDJSON.
+// @Summary Import subscriptions from CSV or NDJSON
+// @Description Streams the request body row by row. CSV columns are matched by header (case-insensitive); use header.<field>=<column> to map differently named columns. Dates accept MM-YYYY, YYYY-MM or YYYY-MM-DD, prices accept "1 299,00". Either price or unit_price (with an optional quantity) is required. With dry_run=true the file is only validated; otherwise all rows are inserted in one transaction, which is rolled back if any row is invalid.
+// @Tags subscriptions
+// @Accept  text/csv
+// @Accept  application/x-ndjson
+// @Produce  json
+// @Param   Accept-Language       header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   format                query   string  false  "File format (defaults to the Content-Type)"  Enums(csv, ndjson)
+// @Param   delimiter             query   string  false  "CSV delimiter: a single character or \"tab\" (default \",\")"
+// @Param   dry_run               query   bool    false  "Validate only, do not insert"
+// @Param   header.user_id        query   string  false  "CSV column with user_id"
+// @Param   header.service_name   query   string  false  "CSV column with service_name"
+// @Param   header.price          query   string  false  "CSV column with price"
+// @Param   header.start_date     query   string  false  "CSV column with start_date"
+// @Param   header.end_date       query   string  false  "CSV column with end_date"
+// @Param   header.trial_end_date query   string  false  "CSV column with trial_end_date"
+// @Param   header.unit_price     query   string  false  "CSV column with unit_price"
+// @Param   header.quantity       query   string  false  "CSV column with quantity"
+// @Param   file                  body    string  true   "CSV or NDJSON file"
+// @Success 200  {object}  service.ImportReport "Файл проверен или импортирован"
+// @Failure 400  {string}  string "Неверные параметры импорта или заголовок файла"
+// @Failure 403  {object}  problem "Недостаточно прав"
+// @Failure 415  {string}  string "Неподдерживаемый формат файла"
+// @Failure 422  {object}  service.ImportReport "В файле есть ошибки, ничего не импортировано"
+// @Failure 500  {string}  string "Внутренняя ошибка сервера"
+// @Router /subscriptions/import [post]
+func (h *Handler) ImportSubscriptions(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+	deadline := time.Now().Add(importTimeout)
+	if err := rc.SetReadDeadline(deadline); err != nil {
+		h.log.Debug("не удалось продлить таймаут чтения", "error", err)
+	}
+	if err := rc.SetWriteDeadline(deadline); err != nil {
+		h.log.Debug("не удалось продлить таймаут записи", "error", err)
+	}
+
+	q := r.URL.Query()
+
+	dryRun := false
+	if v := q.Get("dry_run"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			h.respondError(w, r, http.StatusBadRequest, i18n.ImportInvalidOptions)
+			return
+		}
+		dryRun = parsed
+	}
+
+	var src service.RowSource
+	switch importFormat(r) {
+	case importFormatCSV:
+		opts, ok := csvOptions(q)
+		if !ok {
+			h.respondError(w, r, http.StatusBadRequest, i18n.ImportInvalidOptions)
+			return
+		}
+
+		csvSrc, err := service.NewCSVSource(r.Body, opts)
+		if err != nil {
+			var missingErr *service.MissingColumnError
+			if errors.As(err, &missingErr) {
+				loc := i18n.FromContext(r.Context())
+				http.Error(w, i18n.Translate(loc, i18n.ImportMissingColumn, missingErr.Column), http.StatusBadRequest)
+				return
+			}
+			h.log.Warn("не удалось прочитать заголовок CSV", "error", err)
+			h.respondError(w, r, http.StatusBadRequest, i18n.InvalidFormat)
+			return
+		}
+		src = csvSrc
+	case importFormatNDJSON:
+		src = service.NewNDJSONSource(r.Body)
+	default:
+		h.respondError(w, r, http.StatusUnsupportedMediaType, i18n.ImportUnsupportedFormat)
+		return
+	}
+
+	report, err := h.service.Import(r.Context(), src, dryRun)
+	if err != nil {
+		if h.respondDomainError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось импортировать подписки", "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	loc := i18n.FromContext(r.Context())
+	for i := range report.Errors {
+		report.Errors[i].Error = h.itemErrorMessage(loc, report.Errors[i].Err)
+	}
+
+	code := http.StatusOK
+	if !dryRun && !report.Committed {
+		code = http.StatusUnprocessableEntity
+	}
+
+	h.log.Info("импорт подписок завершён",
+		"dry_run", dryRun,
+		"total_rows", report.TotalRows,
+		"invalid_rows", report.InvalidRows,
+		"imported", report.Imported,
+	)
+
+	respondWithJSON(w, code, report)
+}
+
+// importFormat определяет формат файла по параметру format или Content-Type.
+func importFormat(r *http.Request) string {
+	if format := strings.ToLower(r.URL.Query().Get("format")); format != "" {
+		return format
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "text/csv", "application/csv":
+		return importFormatCSV
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return importFormatNDJSON
+	}
+
+	return ""
+}
+
+// csvOptions собирает настройки разбора CSV из параметров запроса.
+func csvOptions(q url.Values) (service.CSVOptions, bool) {
+	opts := service.CSVOptions{Headers: make(map[string]string)}
+
+	if values := q["delimiter"]; len(values) > 0 && values[0] != "" {
+		delimiter := values[0]
+		if strings.EqualFold(delimiter, "tab") || delimiter == `\t` {
+			delimiter = "\t"
+		}
+		if utf8.RuneCountInString(delimiter) != 1 {
+			return opts, false
+		}
+		opts.Delimiter, _ = utf8.DecodeRuneInString(delimiter)
+		if opts.Delimiter == '"' || opts.Delimiter == '\r' || opts.Delimiter == '\n' {
+			return opts, false
+		}
+	}
+
+	for key, values := range q {
+		if field, ok := strings.CutPrefix(key, "header."); ok && len(values) > 0 {
+			opts.Headers[field] = values[0]
+		}
+	}
+
+	return opts, true
+}
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"effective-mobile-task/internal/i18n"
+	"effective-mobile-task/internal/repository/postgres"
+	"effective-mobile-task/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// PauseSubscription обрабатывает запрос на приостановку подписки.
+// @Summary Pause a subscription
+// @Description Pauses an active subscription from the given month (current month by default). Paused months are excluded from summaries.
+// @Tags subscriptions
+// @Accept  json
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   id      path      string                   true   "Subscription ID"
+// @Param   change  body      service.StatusChangeDTO  false  "Month the pause starts from"
+// @Success 200     {object}  models.Subscription
+// @Failure 400     {string}  string "Неверный формат ID или JSON"
+// @Failure 403     {object}  problem "Недостаточно прав"
+// @Failure 404     {string}  string "Подписка не найдена"
+// @Failure 409     {string}  string "Действие недоступно в текущем состоянии"
+// @Failure 500     {string}  string "Внутренняя ошибка сервера"
+// @Router /subscriptions/{id}/pause [post]
+func (h *Handler) PauseSubscription(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, service.ActionPause)
+}
+
+// ResumeSubscription обрабатывает запрос на возобновление подписки.
+// @Summary Resume a paused subscription
+// @Description Resumes a paused subscription from the given month (current month by default).
+// @Tags subscriptions
+// @Accept  json
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   id      path      string                   true   "Subscription ID"
+// @Param   change  body      service.StatusChangeDTO  false  "First paid month after the pause"
+// @Success 200     {object}  models.Subscription
+// @Failure 400     {string}  string "Неверный формат ID или JSON"
+// @Failure 403     {object}  problem "Недостаточно прав"
+// @Failure 404     {string}  string "Подписка не найдена"
+// @Failure 409     {string}  string "Действие недоступно в текущем состоянии"
+// @Failure 500     {string}  string "Внутренняя ошибка сервера"
+// @Router /subscriptions/{id}/resume [post]
+func (h *Handler) ResumeSubscription(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, service.ActionResume)
+}
+
+// CancelSubscription обрабатывает запрос на отмену подписки.
+// @Summary Cancel a subscription
+// @Description Cancels an active or paused subscription. The given month (current month by default) becomes the last paid month and the subscription end date.
+// @Tags subscriptions
+// @Accept  json
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   id      path      string                   true   "Subscription ID"
+// @Param   change  body      service.StatusChangeDTO  false  "Last paid month"
+// @Success 200     {object}  models.Subscription
+// @Failure 400     {string}  string "Неверный формат ID или JSON"
+// @Failure 403     {object}  problem "Недостаточно прав"
+// @Failure 404     {string}  string "Подписка не найдена"
+// @Failure 409     {string}  string "Действие недоступно в текущем состоянии"
+// @Failure 500     {string}  string "Внутренняя ошибка сервера"
+// @Router /subscriptions/{id}/cancel [post]
+func (h *Handler) CancelSubscription(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, service.ActionCancel)
+}
+
+func (h *Handler) changeStatus(w http.ResponseWriter, r *http.Request, action service.LifecycleAction) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+
+	// Тело необязательно: без него переход действует с текущего месяца.
+	var dto service.StatusChangeDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil && !errors.Is(err, io.EOF) {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidJSON)
+		return
+	}
+
+	sub, err := h.service.ChangeStatus(r.Context(), id, action, dto)
+	if err != nil {
+		switch {
+		case errors.Is(err, postgres.ErrNotFound):
+			h.respondError(w, r, http.StatusNotFound, i18n.NotFound)
+			return
+		case errors.Is(err, postgres.ErrStatusChanged):
+			h.respondError(w, r, http.StatusConflict, i18n.StatusChanged)
+			return
+		}
+		if h.respondDomainError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось изменить состояние подписки", "id", id, "action", action, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, sub)
+}
+
+// GetStatusHistory обрабатывает запрос на получение истории состояний подписки.
+// @Summary Get status history of a subscription
+// @Description Returns all status transitions of the subscription with their timestamps, oldest first
+// @Tags subscriptions
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   id   path      string  true  "Subscription ID"
+// @Success 200  {array}   models.StatusChange
+// @Failure 400  {string}  string "Неверный формат ID"
+// @Failure 404  {string}  string "Подписка не найдена"
+// @Failure 500  {string}  string "Внутренняя ошибка сервера"
+// @Router /subscriptions/{id}/status-history [get]
+func (h *Handler) GetStatusHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+
+	history, err := h.service.StatusHistory(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			h.respondError(w, r, http.StatusNotFound, i18n.NotFound)
+			return
+		}
+		h.log.Error("не удалось получить историю состояний", "id", id, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, history)
+}
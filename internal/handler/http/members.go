@@ -0,0 +1,193 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"effective-mobile-task/internal/i18n"
+	"effective-mobile-task/internal/models"
+	"effective-mobile-task/internal/repository/postgres"
+	"effective-mobile-task/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// SetMember обрабатывает запрос на добавление участника общей подписки.
+// @Summary Add or update a member of a shared subscription
+// @Description The subscription owner pays for it and members reimburse their share: a fixed amount per month (share_amount) or a part of the rest proportional to share_weight. Fixed amounts are taken first, the rest is split by weights between the weighted members and the owner (weight 1 unless set by adding the owner as a member with share_weight). Per-user summaries, forecasts and budgets count only the user's share.
+// @Tags subscriptions
+// @Accept  json
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   id       path      string             true  "Subscription ID"
+// @Param   user_id  path      string             true  "Member User ID"
+// @Param   member   body      service.MemberDTO  true  "Share of the member"
+// @Success 200      {object}  models.SubscriptionMember
+// @Failure 400      {string}  string "Неверный формат JSON или неверные данные"
+// @Failure 403      {object}  problem "Недостаточно прав"
+// @Failure 404      {string}  string "Подписка не найдена"
+// @Failure 500      {string}  string "Внутренняя ошибка сервера"
+// @Router /subscriptions/{id}/members/{user_id} [put]
+func (h *Handler) SetMember(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+	userID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidUserID)
+		return
+	}
+
+	var dto service.MemberDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidJSON)
+		return
+	}
+
+	if err := h.validate.Struct(dto); err != nil {
+		h.log.Warn("неверные данные", "error", err)
+		h.respondValidationError(w, r, err)
+		return
+	}
+
+	member, err := h.service.SetMember(r.Context(), id, userID, dto)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			h.respondError(w, r, http.StatusNotFound, i18n.NotFound)
+			return
+		}
+		if h.respondDomainError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось сохранить участника подписки", "id", id, "user_id", userID, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, member)
+}
+
+// ListMembers обрабатывает запрос на получение участников подписки.
+// @Summary List members of a shared subscription
+// @Tags subscriptions
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   id   path      string  true  "Subscription ID"
+// @Success 200  {array}   models.SubscriptionMember
+// @Failure 400  {string}  string "Неверный формат ID"
+// @Failure 404  {string}  string "Подписка не найдена"
+// @Failure 500  {string}  string "Внутренняя ошибка сервера"
+// @Router /subscriptions/{id}/members [get]
+func (h *Handler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+
+	members, err := h.service.ListMembers(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			h.respondError(w, r, http.StatusNotFound, i18n.NotFound)
+			return
+		}
+		h.log.Error("не удалось получить участников подписки", "id", id, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, members)
+}
+
+// RemoveMember обрабатывает запрос на исключение участника из подписки.
+// @Summary Remove a member from a shared subscription
+// @Description The owner pays the share of the removed member again, including in past months.
+// @Tags subscriptions
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   id       path      string  true  "Subscription ID"
+// @Param   user_id  path      string  true  "Member User ID"
+// @Success 204
+// @Failure 400      {string}  string "Неверный формат ID"
+// @Failure 403      {object}  problem "Недостаточно прав"
+// @Failure 404      {string}  string "Пользователь не участник подписки"
+// @Failure 500      {string}  string "Внутренняя ошибка сервера"
+// @Router /subscriptions/{id}/members/{user_id} [delete]
+func (h *Handler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+	userID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidUserID)
+		return
+	}
+
+	if err := h.service.RemoveMember(r.Context(), id, userID); err != nil {
+		if errors.Is(err, postgres.ErrMemberNotFound) {
+			h.respondError(w, r, http.StatusNotFound, i18n.MemberNotFound)
+			return
+		}
+		if errors.Is(err, postgres.ErrNotFound) {
+			h.respondError(w, r, http.StatusNotFound, i18n.NotFound)
+			return
+		}
+		if h.respondDomainError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось исключить участника подписки", "id", id, "user_id", userID, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetSettlements обрабатывает запрос на расчёт долгов по общим подпискам.
+// @Summary Who owes whom for shared subscriptions
+// @Description For the given month (current by default) returns how much each member owes each subscription owner after discounts, with mutual debts of two users netted. Items list the shares per subscription; a netted debt in the opposite direction has a negative amount.
+// @Tags subscriptions
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   month    query     string  false  "Month (MM-YYYY, YYYY-MM or YYYY-MM-DD)"
+// @Param   user_id  query     string  false  "Only debts of and to this user"
+// @Success 200      {array}   models.Settlement
+// @Failure 400      {string}  string "Неверный месяц или ID пользователя"
+// @Failure 500      {string}  string "Внутренняя ошибка сервера"
+// @Router /subscriptions/settlements [get]
+func (h *Handler) GetSettlements(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	var month *models.Month
+	if str := q.Get("month"); str != "" {
+		m, err := models.ParseMonth(str)
+		if err != nil {
+			h.respondError(w, r, http.StatusBadRequest, i18n.InvalidMonth)
+			return
+		}
+		month = &m
+	}
+
+	var userID *uuid.UUID
+	if str := q.Get("user_id"); str != "" {
+		id, err := uuid.Parse(str)
+		if err != nil {
+			h.respondError(w, r, http.StatusBadRequest, i18n.InvalidUserID)
+			return
+		}
+		userID = &id
+	}
+
+	settlements, err := h.service.Settlements(r.Context(), month, userID)
+	if err != nil {
+		h.log.Error("не удалось посчитать долги по общим подпискам", "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, settlements)
+}
@@ -0,0 +1,224 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"effective-mobile-task/internal/auth"
+	"effective-mobile-task/internal/i18n"
+	"effective-mobile-task/internal/repository/postgres"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// Заголовки, которыми шлюз передаёт арендатора, пользователя и роль
+// запроса.
+const (
+	tenantHeader = "X-Tenant-ID"
+	userHeader   = "X-User-ID"
+	roleHeader   = "X-User-Role"
+)
+
+// localeMiddleware определяет язык клиента по заголовку Accept-Language
+// и сохраняет его в контексте запроса.
+func localeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		loc := i18n.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+
+		w.Header().Set("Content-Language", string(loc))
+		w.Header().Add("Vary", "Accept-Language")
+
+		next.ServeHTTP(w, r.WithContext(i18n.WithLocale(r.Context(), loc)))
+	})
+}
+
+// TenantBinder находит по токену доступа, от чьего имени выполняется
+// запрос, и привязывает запросы к базе к арендатору.
+type TenantBinder interface {
+	ResolveToken(ctx context.Context, token string) (auth.Principal, error)
+	WithTenant(ctx context.Context, tenantID uuid.UUID) (context.Context, error)
+}
+
+// tenantMiddleware определяет, от чьего имени выполняется запрос, и
+// выполняет его от имени арендатора. Арендатор, пользователь и роль берутся
+// из токена в заголовке Authorization, затем из заголовков X-Tenant-ID,
+// X-User-ID и X-User-Role, если им явно разрешено доверять. Запрос без арендатора
+// относится к арендатору по умолчанию или, если арендатор обязателен,
+// отклоняется. Запрос без роли получает роль по умолчанию.
+func (h *Handler) tenantMiddleware(next http.Handler) http.Handler {
+	if h.tenants == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, code, key := h.resolvePrincipal(r)
+		if code != 0 {
+			if code == http.StatusUnauthorized {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+			}
+			h.respondError(w, r, code, key)
+			return
+		}
+
+		ctx, err := h.tenants.WithTenant(r.Context(), principal.TenantID)
+		if err != nil {
+			if errors.Is(err, postgres.ErrTenantNotFound) {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				h.respondError(w, r, http.StatusUnauthorized, i18n.TenantUnknown)
+				return
+			}
+			h.log.Error("не удалось привязать запрос к арендатору", "tenant_id", principal.TenantID, "error", err)
+			h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(ctx, principal)))
+	})
+}
+
+// resolvePrincipal возвращает, от чьего имени выполняется запрос. Если
+// определить это не удалось, возвращает код ответа и сообщение об ошибке.
+func (h *Handler) resolvePrincipal(r *http.Request) (auth.Principal, int, i18n.Key) {
+	if header := r.Header.Get("Authorization"); header != "" {
+		token, found := strings.CutPrefix(header, "Bearer ")
+		token = strings.TrimSpace(token)
+		if !found || token == "" {
+			return auth.Principal{}, http.StatusUnauthorized, i18n.TenantUnknown
+		}
+
+		principal, err := h.tenants.ResolveToken(r.Context(), token)
+		if err != nil {
+			if errors.Is(err, postgres.ErrTenantNotFound) {
+				return auth.Principal{}, http.StatusUnauthorized, i18n.TenantUnknown
+			}
+			h.log.Error("не удалось найти арендатора по токену", "error", err)
+			return auth.Principal{}, http.StatusInternalServerError, i18n.Internal
+		}
+		return principal, 0, ""
+	}
+
+	principal := auth.Principal{TenantID: postgres.DefaultTenantID, Role: h.defaultRole}
+	hasTenant := false
+	if h.trustTenantHeader {
+		if header := r.Header.Get(tenantHeader); header != "" {
+			tenantID, err := uuid.Parse(header)
+			if err != nil {
+				return auth.Principal{}, http.StatusBadRequest, i18n.InvalidTenantID
+			}
+			principal.TenantID = tenantID
+			hasTenant = true
+		}
+	}
+	if h.trustUserHeaders {
+		if header := r.Header.Get(userHeader); header != "" {
+			userID, err := uuid.Parse(header)
+			if err != nil {
+				return auth.Principal{}, http.StatusBadRequest, i18n.InvalidPrincipal
+			}
+			principal.UserID = &userID
+		}
+		if header := r.Header.Get(roleHeader); header != "" {
+			role, ok := auth.ParseRole(header)
+			if !ok {
+				return auth.Principal{}, http.StatusBadRequest, i18n.InvalidPrincipal
+			}
+			principal.Role = role
+		}
+	}
+
+	if !hasTenant && h.tenantRequired {
+		return auth.Principal{}, http.StatusUnauthorized, i18n.TenantRequired
+	}
+
+	if principal.Role == "" {
+		return auth.Principal{}, http.StatusUnauthorized, i18n.TenantRequired
+	}
+	return principal, 0, ""
+}
+
+// rateLimitMiddleware ограничивает частоту запросов клиента к маршруту и
+// сообщает клиенту о лимите заголовками RateLimit-*. Если хранилище вёдер
+// недоступно, запрос пропускается: лимит защищает сервис от перегрузки, а
+// не данные.
+func (h *Handler) rateLimitMiddleware(next http.Handler) http.Handler {
+	if h.limiter == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		d, err := h.limiter.Allow(r.Context(), h.rateLimitClient(r), r.Method, routePattern(r))
+		if err != nil {
+			h.log.Error("не удалось проверить лимит запросов", "error", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+		if d.Limit.IsZero() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(d.Limit.Requests))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(d.Remaining))
+		w.Header().Set("RateLimit-Reset", ceilSeconds(d.Reset))
+		w.Header().Set("RateLimit-Policy", strconv.Itoa(d.Limit.Requests)+";w="+ceilSeconds(d.Limit.Period))
+
+		if !d.Allowed {
+			w.Header().Set("Retry-After", ceilSeconds(d.RetryAfter))
+			h.respondError(w, r, http.StatusTooManyRequests, i18n.RateLimited)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// rateLimitClient возвращает ключ клиента для лимитов: пользователя или
+// арендатора, от имени которого выполняется запрос, если они определены
+// токеном доступа или заголовками доверенного шлюза, иначе IP-адрес,
+// который middleware.RealIP уже взял из заголовков прокси. Ключ не
+// строится из того, что клиент может менять от запроса к запросу сам.
+func (h *Handler) rateLimitClient(r *http.Request) string {
+	if principal, ok := auth.FromContext(r.Context()); ok {
+		if principal.UserID != nil {
+			return "user:" + principal.TenantID.String() + "/" + principal.UserID.String()
+		}
+		// tenantMiddleware пропускает дальше только запросы с действующим
+		// токеном.
+		if r.Header.Get("Authorization") != "" {
+			return "tenant:" + principal.TenantID.String()
+		}
+	}
+
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	return "ip:" + ip
+}
+
+// routePattern возвращает шаблон маршрута запроса, например
+// /subscriptions/{id}. Запросы к несуществующим маршрутам получают пустой
+// шаблон и делят одно ведро.
+func routePattern(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil || rctx.Routes == nil {
+		return ""
+	}
+
+	tctx := chi.NewRouteContext()
+	if !rctx.Routes.Match(tctx, r.Method, r.URL.Path) {
+		return ""
+	}
+	return tctx.RoutePattern()
+}
+
+// ceilSeconds округляет длительность вверх до целых секунд.
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,530 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"effective-mobile-task/internal/i18n"
+	"effective-mobile-task/internal/models"
+	"effective-mobile-task/internal/repository/postgres"
+	"effective-mobile-task/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// chargebackColumns — колонки отчёта о расходах в файле.
+var chargebackColumns = []string{"month", "cost_centre_id", "parent_id", "kind", "code", "name", "path", "depth", "direct", "total"}
+
+// CreateOrganisation обрабатывает запрос на создание организации.
+// @Summary Create an organisation
+// @Tags organisations
+// @Accept  json
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   organisation  body      service.OrganisationDTO  true  "Organisation"
+// @Success 201           {object}  models.Organisation
+// @Failure 400           {string}  string "Неверный формат JSON или неверные данные"
+// @Failure 403           {object}  problem "Недостаточно прав"
+// @Failure 409           {string}  string "Организация с таким названием уже есть"
+// @Failure 500           {string}  string "Внутренняя ошибка сервера"
+// @Router /organisations [post]
+func (h *Handler) CreateOrganisation(w http.ResponseWriter, r *http.Request) {
+	var dto service.OrganisationDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidJSON)
+		return
+	}
+
+	if err := h.validate.Struct(dto); err != nil {
+		h.log.Warn("неверные данные", "error", err)
+		h.respondValidationError(w, r, err)
+		return
+	}
+
+	org, err := h.organisations.Create(r.Context(), dto)
+	if err != nil {
+		if h.respondOrganisationError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось создать организацию", "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, org)
+}
+
+// ListOrganisations обрабатывает запрос на получение списка организаций.
+// @Summary List organisations
+// @Tags organisations
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Success 200  {array}   models.Organisation
+// @Failure 500  {string}  string "Внутренняя ошибка сервера"
+// @Router /organisations [get]
+func (h *Handler) ListOrganisations(w http.ResponseWriter, r *http.Request) {
+	orgs, err := h.organisations.List(r.Context())
+	if err != nil {
+		h.log.Error("не удалось получить организации", "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, orgs)
+}
+
+// GetOrganisation обрабатывает запрос на получение организации.
+// @Summary Get an organisation by ID
+// @Tags organisations
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   id   path      string  true  "Organisation ID"
+// @Success 200  {object}  models.Organisation
+// @Failure 400  {string}  string "Неверный формат ID"
+// @Failure 404  {string}  string "Организация не найдена"
+// @Failure 500  {string}  string "Внутренняя ошибка сервера"
+// @Router /organisations/{id} [get]
+func (h *Handler) GetOrganisation(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+
+	org, err := h.organisations.GetByID(r.Context(), id)
+	if err != nil {
+		if h.respondOrganisationError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось получить организацию", "id", id, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, org)
+}
+
+// DeleteOrganisation обрабатывает запрос на удаление организации.
+// @Summary Delete an organisation
+// @Description Deletes the organisation with all its departments and cost centres. Subscriptions attached to them are kept without a cost centre.
+// @Tags organisations
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   id   path      string  true  "Organisation ID"
+// @Success 204
+// @Failure 400  {string}  string "Неверный формат ID"
+// @Failure 403  {object}  problem "Недостаточно прав"
+// @Failure 404  {string}  string "Организация не найдена"
+// @Failure 500  {string}  string "Внутренняя ошибка сервера"
+// @Router /organisations/{id} [delete]
+func (h *Handler) DeleteOrganisation(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+
+	if err := h.organisations.Delete(r.Context(), id); err != nil {
+		if h.respondOrganisationError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось удалить организацию", "id", id, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateCostCentre обрабатывает запрос на добавление узла в структуру
+// организации.
+// @Summary Add a department or cost centre to an organisation
+// @Description Departments form a tree and may contain other departments and cost centres; cost centres are leaves. A node without parent_id is a top-level node. Subscriptions can be attached to any node.
+// @Tags organisations
+// @Accept  json
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   id           path      string                 true  "Organisation ID"
+// @Param   cost_centre  body      service.CostCentreDTO  true  "Department or cost centre"
+// @Success 201          {object}  models.CostCentre
+// @Failure 400          {string}  string "Неверный формат JSON или неверные данные"
+// @Failure 403          {object}  problem "Недостаточно прав"
+// @Failure 404          {string}  string "Организация или родительский узел не найдены"
+// @Failure 409          {string}  string "В организации уже есть узел с таким кодом"
+// @Failure 500          {string}  string "Внутренняя ошибка сервера"
+// @Router /organisations/{id}/cost-centres [post]
+func (h *Handler) CreateCostCentre(w http.ResponseWriter, r *http.Request) {
+	orgID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+
+	var dto service.CostCentreDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidJSON)
+		return
+	}
+
+	if err := h.validate.Struct(dto); err != nil {
+		h.log.Warn("неверные данные", "error", err)
+		h.respondValidationError(w, r, err)
+		return
+	}
+
+	centre, err := h.organisations.CreateCostCentre(r.Context(), orgID, dto)
+	if err != nil {
+		if h.respondOrganisationError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось создать центр затрат", "organisation_id", orgID, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, centre)
+}
+
+// ListCostCentres обрабатывает запрос на получение структуры организации.
+// @Summary List departments and cost centres of an organisation
+// @Tags organisations
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   id   path      string  true  "Organisation ID"
+// @Success 200  {array}   models.CostCentre
+// @Failure 400  {string}  string "Неверный формат ID"
+// @Failure 404  {string}  string "Организация не найдена"
+// @Failure 500  {string}  string "Внутренняя ошибка сервера"
+// @Router /organisations/{id}/cost-centres [get]
+func (h *Handler) ListCostCentres(w http.ResponseWriter, r *http.Request) {
+	orgID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+
+	centres, err := h.organisations.ListCostCentres(r.Context(), orgID)
+	if err != nil {
+		if h.respondOrganisationError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось получить структуру организации", "organisation_id", orgID, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, centres)
+}
+
+// DeleteCostCentre обрабатывает запрос на удаление узла структуры.
+// @Summary Delete a department or cost centre
+// @Description Only a node without children can be deleted. Subscriptions attached to it are kept without a cost centre.
+// @Tags organisations
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   id              path      string  true  "Organisation ID"
+// @Param   cost_centre_id  path      string  true  "Department or cost centre ID"
+// @Success 204
+// @Failure 400             {string}  string "Неверный формат ID"
+// @Failure 403             {object}  problem "Недостаточно прав"
+// @Failure 404             {string}  string "Подразделение или центр затрат не найден"
+// @Failure 409             {string}  string "У узла есть дочерние узлы"
+// @Failure 500             {string}  string "Внутренняя ошибка сервера"
+// @Router /organisations/{id}/cost-centres/{cost_centre_id} [delete]
+func (h *Handler) DeleteCostCentre(w http.ResponseWriter, r *http.Request) {
+	orgID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "cost_centre_id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+
+	if err := h.organisations.DeleteCostCentre(r.Context(), orgID, id); err != nil {
+		if h.respondOrganisationError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось удалить центр затрат", "organisation_id", orgID, "id", id, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetChargeback обрабатывает запрос на отчёт о расходах по структуре
+// организации.
+// @Summary Chargeback report by department and cost centre
+// @Description For each month of the period (current month by default) returns the cost of subscriptions attached to every node of the organisation: direct is the cost of the node's own subscriptions, total includes all nodes below it. Costs are counted as in /subscriptions/summary, the other summary filters narrow the subscriptions. Rows are ordered by month and then by the tree, each node right after its parent; nodes without costs in a month are omitted.
+// @Tags organisations
+// @Produce  json
+// @Produce  text/csv
+// @Produce  application/x-ndjson
+// @Produce  application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   id            path      string  true   "Organisation ID"
+// @Param   format        query     string  false  "Response format (default json)"  Enums(json, csv, ndjson, xlsx)
+// @Param   start_date    query     string  false  "First month of the period (MM-YYYY, YYYY-MM or YYYY-MM-DD)"
+// @Param   end_date      query     string  false  "Last month of the period (MM-YYYY, YYYY-MM or YYYY-MM-DD)"
+// @Param   as_of         query     string  false  "Read subscription versions that were current at this moment (RFC 3339)"
+// @Param   user_id       query     string  false  "Filter by User ID (UUID format)"
+// @Param   service_name  query     string  false  "Filter by Service Name"
+// @Param   category      query     string  false  "Filter by service catalogue category"
+// @Param   tags          query     string  false  "Filter by comma-separated tags"
+// @Success 200           {array}   models.ChargebackRow
+// @Failure 400           {string}  string "Неверный фильтр или формат"
+// @Failure 404           {string}  string "Организация не найдена"
+// @Failure 500           {string}  string "Внутренняя ошибка сервера"
+// @Router /organisations/{id}/chargeback [get]
+func (h *Handler) GetChargeback(w http.ResponseWriter, r *http.Request) {
+	orgID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+
+	filter, key, ok := parseSummaryFilter(r)
+	if !ok {
+		h.respondError(w, r, http.StatusBadRequest, key)
+		return
+	}
+
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	contentType, ok := exportContentTypes[format]
+	if !ok && format != "" && format != "json" {
+		h.respondError(w, r, http.StatusBadRequest, i18n.ExportUnsupportedFormat)
+		return
+	}
+
+	report, err := h.organisations.Chargeback(r.Context(), orgID, filter)
+	if err != nil {
+		if h.respondOrganisationError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось построить отчёт о расходах", "organisation_id", orgID, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	if !ok {
+		respondWithJSON(w, http.StatusOK, report)
+		return
+	}
+
+	filename := "chargeback-" + time.Now().UTC().Format("20060102-150405") + "." + format
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	w.WriteHeader(http.StatusOK)
+
+	out := newExportWriter(w, format, "Chargeback", chargebackColumns)
+	for _, row := range report {
+		if err := out.WriteRow(chargebackValues(row)); err != nil {
+			h.log.Error("выгрузка отчёта о расходах прервана", "error", err)
+			panic(http.ErrAbortHandler)
+		}
+	}
+	if err := out.Close(); err != nil {
+		h.log.Error("не удалось завершить выгрузку отчёта о расходах", "error", err)
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// chargebackValues возвращает значения строки отчёта в порядке
+// chargebackColumns.
+func chargebackValues(row models.ChargebackRow) []any {
+	var parentID any
+	if row.ParentID != nil {
+		parentID = row.ParentID.String()
+	}
+	return []any{
+		row.Month.String(), row.CostCentreID.String(), parentID, string(row.Kind),
+		row.Code, row.Name, row.Path, row.Depth, row.Direct, row.Total,
+	}
+}
+
+// SetSubscriptionCostCentre обрабатывает запрос на отнесение подписки на
+// центр затрат.
+// @Summary Attach a subscription to a department or cost centre
+// @Description Its cost is then charged to the node and rolled up to all nodes above it. cost_centre_id null detaches the subscription.
+// @Tags subscriptions
+// @Accept  json
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   id          path      string                           true  "Subscription ID"
+// @Param   assignment  body      service.CostCentreAssignmentDTO  true  "Cost centre"
+// @Success 200         {object}  models.Subscription
+// @Failure 400         {string}  string "Неверный формат JSON или ID"
+// @Failure 403         {object}  problem "Недостаточно прав"
+// @Failure 404         {string}  string "Подписка или центр затрат не найдены"
+// @Failure 500         {string}  string "Внутренняя ошибка сервера"
+// @Router /subscriptions/{id}/cost-centre [put]
+func (h *Handler) SetSubscriptionCostCentre(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+
+	var dto service.CostCentreAssignmentDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidJSON)
+		return
+	}
+
+	sub, err := h.service.SetCostCentre(r.Context(), id, dto)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			h.respondError(w, r, http.StatusNotFound, i18n.NotFound)
+			return
+		}
+		if h.respondOrganisationError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось отнести подписку на центр затрат", "id", id, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, sub)
+}
+
+// ListOrganisationUsers обрабатывает запрос на получение пользователей
+// организации.
+// @Summary List organisation users
+// @Description Managers can edit subscriptions of users who share an organisation with them.
+// @Tags organisations
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   id   path      string  true  "Organisation ID"
+// @Success 200  {array}   models.OrganisationUser
+// @Failure 400  {string}  string "Неверный формат ID"
+// @Failure 404  {string}  string "Организация не найдена"
+// @Failure 500  {string}  string "Внутренняя ошибка сервера"
+// @Router /organisations/{id}/users [get]
+func (h *Handler) ListOrganisationUsers(w http.ResponseWriter, r *http.Request) {
+	orgID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+
+	users, err := h.organisations.ListUsers(r.Context(), orgID)
+	if err != nil {
+		if h.respondOrganisationError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось получить пользователей организации", "organisation_id", orgID, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, users)
+}
+
+// AddOrganisationUser обрабатывает запрос на включение пользователя в
+// организацию.
+// @Summary Add a user to an organisation
+// @Description Adding a user who is already a member changes nothing.
+// @Tags organisations
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   id       path      string  true  "Organisation ID"
+// @Param   user_id  path      string  true  "User ID"
+// @Success 200      {object}  models.OrganisationUser
+// @Failure 400      {string}  string "Неверный формат ID"
+// @Failure 403      {object}  problem "Недостаточно прав"
+// @Failure 404      {string}  string "Организация не найдена"
+// @Failure 500      {string}  string "Внутренняя ошибка сервера"
+// @Router /organisations/{id}/users/{user_id} [put]
+func (h *Handler) AddOrganisationUser(w http.ResponseWriter, r *http.Request) {
+	orgID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+	userID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidUserID)
+		return
+	}
+
+	user, err := h.organisations.AddUser(r.Context(), orgID, userID)
+	if err != nil {
+		if h.respondOrganisationError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось добавить пользователя в организацию", "organisation_id", orgID, "user_id", userID, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, user)
+}
+
+// RemoveOrganisationUser обрабатывает запрос на исключение пользователя из
+// организации.
+// @Summary Remove a user from an organisation
+// @Tags organisations
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   id       path      string  true  "Organisation ID"
+// @Param   user_id  path      string  true  "User ID"
+// @Success 204
+// @Failure 400      {string}  string "Неверный формат ID"
+// @Failure 403      {object}  problem "Недостаточно прав"
+// @Failure 404      {string}  string "Пользователь не состоит в организации"
+// @Failure 500      {string}  string "Внутренняя ошибка сервера"
+// @Router /organisations/{id}/users/{user_id} [delete]
+func (h *Handler) RemoveOrganisationUser(w http.ResponseWriter, r *http.Request) {
+	orgID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+	userID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidUserID)
+		return
+	}
+
+	if err := h.organisations.RemoveUser(r.Context(), orgID, userID); err != nil {
+		if h.respondOrganisationError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось исключить пользователя из организации", "organisation_id", orgID, "user_id", userID, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respondOrganisationError отвечает клиенту на ошибки организаций и их
+// структуры. Возвращает false, если ошибка к ним не относится.
+func (h *Handler) respondOrganisationError(w http.ResponseWriter, r *http.Request, err error) bool {
+	switch {
+	case errors.Is(err, postgres.ErrOrganisationNotFound):
+		h.respondError(w, r, http.StatusNotFound, i18n.OrganisationNotFound)
+		return true
+	case errors.Is(err, postgres.ErrOrganisationConflict):
+		h.respondError(w, r, http.StatusConflict, i18n.OrganisationConflict)
+		return true
+	case errors.Is(err, postgres.ErrCostCentreNotFound):
+		h.respondError(w, r, http.StatusNotFound, i18n.CostCentreNotFound)
+		return true
+	case errors.Is(err, postgres.ErrCostCentreConflict):
+		h.respondError(w, r, http.StatusConflict, i18n.CostCentreConflict)
+		return true
+	case errors.Is(err, postgres.ErrCostCentreInUse):
+		h.respondError(w, r, http.StatusConflict, i18n.CostCentreInUse)
+		return true
+	case errors.Is(err, postgres.ErrOrganisationUserNotFound):
+		h.respondError(w, r, http.StatusNotFound, i18n.OrganisationUserNotFound)
+		return true
+	}
+	return h.respondDomainError(w, r, err)
+}
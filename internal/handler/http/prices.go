@@ -0,0 +1,148 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"effective-mobile-task/internal/i18n"
+	"effective-mobile-task/internal/repository/postgres"
+	"effective-mobile-task/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// SchedulePriceChange обрабатывает запрос на изменение цены места с указанного месяца.
+// @Summary Schedule a unit price change
+// @Description Sets the price per seat from the given month (current or future) until the next change. A change from the same month is replaced. Summaries use the price effective in each month. Returns the resulting price history.
+// @Tags subscriptions
+// @Accept  json
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   id      path      string                  true  "Subscription ID"
+// @Param   change  body      service.PriceChangeDTO  true  "New unit price and the month it applies from"
+// @Success 200     {array}   models.PricePeriod
+// @Failure 400     {string}  string "Неверный формат JSON или неверные данные"
+// @Failure 403     {object}  problem "Недостаточно прав"
+// @Failure 404     {string}  string "Подписка не найдена"
+// @Failure 500     {string}  string "Внутренняя ошибка сервера"
+// @Router /subscriptions/{id}/prices [post]
+func (h *Handler) SchedulePriceChange(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+
+	var dto service.PriceChangeDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidJSON)
+		return
+	}
+
+	if err := h.validate.Struct(dto); err != nil {
+		h.log.Warn("неверные данные", "error", err)
+		h.respondValidationError(w, r, err)
+		return
+	}
+
+	history, err := h.service.SchedulePriceChange(r.Context(), id, dto)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			h.respondError(w, r, http.StatusNotFound, i18n.NotFound)
+			return
+		}
+		if h.respondDomainError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось запланировать изменение цены", "id", id, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, history)
+}
+
+// ChangeSeats обрабатывает запрос на изменение числа мест с указанного месяца.
+// @Summary Change the seat count
+// @Description Sets the number of seats from the given month (current or future) until the next change. A change from the same month is replaced. Summaries bill each month by the seats effective in it. Returns the resulting price history.
+// @Tags subscriptions
+// @Accept  json
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   id      path      string                 true  "Subscription ID"
+// @Param   change  body      service.SeatChangeDTO  true  "New seat count and the month it applies from"
+// @Success 200     {array}   models.PricePeriod
+// @Failure 400     {string}  string "Неверный формат JSON или неверные данные"
+// @Failure 403     {object}  problem "Недостаточно прав"
+// @Failure 404     {string}  string "Подписка не найдена"
+// @Failure 500     {string}  string "Внутренняя ошибка сервера"
+// @Router /subscriptions/{id}/seats [post]
+func (h *Handler) ChangeSeats(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+
+	var dto service.SeatChangeDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidJSON)
+		return
+	}
+
+	if err := h.validate.Struct(dto); err != nil {
+		h.log.Warn("неверные данные", "error", err)
+		h.respondValidationError(w, r, err)
+		return
+	}
+
+	history, err := h.service.ChangeSeats(r.Context(), id, dto)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			h.respondError(w, r, http.StatusNotFound, i18n.NotFound)
+			return
+		}
+		if h.respondDomainError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось изменить число мест", "id", id, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, history)
+}
+
+// GetPriceHistory обрабатывает запрос на получение истории цен подписки.
+// @Summary Get price history of a subscription
+// @Description Returns the price periods of the subscription from its start month, including scheduled changes, oldest first. A new period starts with every change of the unit price or the seat count.
+// @Tags subscriptions
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   id   path      string  true  "Subscription ID"
+// @Success 200  {array}   models.PricePeriod
+// @Failure 400  {string}  string "Неверный формат ID"
+// @Failure 404  {string}  string "Подписка не найдена"
+// @Failure 500  {string}  string "Внутренняя ошибка сервера"
+// @Router /subscriptions/{id}/prices [get]
+func (h *Handler) GetPriceHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+
+	history, err := h.service.PriceHistory(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			h.respondError(w, r, http.StatusNotFound, i18n.NotFound)
+			return
+		}
+		h.log.Error("не удалось получить историю цен", "id", id, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, history)
+}
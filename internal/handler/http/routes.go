@@ -17,19 +17,90 @@ func (h *Handler) RegisterRoutes() http.Handler {
 	r.Use(middleware.RealIP)
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
+	r.Use(localeMiddleware)
 
-	r.Get("/swagger/*", httpSwagger.Handler(
+	r.With(h.rateLimitMiddleware).Get("/swagger/*", httpSwagger.Handler(
 		httpSwagger.URL("http://localhost:8080/swagger/doc.json"),
 	))
 
-	r.Route("/subscriptions", func(r chi.Router) {
+	// Запросы к данным выполняются от имени арендатора. Лимит проверяется
+	// после того, как известно, от чьего имени выполняется запрос.
+	api := r.With(h.tenantMiddleware, h.rateLimitMiddleware)
+
+	api.Route("/subscriptions", func(r chi.Router) {
 		r.Post("/", h.CreateSubscription)
+		r.Get("/", h.ListSubscriptions)
 		r.Get("/summary", h.GetSummary)
+		r.Get("/summary/categories", h.GetSummaryByCategory)
+		r.Get("/forecast", h.GetForecast)
+		r.Get("/settlements", h.GetSettlements)
+		r.Get("/search", h.SearchSubscriptions)
+		r.Post("/batch", h.BatchSubscriptions)
+		r.Post("/import", h.ImportSubscriptions)
+		r.Get("/export", h.ExportSubscriptions)
+		r.Get("/trials/converting", h.ListConvertingTrials)
 
 		r.Route("/{id}", func(r chi.Router) {
 			r.Get("/", h.GetSubscriptionByID)
 			r.Put("/", h.UpdateSubscription)
 			r.Delete("/", h.DeleteSubscription)
+			r.Post("/tags", h.AddSubscriptionTags)
+			r.Delete("/tags/{tag}", h.RemoveSubscriptionTag)
+			r.Post("/pause", h.PauseSubscription)
+			r.Post("/resume", h.ResumeSubscription)
+			r.Post("/cancel", h.CancelSubscription)
+			r.Get("/status-history", h.GetStatusHistory)
+			r.Post("/prices", h.SchedulePriceChange)
+			r.Get("/prices", h.GetPriceHistory)
+			r.Post("/seats", h.ChangeSeats)
+			r.Post("/discounts", h.AddDiscount)
+			r.Get("/discounts", h.ListDiscounts)
+			r.Delete("/discounts/{discount_id}", h.DeleteDiscount)
+			r.Get("/members", h.ListMembers)
+			r.Put("/members/{user_id}", h.SetMember)
+			r.Delete("/members/{user_id}", h.RemoveMember)
+			r.Put("/cost-centre", h.SetSubscriptionCostCentre)
+		})
+	})
+
+	api.Route("/services", func(r chi.Router) {
+		r.Post("/", h.CreateService)
+		r.Get("/", h.ListServices)
+
+		r.Route("/{id}", func(r chi.Router) {
+			r.Get("/", h.GetServiceByID)
+			r.Put("/", h.UpdateService)
+			r.Delete("/", h.DeleteService)
+		})
+	})
+
+	api.Route("/organisations", func(r chi.Router) {
+		r.Post("/", h.CreateOrganisation)
+		r.Get("/", h.ListOrganisations)
+
+		r.Route("/{id}", func(r chi.Router) {
+			r.Get("/", h.GetOrganisation)
+			r.Delete("/", h.DeleteOrganisation)
+			r.Post("/cost-centres", h.CreateCostCentre)
+			r.Get("/cost-centres", h.ListCostCentres)
+			r.Delete("/cost-centres/{cost_centre_id}", h.DeleteCostCentre)
+			r.Get("/chargeback", h.GetChargeback)
+			r.Get("/users", h.ListOrganisationUsers)
+			r.Put("/users/{user_id}", h.AddOrganisationUser)
+			r.Delete("/users/{user_id}", h.RemoveOrganisationUser)
+		})
+	})
+
+	api.Route("/users/{user_id}", func(r chi.Router) {
+		r.Get("/data-export", h.ExportUserData)
+		r.Delete("/", h.EraseUserData)
+
+		r.Route("/budgets", func(r chi.Router) {
+			r.Post("/", h.CreateBudget)
+			r.Get("/", h.ListBudgets)
+			r.Get("/status", h.GetBudgetStatus)
+			r.Put("/{budget_id}", h.UpdateBudget)
+			r.Delete("/{budget_id}", h.DeleteBudget)
 		})
 	})
 
@@ -0,0 +1,206 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"effective-mobile-task/internal/i18n"
+	"effective-mobile-task/internal/repository/postgres"
+	"effective-mobile-task/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// CreateService обрабатывает запрос на добавление сервиса в каталог.
+// @Summary Add a service to the catalogue
+// @Description Adds a service with its aliases. Existing subscriptions whose service name matches the name or an alias are linked to the service and renamed to the canonical name.
+// @Tags services
+// @Accept  json
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   service  body      service.ServiceDTO  true  "Service Info"
+// @Success 201      {object}  models.Service
+// @Failure 400      {string}  string "Неверный формат JSON или неверные данные"
+// @Failure 403      {object}  problem "Недостаточно прав"
+// @Failure 409      {string}  string "Название или псевдоним уже используется"
+// @Failure 500      {string}  string "Внутренняя ошибка сервера"
+// @Router /services [post]
+func (h *Handler) CreateService(w http.ResponseWriter, r *http.Request) {
+	var dto service.ServiceDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		h.log.Warn("не удалось декодировать тело запроса", "error", err)
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidJSON)
+		return
+	}
+
+	if err := h.validate.Struct(dto); err != nil {
+		h.log.Warn("неверные данные", "error", err)
+		h.respondValidationError(w, r, err)
+		return
+	}
+
+	svc, err := h.catalog.Create(r.Context(), dto)
+	if err != nil {
+		if h.respondCatalogError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось создать сервис", "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, svc)
+}
+
+// ListServices обрабатывает запрос на получение каталога сервисов.
+// @Summary List catalogue services
+// @Description Returns all services of the catalogue ordered by name
+// @Tags services
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   category  query     string  false  "Filter by category"
+// @Success 200       {array}   models.Service
+// @Failure 500       {string}  string "Внутренняя ошибка сервера"
+// @Router /services [get]
+func (h *Handler) ListServices(w http.ResponseWriter, r *http.Request) {
+	var category *string
+	if c := r.URL.Query().Get("category"); c != "" {
+		category = &c
+	}
+
+	services, err := h.catalog.List(r.Context(), category)
+	if err != nil {
+		h.log.Error("не удалось получить каталог сервисов", "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, services)
+}
+
+// GetServiceByID обрабатывает запрос на получение сервиса каталога по ID.
+// @Summary Get a catalogue service by ID
+// @Description Get details of a specific catalogue service
+// @Tags services
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   id   path      string  true  "Service ID"
+// @Success 200  {object}  models.Service
+// @Failure 400  {string}  string "Неверный формат ID"
+// @Failure 404  {string}  string "Сервис не найден"
+// @Failure 500  {string}  string "Внутренняя ошибка сервера"
+// @Router /services/{id} [get]
+func (h *Handler) GetServiceByID(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+
+	svc, err := h.catalog.GetByID(r.Context(), id)
+	if err != nil {
+		if h.respondCatalogError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось получить сервис", "id", id, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, svc)
+}
+
+// UpdateService обрабатывает запрос на изменение сервиса каталога.
+// @Summary Update a catalogue service
+// @Description Replaces the service data and its set of aliases. Subscriptions linked to the service are renamed to the new canonical name.
+// @Tags services
+// @Accept  json
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   id       path      string              true  "Service ID"
+// @Param   service  body      service.ServiceDTO  true  "Service data to update"
+// @Success 200      {object}  models.Service
+// @Failure 400      {string}  string "Неверный формат JSON или неверные данные"
+// @Failure 403      {object}  problem "Недостаточно прав"
+// @Failure 404      {string}  string "Сервис не найден"
+// @Failure 409      {string}  string "Название или псевдоним уже используется"
+// @Failure 500      {string}  string "Внутренняя ошибка сервера"
+// @Router /services/{id} [put]
+func (h *Handler) UpdateService(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+
+	var dto service.ServiceDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidJSON)
+		return
+	}
+
+	if err := h.validate.Struct(dto); err != nil {
+		h.log.Warn("неверные данные", "error", err)
+		h.respondValidationError(w, r, err)
+		return
+	}
+
+	svc, err := h.catalog.Update(r.Context(), id, dto)
+	if err != nil {
+		if h.respondCatalogError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось обновить сервис", "id", id, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, svc)
+}
+
+// DeleteService обрабатывает запрос на удаление сервиса из каталога.
+// @Summary Delete a catalogue service
+// @Description Deletes the service. Subscriptions keep their name but lose the link to the catalogue.
+// @Tags services
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   id   path      string  true  "Service ID"
+// @Success 204  {string}  string "No Content"
+// @Failure 400  {string}  string "Неверный формат ID"
+// @Failure 403  {object}  problem "Недостаточно прав"
+// @Failure 404  {string}  string "Сервис не найден"
+// @Failure 500  {string}  string "Внутренняя ошибка сервера"
+// @Router /services/{id} [delete]
+func (h *Handler) DeleteService(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+
+	if err := h.catalog.Delete(r.Context(), id); err != nil {
+		if h.respondCatalogError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось удалить сервис", "id", id, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// respondCatalogError отвечает клиенту на ошибки каталога сервисов.
+// Возвращает false, если ошибка к ним не относится.
+func (h *Handler) respondCatalogError(w http.ResponseWriter, r *http.Request, err error) bool {
+	switch {
+	case errors.Is(err, postgres.ErrServiceNotFound):
+		h.respondError(w, r, http.StatusNotFound, i18n.ServiceNotFound)
+		return true
+	case errors.Is(err, postgres.ErrServiceConflict):
+		h.respondError(w, r, http.StatusConflict, i18n.ServiceConflict)
+		return true
+	}
+	return h.respondDomainError(w, r, err)
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"effective-mobile-task/internal/i18n"
+	"effective-mobile-task/internal/repository/postgres"
+	"effective-mobile-task/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// AddSubscriptionTags обрабатывает запрос на добавление меток подписке.
+// @Summary Add tags to a subscription
+// @Description Attaches tags to the subscription, creating the ones its owner does not have yet. Tags are case-insensitive. Returns all tags of the subscription.
+// @Tags subscriptions
+// @Accept  json
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   id    path      string           true  "Subscription ID"
+// @Param   tags  body      service.TagsDTO  true  "Tags to add"
+// @Success 200   {object}  map[string][]string
+// @Failure 400   {string}  string "Неверный формат JSON или неверные метки"
+// @Failure 403   {object}  problem "Недостаточно прав"
+// @Failure 404   {string}  string "Подписка не найдена"
+// @Failure 500   {string}  string "Внутренняя ошибка сервера"
+// @Router /subscriptions/{id}/tags [post]
+func (h *Handler) AddSubscriptionTags(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+
+	var dto service.TagsDTO
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidJSON)
+		return
+	}
+
+	if err := h.validate.Struct(dto); err != nil {
+		h.log.Warn("неверные данные", "error", err)
+		h.respondValidationError(w, r, err)
+		return
+	}
+
+	tags, err := h.service.AddTags(r.Context(), id, dto)
+	h.respondTags(w, r, id, tags, err)
+}
+
+// RemoveSubscriptionTag обрабатывает запрос на снятие метки с подписки.
+// @Summary Remove a tag from a subscription
+// @Description Detaches the tag from the subscription. Returns the remaining tags of the subscription.
+// @Tags subscriptions
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   id   path      string  true  "Subscription ID"
+// @Param   tag  path      string  true  "Tag"
+// @Success 200  {object}  map[string][]string
+// @Failure 400  {string}  string "Неверный формат ID или метки"
+// @Failure 403  {object}  problem "Недостаточно прав"
+// @Failure 404  {string}  string "Подписка не найдена"
+// @Failure 500  {string}  string "Внутренняя ошибка сервера"
+// @Router /subscriptions/{id}/tags/{tag} [delete]
+func (h *Handler) RemoveSubscriptionTag(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidID)
+		return
+	}
+
+	tag, err := url.PathUnescape(chi.URLParam(r, "tag"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.TagLength)
+		return
+	}
+
+	tags, err := h.service.RemoveTag(r.Context(), id, tag)
+	h.respondTags(w, r, id, tags, err)
+}
+
+func (h *Handler) respondTags(w http.ResponseWriter, r *http.Request, id uuid.UUID, tags []string, err error) {
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			h.respondError(w, r, http.StatusNotFound, i18n.NotFound)
+			return
+		}
+		if h.respondDomainError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось изменить метки подписки", "id", id, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, map[string][]string{"tags": tags})
+}
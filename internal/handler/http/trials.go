@@ -0,0 +1,47 @@
+package http
+
+import (
+	"net/http"
+
+	"effective-mobile-task/internal/i18n"
+	"github.com/google/uuid"
+)
+
+// ListConvertingTrials обрабатывает запрос на отчёт о заканчивающихся пробных периодах.
+// @Summary List trials converting to paid
+// @Description Returns active subscriptions whose free trial ends and which become paid within the next N days, ordered by conversion time. Subscriptions that end together with the trial are not listed.
+// @Tags subscriptions
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   days     query     int     false  "Look-ahead window in days (default 7, max 366)"
+// @Param   user_id  query     string  false  "Filter by User ID (UUID format)"
+// @Success 200      {array}   models.TrialConversion
+// @Failure 400      {string}  string "Неверный срок или ID пользователя"
+// @Failure 500      {string}  string "Внутренняя ошибка сервера"
+// @Router /subscriptions/trials/converting [get]
+func (h *Handler) ListConvertingTrials(w http.ResponseWriter, r *http.Request) {
+	days, ok := queryInt(r, "days", 1)
+	if !ok {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidDays)
+		return
+	}
+
+	var userID *uuid.UUID
+	if str := r.URL.Query().Get("user_id"); str != "" {
+		id, err := uuid.Parse(str)
+		if err != nil {
+			h.respondError(w, r, http.StatusBadRequest, i18n.InvalidUserID)
+			return
+		}
+		userID = &id
+	}
+
+	trials, err := h.service.ConvertingTrials(r.Context(), days, userID)
+	if err != nil {
+		h.log.Error("не удалось получить отчёт о пробных периодах", "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, trials)
+}
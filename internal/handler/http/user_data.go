@@ -0,0 +1,93 @@
+package http
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"time"
+
+	"effective-mobile-task/internal/i18n"
+	"effective-mobile-task/internal/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// ExportUserData обрабатывает запрос пользователя на выгрузку его данных.
+// @Summary Export all personal data of a user
+// @Description Returns a ZIP archive with one JSON file per data section (subscriptions, ...) and a manifest.json.
+// @Tags users
+// @Produce  application/zip
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   user_id  path      string  true  "User ID"
+// @Success 200      {file}    file
+// @Failure 400      {string}  string "Неверный формат user_id"
+// @Failure 404      {string}  string "Данные пользователя не найдены"
+// @Failure 500      {string}  string "Внутренняя ошибка сервера"
+// @Router /users/{user_id}/data-export [get]
+func (h *Handler) ExportUserData(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidUserID)
+		return
+	}
+
+	if err := h.userData.CheckUserData(r.Context(), userID); err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			h.respondError(w, r, http.StatusNotFound, i18n.UserNotFound)
+			return
+		}
+		h.log.Error("не удалось проверить данные пользователя", "user_id", userID, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(exportTimeout)); err != nil {
+		h.log.Debug("не удалось продлить таймаут записи", "error", err)
+	}
+
+	filename := "user-data-" + userID.String() + ".zip"
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	w.WriteHeader(http.StatusOK)
+
+	if err := h.userData.ExportUserData(r.Context(), userID, w); err != nil {
+		h.log.Error("выгрузка данных пользователя прервана", "user_id", userID, "error", err)
+		panic(http.ErrAbortHandler)
+	}
+
+	h.log.Info("данные пользователя выгружены", "user_id", userID)
+}
+
+// EraseUserData обрабатывает запрос пользователя на удаление его данных.
+// @Summary Erase all personal data of a user
+// @Description Deletes every row referencing the user in a single transaction and returns the stored erasure receipt.
+// @Tags users
+// @Produce  json
+// @Param   Accept-Language  header  string  false  "Preferred language of error messages (ru, en)"
+// @Param   user_id  path      string  true  "User ID"
+// @Success 200      {object}  models.ErasureReceipt
+// @Failure 400      {string}  string "Неверный формат user_id"
+// @Failure 403      {object}  problem "Недостаточно прав"
+// @Failure 500      {string}  string "Внутренняя ошибка сервера"
+// @Router /users/{user_id} [delete]
+func (h *Handler) EraseUserData(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		h.respondError(w, r, http.StatusBadRequest, i18n.InvalidUserID)
+		return
+	}
+
+	receipt, err := h.userData.EraseUserData(r.Context(), userID)
+	if err != nil {
+		if h.respondDomainError(w, r, err) {
+			return
+		}
+		h.log.Error("не удалось удалить данные пользователя", "user_id", userID, "error", err)
+		h.respondError(w, r, http.StatusInternalServerError, i18n.Internal)
+		return
+	}
+
+	h.log.Info("данные пользователя удалены", "receipt_id", receipt.ID, "affected", receipt.Affected)
+
+	respondWithJSON(w, http.StatusOK, receipt)
+}
@@ -0,0 +1,78 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"effective-mobile-task/internal/i18n"
+	"effective-mobile-task/internal/models"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/ru"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	ruTranslations "github.com/go-playground/validator/v10/translations/ru"
+)
+
+// newValidator создаёт валидатор с переводами ошибок на все поддерживаемые языки.
+func newValidator() (*validator.Validate, *ut.UniversalTranslator, error) {
+	v := validator.New()
+
+	// В сообщениях об ошибках используем имена полей из JSON, а не из Go-структур.
+	v.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name, _, _ := strings.Cut(fld.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return fld.Name
+		}
+		return name
+	})
+
+	// Месяц без значения считается незаполненным, чтобы работал тег required.
+	v.RegisterCustomTypeFunc(func(field reflect.Value) any {
+		month, ok := field.Interface().(models.Month)
+		if !ok || month.IsZero() {
+			return nil
+		}
+		return month.Time()
+	}, models.Month{})
+
+	uni := ut.New(ru.New(), ru.New(), en.New())
+
+	registrations := map[i18n.Locale]func(*validator.Validate, ut.Translator) error{
+		i18n.RU: ruTranslations.RegisterDefaultTranslations,
+		i18n.EN: enTranslations.RegisterDefaultTranslations,
+	}
+	for loc, register := range registrations {
+		trans, found := uni.GetTranslator(string(loc))
+		if !found {
+			return nil, nil, fmt.Errorf("не найден переводчик для языка %q", loc)
+		}
+		if err := register(v, trans); err != nil {
+			return nil, nil, fmt.Errorf("не удалось зарегистрировать переводы для языка %q: %w", loc, err)
+		}
+	}
+
+	return v, uni, nil
+}
+
+// translateValidationError переводит ошибки валидатора на язык клиента.
+func (h *Handler) translateValidationError(loc i18n.Locale, err error) string {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return i18n.Translate(loc, i18n.InvalidData)
+	}
+
+	trans, _ := h.translator.GetTranslator(string(loc))
+
+	messages := make([]string, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		messages = append(messages, fe.Translate(trans))
+	}
+
+	return strings.Join(messages, "; ")
+}
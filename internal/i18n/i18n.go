@@ -0,0 +1,364 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Locale — язык, на котором API возвращает сообщения клиенту.
+type Locale string
+
+const (
+	RU Locale = "ru"
+	EN Locale = "en"
+
+	// Default используется, если клиент не прислал Accept-Language
+	// или запросил неподдерживаемый язык.
+	Default = RU
+)
+
+// Supported перечисляет языки, для которых есть переводы.
+var Supported = []Locale{RU, EN}
+
+// Key — идентификатор сообщения в каталоге.
+type Key string
+
+const (
+	InvalidJSON      Key = "invalid_json"
+	InvalidData      Key = "invalid_data"
+	InvalidID        Key = "invalid_id"
+	InvalidUserID    Key = "invalid_user_id"
+	InvalidStartDate Key = "invalid_start_date"
+	InvalidEndDate   Key = "invalid_end_date"
+	NotFound         Key = "not_found"
+	Internal         Key = "internal"
+
+	EndBeforeStart          Key = "end_before_start"
+	ServiceNameLength       Key = "service_name_length"
+	ServiceNameSymbols      Key = "service_name_symbols"
+	OverlappingSubscription Key = "overlapping_subscription"
+
+	BatchRolledBack Key = "batch_rolled_back"
+
+	InvalidFormat           Key = "invalid_format"
+	PricePositive           Key = "price_positive"
+	PriceFraction           Key = "price_fraction"
+	ImportMissingColumn     Key = "import_missing_column"
+	ImportUnsupportedFormat Key = "import_unsupported_format"
+	ImportInvalidOptions    Key = "import_invalid_options"
+
+	ExportUnsupportedFormat Key = "export_unsupported_format"
+	ExportUnknownColumn     Key = "export_unknown_column"
+
+	UserNotFound Key = "user_not_found"
+
+	ServiceNotFound Key = "service_not_found"
+	ServiceConflict Key = "service_conflict"
+
+	SearchQueryRequired Key = "search_query_required"
+	InvalidLimit        Key = "invalid_limit"
+
+	InvalidOffset    Key = "invalid_offset"
+	TagLength        Key = "tag_length"
+	InvalidTagsMatch Key = "invalid_tags_match"
+
+	InvalidTransition Key = "invalid_transition"
+	StatusChanged     Key = "status_changed"
+
+	TrialOutsidePeriod Key = "trial_outside_period"
+	InvalidDays        Key = "invalid_days"
+
+	ChangeInPast        Key = "change_in_past"
+	ChangeOutsidePeriod Key = "change_outside_period"
+	PriceMismatch       Key = "price_mismatch"
+
+	DiscountPercent       Key = "discount_percent"
+	DiscountTerm          Key = "discount_term"
+	DiscountOutsidePeriod Key = "discount_outside_period"
+	DiscountNotFound      Key = "discount_not_found"
+	InvalidBreakdown      Key = "invalid_breakdown"
+
+	BudgetScope     Key = "budget_scope"
+	BudgetThreshold Key = "budget_threshold"
+	BudgetNotFound  Key = "budget_not_found"
+	BudgetConflict  Key = "budget_conflict"
+
+	InvalidMonths Key = "invalid_months"
+
+	MemberShare    Key = "member_share"
+	MemberNotFound Key = "member_not_found"
+	InvalidMonth   Key = "invalid_month"
+
+	OrganisationNotFound Key = "organisation_not_found"
+	OrganisationConflict Key = "organisation_conflict"
+	CostCentreParent     Key = "cost_centre_parent"
+	CostCentreNotFound   Key = "cost_centre_not_found"
+	CostCentreConflict   Key = "cost_centre_conflict"
+	CostCentreInUse      Key = "cost_centre_in_use"
+
+	TenantRequired  Key = "tenant_required"
+	TenantUnknown   Key = "tenant_unknown"
+	InvalidTenantID Key = "invalid_tenant_id"
+
+	Forbidden                Key = "forbidden"
+	InvalidPrincipal         Key = "invalid_principal"
+	OrganisationUserNotFound Key = "organisation_user_not_found"
+
+	RateLimited Key = "rate_limited"
+
+	InvalidAsOf Key = "invalid_as_of"
+)
+
+var catalogue = map[Locale]map[Key]string{
+	RU: {
+		InvalidJSON:      "Неверный формат JSON",
+		InvalidData:      "Неверные данные",
+		InvalidID:        "Неверный формат ID",
+		InvalidUserID:    "Неверный формат user_id",
+		InvalidStartDate: "Неверный формат start_date, используйте MM-YYYY",
+		InvalidEndDate:   "Неверный формат end_date, используйте MM-YYYY",
+		NotFound:         "Подписка не найдена",
+		Internal:         "Внутренняя ошибка сервера",
+
+		EndBeforeStart:          "end_date не может быть раньше start_date",
+		ServiceNameLength:       "service_name должно содержать от 2 до 100 символов",
+		ServiceNameSymbols:      "service_name содержит недопустимые символы",
+		OverlappingSubscription: "У пользователя уже есть подписка на этот сервис в указанный период",
+
+		BatchRolledBack: "Операция не применена: пакет отменён из-за ошибки в другой операции",
+
+		InvalidFormat:           "Неверный формат значения",
+		PricePositive:           "price должно быть больше нуля",
+		PriceFraction:           "price должно быть целым числом рублей",
+		ImportMissingColumn:     "В файле нет обязательной колонки %s",
+		ImportUnsupportedFormat: "Неподдерживаемый формат файла, используйте csv или ndjson",
+		ImportInvalidOptions:    "Неверные параметры импорта",
+
+		ExportUnsupportedFormat: "Неподдерживаемый формат выгрузки, используйте csv, ndjson или xlsx",
+		ExportUnknownColumn:     "Неизвестная колонка выгрузки %s",
+
+		UserNotFound: "Данные пользователя не найдены",
+
+		ServiceNotFound: "Сервис не найден в каталоге",
+		ServiceConflict: "Название или псевдоним уже используется другим сервисом",
+
+		SearchQueryRequired: "Укажите строку поиска в параметре q",
+		InvalidLimit:        "limit должен быть положительным целым числом",
+
+		InvalidOffset:    "offset должен быть неотрицательным целым числом",
+		TagLength:        "Метка должна содержать от 1 до 50 символов, не больше 20 меток за раз",
+		InvalidTagsMatch: "Неверное значение tags_match, используйте any или all",
+
+		InvalidTransition: "Действие недоступно в текущем состоянии подписки",
+		StatusChanged:     "Состояние подписки изменилось, повторите запрос",
+
+		TrialOutsidePeriod: "trial_end_date должен попадать в период подписки",
+		InvalidDays:        "days должен быть положительным целым числом",
+
+		ChangeInPast:        "Прошлые месяцы изменить нельзя, укажите текущий или будущий месяц",
+		ChangeOutsidePeriod: "effective_from должен попадать в период подписки",
+		PriceMismatch:       "price должен равняться unit_price × quantity",
+
+		DiscountPercent:       "Процентная скидка должна быть от 1 до 100",
+		DiscountTerm:          "Укажите либо end_month, либо months",
+		DiscountOutsidePeriod: "Скидка должна попадать в период подписки",
+		DiscountNotFound:      "Скидка не найдена",
+		InvalidBreakdown:      "breakdown должен быть true или false",
+
+		BudgetScope:     "Для бюджета на категорию укажите только category, на сервис — только service_name, на все подписки — ни то, ни другое",
+		BudgetThreshold: "Пороги бюджета должны быть положительными процентами",
+		BudgetNotFound:  "Бюджет не найден",
+		BudgetConflict:  "Бюджет на эту область уже есть",
+
+		InvalidMonths: "months должен быть положительным целым числом",
+
+		MemberShare:    "Укажите либо share_weight, либо share_amount; владельцу подписки — только share_weight",
+		MemberNotFound: "Пользователь не участник подписки",
+		InvalidMonth:   "Неверный формат month",
+
+		OrganisationNotFound: "Организация не найдена",
+		OrganisationConflict: "Организация с таким названием уже есть",
+		CostCentreParent:     "Родителем узла может быть только подразделение",
+		CostCentreNotFound:   "Подразделение или центр затрат не найден",
+		CostCentreConflict:   "В организации уже есть узел с таким кодом",
+		CostCentreInUse:      "У узла есть дочерние подразделения или центры затрат",
+
+		TenantRequired:  "Укажите токен доступа арендатора",
+		TenantUnknown:   "Неверный токен доступа или неизвестный арендатор",
+		InvalidTenantID: "Неверный формат X-Tenant-ID",
+
+		Forbidden:                "Недостаточно прав для этого действия",
+		InvalidPrincipal:         "Неверный формат X-User-ID или X-User-Role",
+		OrganisationUserNotFound: "Пользователь не состоит в организации",
+
+		RateLimited: "Слишком много запросов, повторите позже",
+
+		InvalidAsOf: "Неверный формат as_of, используйте RFC 3339, например 2025-07-01T00:00:00Z",
+	},
+	EN: {
+		InvalidJSON:      "Invalid JSON format",
+		InvalidData:      "Invalid data",
+		InvalidID:        "Invalid ID format",
+		InvalidUserID:    "Invalid user_id format",
+		InvalidStartDate: "Invalid start_date format, use MM-YYYY",
+		InvalidEndDate:   "Invalid end_date format, use MM-YYYY",
+		NotFound:         "Subscription not found",
+		Internal:         "Internal server error",
+
+		EndBeforeStart:          "end_date must not be earlier than start_date",
+		ServiceNameLength:       "service_name must be between 2 and 100 characters long",
+		ServiceNameSymbols:      "service_name contains invalid characters",
+		OverlappingSubscription: "The user already has a subscription to this service for the given period",
+
+		BatchRolledBack: "Operation not applied: the batch was rolled back because another operation failed",
+
+		InvalidFormat:           "Invalid value format",
+		PricePositive:           "price must be greater than zero",
+		PriceFraction:           "price must be a whole number of roubles",
+		ImportMissingColumn:     "Required column %s is missing from the file",
+		ImportUnsupportedFormat: "Unsupported file format, use csv or ndjson",
+		ImportInvalidOptions:    "Invalid import parameters",
+
+		ExportUnsupportedFormat: "Unsupported export format, use csv, ndjson or xlsx",
+		ExportUnknownColumn:     "Unknown export column %s",
+
+		UserNotFound: "No data found for the user",
+
+		ServiceNotFound: "Service not found in the catalogue",
+		ServiceConflict: "The name or alias is already used by another service",
+
+		SearchQueryRequired: "Specify the search text in the q parameter",
+		InvalidLimit:        "limit must be a positive integer",
+
+		InvalidOffset:    "offset must be a non-negative integer",
+		TagLength:        "A tag must be 1 to 50 characters long, at most 20 tags at a time",
+		InvalidTagsMatch: "Invalid tags_match value, use any or all",
+
+		InvalidTransition: "The action is not allowed in the current subscription status",
+		StatusChanged:     "The subscription status has changed, retry the request",
+
+		TrialOutsidePeriod: "trial_end_date must be within the subscription period",
+		InvalidDays:        "days must be a positive integer",
+
+		ChangeInPast:        "Past months cannot be changed, use the current or a future month",
+		ChangeOutsidePeriod: "effective_from must be within the subscription period",
+		PriceMismatch:       "price must equal unit_price × quantity",
+
+		DiscountPercent:       "A percentage discount must be between 1 and 100",
+		DiscountTerm:          "Specify either end_month or months",
+		DiscountOutsidePeriod: "The discount must be within the subscription period",
+		DiscountNotFound:      "Discount not found",
+		InvalidBreakdown:      "breakdown must be true or false",
+
+		BudgetScope:     "A category budget needs only category, a service budget only service_name, an overall budget neither",
+		BudgetThreshold: "Budget thresholds must be positive percentages",
+		BudgetNotFound:  "Budget not found",
+		BudgetConflict:  "A budget for this scope already exists",
+
+		InvalidMonths: "months must be a positive integer",
+
+		MemberShare:    "Specify either share_weight or share_amount; the subscription owner may only have share_weight",
+		MemberNotFound: "The user is not a member of the subscription",
+		InvalidMonth:   "Invalid month format",
+
+		OrganisationNotFound: "Organisation not found",
+		OrganisationConflict: "An organisation with this name already exists",
+		CostCentreParent:     "Only a department can be the parent of a node",
+		CostCentreNotFound:   "Department or cost centre not found",
+		CostCentreConflict:   "The organisation already has a node with this code",
+		CostCentreInUse:      "The node has child departments or cost centres",
+
+		TenantRequired:  "A tenant access token is required",
+		TenantUnknown:   "Invalid access token or unknown tenant",
+		InvalidTenantID: "Invalid X-Tenant-ID format",
+
+		Forbidden:                "You are not allowed to perform this action",
+		InvalidPrincipal:         "Invalid X-User-ID or X-User-Role format",
+		OrganisationUserNotFound: "The user is not a member of the organisation",
+
+		RateLimited: "Too many requests, try again later",
+
+		InvalidAsOf: "Invalid as_of format, use RFC 3339, e.g. 2025-07-01T00:00:00Z",
+	},
+}
+
+// Translate возвращает сообщение на нужном языке. Если перевода нет,
+// используется язык по умолчанию, а если нет и его — сам ключ.
+func Translate(loc Locale, key Key, args ...any) string {
+	msg, ok := catalogue[loc][key]
+	if !ok {
+		msg, ok = catalogue[Default][key]
+	}
+	if !ok {
+		msg = string(key)
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// ParseAcceptLanguage выбирает наиболее предпочтительный поддерживаемый
+// язык из заголовка Accept-Language с учётом q-весов.
+func ParseAcceptLanguage(header string) Locale {
+	type candidate struct {
+		locale Locale
+		q      float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+
+		base, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		for _, loc := range Supported {
+			if Locale(base) == loc {
+				candidates = append(candidates, candidate{locale: loc, q: q})
+				break
+			}
+		}
+	}
+
+	if len(candidates) == 0 {
+		return Default
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+
+	return candidates[0].locale
+}
+
+type ctxKey struct{}
+
+// WithLocale сохраняет язык запроса в контексте.
+func WithLocale(ctx context.Context, loc Locale) context.Context {
+	return context.WithValue(ctx, ctxKey{}, loc)
+}
+
+// FromContext возвращает язык запроса или язык по умолчанию.
+func FromContext(ctx context.Context) Locale {
+	if loc, ok := ctx.Value(ctxKey{}).(Locale); ok {
+		return loc
+	}
+	return Default
+}
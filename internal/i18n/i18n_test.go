@@ -0,0 +1,42 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   Locale
+	}{
+		{name: "empty header", header: "", want: Default},
+		{name: "english", header: "en-US,en;q=0.9", want: EN},
+		{name: "russian", header: "ru-RU", want: RU},
+		{name: "q weights", header: "ru;q=0.5, en;q=0.8", want: EN},
+		{name: "unsupported falls back", header: "de-DE, fr;q=0.8", want: Default},
+		{name: "skips unsupported", header: "de, en;q=0.3", want: EN},
+		{name: "zero weight is ignored", header: "en;q=0, ru;q=0.1", want: RU},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseAcceptLanguage(tt.header))
+		})
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	assert.Equal(t, "Subscription not found", Translate(EN, NotFound))
+	assert.Equal(t, "Подписка не найдена", Translate(RU, NotFound))
+	assert.Equal(t, "Подписка не найдена", Translate(Locale("de"), NotFound))
+	assert.Equal(t, "unknown_key", Translate(EN, Key("unknown_key")))
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, Default, FromContext(context.Background()))
+	assert.Equal(t, EN, FromContext(WithLocale(context.Background(), EN)))
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BudgetScope — подписки, расходы на которые ограничивает бюджет.
+type BudgetScope string
+
+const (
+	// BudgetOverall ограничивает расходы на все подписки пользователя.
+	BudgetOverall BudgetScope = "overall"
+	// BudgetCategory ограничивает расходы на подписки одной категории каталога.
+	BudgetCategory BudgetScope = "category"
+	// BudgetService ограничивает расходы на подписки одного сервиса.
+	BudgetService BudgetScope = "service"
+)
+
+// Budget — месячный бюджет пользователя. Category заполняется только для
+// бюджета на категорию, ServiceName — только для бюджета на сервис.
+// Thresholds — доли Amount в процентах, при достижении которых
+// пользователь получает уведомление.
+type Budget struct {
+	ID          uuid.UUID   `json:"id" db:"id"`
+	UserID      uuid.UUID   `json:"user_id" db:"user_id"`
+	Scope       BudgetScope `json:"scope" db:"scope"`
+	Category    *string     `json:"category,omitempty" db:"category"`
+	ServiceName *string     `json:"service_name,omitempty" db:"service_name"`
+	Amount      int         `json:"amount" db:"amount"`
+	Thresholds  []int       `json:"thresholds" db:"thresholds"`
+	CreatedAt   time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at" db:"updated_at"`
+}
+
+// BudgetStatus — расходы в рамках бюджета за месяц Month. Spent — стоимость
+// подписок в этом месяце, Projected — прогноз на следующий месяц с учётом
+// запланированных изменений цен, мест и скидок. Проценты округляются вниз.
+type BudgetStatus struct {
+	Budget
+	Month             Month `json:"month" swaggertype:"string" example:"07-2025"`
+	Spent             int   `json:"spent"`
+	Remaining         int   `json:"remaining"`
+	SpentPercent      int   `json:"spent_percent"`
+	Projected         int   `json:"projected"`
+	ProjectedPercent  int   `json:"projected_percent"`
+	ReachedThresholds []int `json:"reached_thresholds"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DiscountKind — способ, которым скидка уменьшает цену месяца.
+type DiscountKind string
+
+const (
+	// DiscountPercent уменьшает цену месяца на Value процентов.
+	DiscountPercent DiscountKind = "percent"
+	// DiscountFixed уменьшает цену месяца на Value рублей.
+	DiscountFixed DiscountKind = "fixed"
+)
+
+// Discount — скидка на подписку, действующая с StartMonth по EndMonth
+// включительно. Без EndMonth скидка действует до конца подписки.
+type Discount struct {
+	ID             uuid.UUID    `json:"id" db:"id"`
+	SubscriptionID uuid.UUID    `json:"subscription_id" db:"subscription_id"`
+	Kind           DiscountKind `json:"kind" db:"kind"`
+	Value          int          `json:"value" db:"value"`
+	StartMonth     Month        `json:"start_month" db:"start_month" swaggertype:"string" example:"07-2025"`
+	EndMonth       *Month       `json:"end_month,omitempty" db:"end_month" swaggertype:"string" example:"09-2025"`
+	PromoCode      *string      `json:"promo_code,omitempty" db:"promo_code"`
+	CreatedAt      time.Time    `json:"created_at" db:"created_at"`
+}
+
+// SummaryTotals — стоимость подписок за период до и после скидок.
+type SummaryTotals struct {
+	Gross    int `json:"gross_price"`
+	Discount int `json:"discount"`
+	Net      int `json:"total_price"`
+}
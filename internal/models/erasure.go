@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErasureReceipt подтверждает удаление персональных данных пользователя.
+// Сам идентификатор пользователя в квитанции не хранится — только его HMAC
+// с ключом сервера, по которому квитанцию можно найти, зная user_id и ключ.
+type ErasureReceipt struct {
+	ID          uuid.UUID        `json:"id" db:"id"`
+	SubjectHash string           `json:"subject_hash" db:"subject_hash"`
+	ErasedAt    time.Time        `json:"erased_at" db:"erased_at"`
+	Affected    map[string]int64 `json:"affected" db:"affected"`
+}
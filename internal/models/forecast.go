@@ -0,0 +1,25 @@
+package models
+
+import "github.com/google/uuid"
+
+// MonthlySpend — стоимость подписок пользователя в одном месяце после скидок.
+type MonthlySpend struct {
+	UserID uuid.UUID `json:"-" db:"user_id"`
+	Month  Month     `json:"month" db:"month" swaggertype:"string" example:"08-2025"`
+	Amount int       `json:"amount" db:"amount"`
+}
+
+// UserForecast — прогноз расходов одного пользователя по месяцам.
+type UserForecast struct {
+	UserID uuid.UUID      `json:"user_id"`
+	Total  int            `json:"total"`
+	Months []MonthlySpend `json:"months"`
+}
+
+// Forecast — прогноз расходов на подписки с From по To включительно.
+type Forecast struct {
+	From  Month          `json:"from" swaggertype:"string" example:"08-2025"`
+	To    Month          `json:"to" swaggertype:"string" example:"07-2026"`
+	Total int            `json:"total"`
+	Users []UserForecast `json:"users"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SubscriptionMember — участник общей подписки. Участник возмещает владельцу
+// подписки либо фиксированную сумму в месяц (ShareAmount), либо долю
+// остатка пропорционально весу (ShareWeight). Для самого владельца
+// задаётся только вес.
+type SubscriptionMember struct {
+	SubscriptionID uuid.UUID `json:"subscription_id" db:"subscription_id"`
+	UserID         uuid.UUID `json:"user_id" db:"user_id"`
+	ShareWeight    *int      `json:"share_weight,omitempty" db:"share_weight"`
+	ShareAmount    *int      `json:"share_amount,omitempty" db:"share_amount"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
+
+// Settlement — сколько пользователь From должен пользователю To за месяц
+// по общим подпискам, после взаимозачёта встречных долгов.
+type Settlement struct {
+	From   uuid.UUID        `json:"from_user_id"`
+	To     uuid.UUID        `json:"to_user_id"`
+	Amount int              `json:"amount"`
+	Items  []SettlementItem `json:"items"`
+}
+
+// SettlementItem — доля участника в одной подписке. Долг в обратном
+// направлении, зачтённый в Settlement, имеет отрицательную сумму.
+type SettlementItem struct {
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	ServiceName    string    `json:"service_name"`
+	Amount         int       `json:"amount"`
+}
+
+// MemberShare — доля участника в стоимости общей подписки за месяц.
+type MemberShare struct {
+	SubscriptionID uuid.UUID
+	ServiceName    string
+	PayerID        uuid.UUID
+	UserID         uuid.UUID
+	Amount         int
+}
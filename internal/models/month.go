@@ -0,0 +1,97 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// monthLayout — формат, в котором месяц возвращается клиентам.
+const monthLayout = "01-2006"
+
+// monthInputLayouts перечисляет форматы, которые принимаются на вход.
+var monthInputLayouts = []string{
+	monthLayout,
+	"2006-01",
+	"2006-01-02",
+	time.RFC3339,
+}
+
+var ErrInvalidMonth = errors.New("неверный формат месяца, используйте MM-YYYY")
+
+// Month — календарный месяц. Подписки оплачиваются помесячно, поэтому
+// день и время не хранятся: месяц всегда представлен первым числом в UTC.
+type Month struct {
+	t time.Time
+}
+
+func NewMonth(year int, month time.Month) Month {
+	return Month{t: time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+// MonthOf возвращает месяц, в который попадает момент времени t.
+func MonthOf(t time.Time) Month {
+	return NewMonth(t.Year(), t.Month())
+}
+
+// ParseMonth разбирает месяц в формате MM-YYYY, YYYY-MM или полную дату.
+func ParseMonth(s string) (Month, error) {
+	for _, layout := range monthInputLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return MonthOf(t), nil
+		}
+	}
+	return Month{}, fmt.Errorf("%w: %q", ErrInvalidMonth, s)
+}
+
+func (m Month) Time() time.Time       { return m.t }
+func (m Month) Year() int             { return m.t.Year() }
+func (m Month) Month() time.Month     { return m.t.Month() }
+func (m Month) IsZero() bool          { return m.t.IsZero() }
+func (m Month) Before(o Month) bool   { return m.t.Before(o.t) }
+func (m Month) After(o Month) bool    { return m.t.After(o.t) }
+func (m Month) Equal(o Month) bool    { return m.t.Equal(o.t) }
+func (m Month) AddMonths(n int) Month { return Month{t: m.t.AddDate(0, n, 0)} }
+
+func (m Month) String() string {
+	if m.IsZero() {
+		return ""
+	}
+	return m.t.Format(monthLayout)
+}
+
+func (m Month) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *Month) UnmarshalText(text []byte) error {
+	parsed, err := ParseMonth(string(text))
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// ScanDate позволяет pgx читать месяц из колонки типа DATE.
+func (m *Month) ScanDate(v pgtype.Date) error {
+	if !v.Valid {
+		*m = Month{}
+		return nil
+	}
+	if v.InfinityModifier != pgtype.Finite {
+		return fmt.Errorf("месяц не может быть бесконечным")
+	}
+	*m = MonthOf(v.Time)
+	return nil
+}
+
+// DateValue позволяет pgx записывать месяц в колонку типа DATE.
+func (m Month) DateValue() (pgtype.Date, error) {
+	if m.IsZero() {
+		return pgtype.Date{}, nil
+	}
+	return pgtype.Date{Time: m.t, Valid: true}, nil
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMonth(t *testing.T) {
+	want := NewMonth(2025, time.July)
+
+	for _, input := range []string{"07-2025", "2025-07", "2025-07-15", "2025-07-31T23:00:00Z"} {
+		t.Run(input, func(t *testing.T) {
+			got, err := ParseMonth(input)
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+
+	for _, input := range []string{"", "7-2025", "13-2025", "2025/07", "july"} {
+		t.Run("invalid "+input, func(t *testing.T) {
+			_, err := ParseMonth(input)
+			assert.ErrorIs(t, err, ErrInvalidMonth)
+		})
+	}
+}
+
+func TestMonth_JSON(t *testing.T) {
+	var payload struct {
+		Start Month  `json:"start"`
+		End   *Month `json:"end"`
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(`{"start":"2025-07","end":null}`), &payload))
+	assert.Equal(t, NewMonth(2025, time.July), payload.Start)
+	assert.Nil(t, payload.End)
+
+	data, err := json.Marshal(payload)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"start":"07-2025","end":null}`, string(data))
+}
+
+func TestMonth_AddMonths(t *testing.T) {
+	assert.Equal(t, NewMonth(2026, time.February), NewMonth(2025, time.December).AddMonths(2))
+	assert.Equal(t, NewMonth(2025, time.November), NewMonth(2026, time.January).AddMonths(-2))
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationKind — повод, по которому пользователю отправляется уведомление.
+type NotificationKind string
+
+const (
+	// NotificationTrialEnding — пробный период подписки скоро закончится, и
+	// она станет платной.
+	NotificationTrialEnding NotificationKind = "trial_ending"
+	// NotificationBudgetThreshold — расходы месяца достигли порога бюджета.
+	NotificationBudgetThreshold NotificationKind = "budget_threshold"
+)
+
+// Notification — уведомление пользователя о событии подписки или бюджета.
+// DueAt — момент самого события, например начало первого оплачиваемого
+// месяца или месяца, в котором превышен порог бюджета.
+type Notification struct {
+	ID             uuid.UUID        `json:"id" db:"id"`
+	UserID         uuid.UUID        `json:"user_id" db:"user_id"`
+	SubscriptionID *uuid.UUID       `json:"subscription_id,omitempty" db:"subscription_id"`
+	BudgetID       *uuid.UUID       `json:"budget_id,omitempty" db:"budget_id"`
+	Threshold      *int             `json:"threshold,omitempty" db:"threshold"`
+	Kind           NotificationKind `json:"kind" db:"kind"`
+	DueAt          time.Time        `json:"due_at" db:"due_at"`
+	CreatedAt      time.Time        `json:"created_at" db:"created_at"`
+	// Subscription — подписка, о которой уведомление, для текста сообщения.
+	// В БД не хранится.
+	Subscription *Subscription `json:"subscription,omitempty" db:"-"`
+	// Budget — состояние бюджета, о котором уведомление. В БД не хранится.
+	Budget *BudgetStatus `json:"budget,omitempty" db:"-"`
+}
+
+// TrialConversion — подписка, пробный период которой заканчивается, и момент,
+// когда она станет платной.
+type TrialConversion struct {
+	Subscription
+	ConvertsAt time.Time `json:"converts_at"`
+}
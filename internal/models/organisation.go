@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Organisation struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// CostCentreKind — вид узла структуры организации.
+type CostCentreKind string
+
+const (
+	// CostCentreDepartment — подразделение, объединяющее другие узлы.
+	CostCentreDepartment CostCentreKind = "department"
+	// CostCentreCostCentre — центр затрат, на который относят подписки.
+	CostCentreCostCentre CostCentreKind = "cost_centre"
+)
+
+// CostCentre — узел структуры организации. Узлы без ParentID — верхний
+// уровень структуры. Code уникален внутри организации.
+type CostCentre struct {
+	ID             uuid.UUID      `json:"id" db:"id"`
+	OrganisationID uuid.UUID      `json:"organisation_id" db:"organisation_id"`
+	ParentID       *uuid.UUID     `json:"parent_id,omitempty" db:"parent_id"`
+	Kind           CostCentreKind `json:"kind" db:"kind"`
+	Code           string         `json:"code" db:"code"`
+	Name           string         `json:"name" db:"name"`
+	CreatedAt      time.Time      `json:"created_at" db:"created_at"`
+}
+
+// OrganisationUser — пользователь, состоящий в организации.
+type OrganisationUser struct {
+	OrganisationID uuid.UUID `json:"organisation_id" db:"organisation_id"`
+	UserID         uuid.UUID `json:"user_id" db:"user_id"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
+
+// ChargebackRow — расходы узла структуры за месяц. Direct — стоимость
+// подписок, отнесённых на сам узел, Total — вместе с подписками всех
+// дочерних узлов. Path — названия узлов от верхнего уровня через « / »,
+// Depth — уровень узла (0 у верхнего).
+type ChargebackRow struct {
+	Month        Month          `json:"month" swaggertype:"string" example:"07-2025"`
+	CostCentreID uuid.UUID      `json:"cost_centre_id"`
+	ParentID     *uuid.UUID     `json:"parent_id,omitempty"`
+	Kind         CostCentreKind `json:"kind"`
+	Code         string         `json:"code"`
+	Name         string         `json:"name"`
+	Path         string         `json:"path" example:"Engineering / Platform"`
+	Depth        int            `json:"depth"`
+	Direct       int            `json:"direct"`
+	Total        int            `json:"total"`
+}
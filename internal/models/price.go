@@ -0,0 +1,10 @@
+package models
+
+// PricePeriod — цена подписки, действующая с месяца EffectiveFrom до начала
+// следующего периода. Price = UnitPrice × Quantity.
+type PricePeriod struct {
+	EffectiveFrom Month `json:"effective_from" db:"effective_from" swaggertype:"string" example:"01-2026"`
+	UnitPrice     int   `json:"unit_price" db:"unit_price"`
+	Quantity      int   `json:"quantity" db:"quantity"`
+	Price         int   `json:"price" db:"price"`
+}
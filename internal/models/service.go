@@ -0,0 +1,24 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// Service — сервис из каталога. Подписки ссылаются на него, чтобы разные
+// написания одного сервиса («Yandex Plus», «Яндекс Плюс») считались вместе.
+type Service struct {
+	ID           uuid.UUID `json:"id" db:"id"`
+	Name         string    `json:"name" db:"name"`
+	Aliases      []string  `json:"aliases" db:"aliases"`
+	Category     *string   `json:"category,omitempty" db:"category"`
+	Website      *string   `json:"website,omitempty" db:"website"`
+	DefaultPrice *int      `json:"default_price,omitempty" db:"default_price"`
+	Currency     string    `json:"currency" db:"currency"`
+}
+
+// CategorySummary — суммарная стоимость подписок одной категории.
+// Category пуста для подписок, не привязанных к каталогу или без категории.
+type CategorySummary struct {
+	Category   *string `json:"category"`
+	TotalPrice int     `json:"total_price"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status — состояние подписки в её жизненном цикле.
+type Status string
+
+const (
+	StatusActive    Status = "active"
+	StatusPaused    Status = "paused"
+	StatusCancelled Status = "cancelled"
+	// StatusExpired не хранится в БД: активная или приостановленная подписка
+	// считается истёкшей, когда прошёл месяц её окончания.
+	StatusExpired Status = "expired"
+)
+
+// StatusChange — запись о переходе подписки из одного состояния в другое.
+type StatusChange struct {
+	ID             uuid.UUID `json:"id" db:"id"`
+	SubscriptionID uuid.UUID `json:"subscription_id" db:"subscription_id"`
+	FromStatus     Status    `json:"from_status" db:"from_status"`
+	ToStatus       Status    `json:"to_status" db:"to_status"`
+	// EffectiveMonth — месяц, с которого действует переход.
+	EffectiveMonth Month     `json:"effective_month" db:"effective_month" swaggertype:"string" example:"07-2025"`
+	ChangedAt      time.Time `json:"changed_at" db:"changed_at"`
+}
@@ -11,7 +11,26 @@ type Subscription struct {
 	ID          uuid.UUID  `json:"id" db:"id"`
 	UserID      uuid.UUID  `json:"user_id" db:"user_id"`
 	ServiceName string     `json:"service_name" db:"service_name"`
-	Price       int        `json:"price" db:"price"`
-	StartDate   time.Time  `json:"start_date" db:"start_date"`
-	EndDate     *time.Time `json:"end_date,omitempty" db:"end_date"`
+	ServiceID   *uuid.UUID `json:"service_id,omitempty" db:"service_id"`
+	// Price — цена текущего месяца: UnitPrice × Quantity.
+	Price     int    `json:"price" db:"price"`
+	UnitPrice int    `json:"unit_price" db:"unit_price"`
+	Quantity  int    `json:"quantity" db:"quantity"`
+	StartDate Month  `json:"start_date" db:"start_date" swaggertype:"string" example:"07-2025"`
+	EndDate   *Month `json:"end_date,omitempty" db:"end_date" swaggertype:"string" example:"12-2025"`
+	// TrialEndDate — последний бесплатный месяц пробного периода.
+	TrialEndDate *Month `json:"trial_end_date,omitempty" db:"trial_end_date" swaggertype:"string" example:"08-2025"`
+	Status       Status `json:"status" db:"status"`
+	// StatusChangedAt — время последнего перехода между состояниями.
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty" db:"status_changed_at"`
+	Tags            []string   `json:"tags,omitempty" db:"tags"`
+	// CostCentreID — центр затрат организации, на который относится подписка.
+	CostCentreID *uuid.UUID `json:"cost_centre_id,omitempty" db:"cost_centre_id"`
+}
+
+// SubscriptionMatch — подписка, найденная нечётким поиском, и степень
+// сходства её названия с запросом (от 0 до 1).
+type SubscriptionMatch struct {
+	Subscription
+	Score float64 `json:"score"`
 }
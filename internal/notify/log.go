@@ -0,0 +1,40 @@
+// Package notify содержит способы доставки уведомлений пользователям.
+package notify
+
+import (
+	"context"
+	"log/slog"
+
+	"effective-mobile-task/internal/models"
+)
+
+// LogNotifier пишет уведомления в журнал. Используется, пока не подключена
+// настоящая доставка (почта, push, вебхуки).
+type LogNotifier struct {
+	log *slog.Logger
+}
+
+func NewLogNotifier(log *slog.Logger) *LogNotifier {
+	return &LogNotifier{log: log}
+}
+
+func (n *LogNotifier) Notify(_ context.Context, notification models.Notification) error {
+	attrs := []any{
+		"id", notification.ID,
+		"user_id", notification.UserID,
+		"kind", notification.Kind,
+		"due_at", notification.DueAt,
+	}
+	if sub := notification.Subscription; sub != nil {
+		attrs = append(attrs, "subscription_id", sub.ID, "service_name", sub.ServiceName, "price", sub.Price)
+	}
+	if budget := notification.Budget; budget != nil {
+		attrs = append(attrs, "budget_id", budget.ID, "scope", budget.Scope, "amount", budget.Amount, "spent", budget.Spent)
+	}
+	if notification.Threshold != nil {
+		attrs = append(attrs, "threshold", *notification.Threshold)
+	}
+
+	n.log.Info("уведомление пользователю", attrs...)
+	return nil
+}
@@ -0,0 +1,184 @@
+// Package ratelimit ограничивает частоту запросов клиентов алгоритмом
+// token bucket: у каждого клиента на каждом маршруте есть ведро на
+// Limit.Requests токенов, которое равномерно наполняется за Limit.Period.
+// Запрос забирает токен; запрос к пустому ведру отклоняется.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit — не больше Requests запросов за Period. Нулевой лимит не
+// ограничивает запросы.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// IsZero сообщает, что лимит не ограничивает запросы.
+func (l Limit) IsZero() bool {
+	return l.Requests <= 0 || l.Period <= 0
+}
+
+// rate — сколько токенов добавляется в ведро за секунду.
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+func (l Limit) String() string {
+	return fmt.Sprintf("%d/%s", l.Requests, l.Period)
+}
+
+// ParseLimit разбирает лимит вида «100/1m» или «10/s». Пустая строка —
+// нулевой лимит.
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Limit{}, nil
+	}
+
+	requests, period, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("лимит %q: ожидается формат N/период", s)
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(requests))
+	if err != nil || n < 0 {
+		return Limit{}, fmt.Errorf("лимит %q: неверное число запросов", s)
+	}
+
+	period = strings.TrimSpace(period)
+	if period != "" && !strings.ContainsAny(period[:1], "0123456789") {
+		period = "1" + period
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("лимит %q: неверный период", s)
+	}
+
+	return Limit{Requests: n, Period: d}, nil
+}
+
+// ParseRoutes разбирает лимиты маршрутов вида
+// «GET /subscriptions/summary=10/1m, /subscriptions/search=30/1m». Маршрут —
+// шаблон chi, перед ним может стоять HTTP-метод.
+func ParseRoutes(s string) (map[string]Limit, error) {
+	routes := make(map[string]Limit)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		route, raw, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("лимит маршрута %q: ожидается формат маршрут=N/период", item)
+		}
+		limit, err := ParseLimit(raw)
+		if err != nil {
+			return nil, err
+		}
+		routes[routeKey(strings.Fields(route))] = limit
+	}
+
+	return routes, nil
+}
+
+// routeKey собирает ключ маршрута: «GET /path» или «/path».
+func routeKey(parts []string) string {
+	if len(parts) == 2 {
+		parts[0] = strings.ToUpper(parts[0])
+	}
+	return strings.Join(parts, " ")
+}
+
+// Store хранит вёдра клиентов. Take добавляет в ведро key токены,
+// накопившиеся с прошлого запроса, и забирает один, если он есть.
+// Возвращает, сколько токенов осталось, и разрешён ли запрос.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (tokens float64, allowed bool, err error)
+}
+
+// Decision — решение по запросу и данные для заголовков RateLimit-*.
+type Decision struct {
+	Allowed   bool
+	Limit     Limit
+	Remaining int
+	// Reset — через сколько ведро наполнится целиком.
+	Reset time.Duration
+	// RetryAfter — через сколько появится токен для отклонённого запроса.
+	RetryAfter time.Duration
+}
+
+// Limiter подбирает лимит маршрута и проверяет запросы по нему.
+type Limiter struct {
+	store  Store
+	def    Limit
+	routes map[string]Limit
+}
+
+// New создаёт Limiter. def действует на маршрутах, для которых в routes нет
+// своего лимита.
+func New(store Store, def Limit, routes map[string]Limit) *Limiter {
+	return &Limiter{store: store, def: def, routes: routes}
+}
+
+// LimitFor возвращает лимит маршрута: сначала с методом, затем без него,
+// затем лимит по умолчанию.
+func (l *Limiter) LimitFor(method, pattern string) Limit {
+	if limit, ok := l.routes[method+" "+pattern]; ok {
+		return limit
+	}
+	if limit, ok := l.routes[pattern]; ok {
+		return limit
+	}
+	return l.def
+}
+
+// MaxPeriod возвращает самый длинный период среди лимитов. Ведро, к
+// которому не обращались дольше, уже наполнилось целиком.
+func (l *Limiter) MaxPeriod() time.Duration {
+	longest := l.def.Period
+	for _, limit := range l.routes {
+		longest = max(longest, limit.Period)
+	}
+	return longest
+}
+
+// Allow забирает токен из ведра клиента client на маршруте pattern. Если
+// лимита у маршрута нет, запрос разрешается без обращения к хранилищу, а
+// Decision.Limit остаётся нулевым.
+func (l *Limiter) Allow(ctx context.Context, client, method, pattern string) (Decision, error) {
+	limit := l.LimitFor(method, pattern)
+	if limit.IsZero() {
+		return Decision{Allowed: true}, nil
+	}
+
+	tokens, allowed, err := l.store.Take(ctx, client+"|"+method+" "+pattern, limit)
+	if err != nil {
+		return Decision{}, err
+	}
+
+	d := Decision{
+		Allowed:   allowed,
+		Limit:     limit,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     seconds((float64(limit.Requests) - tokens) / limit.rate()),
+	}
+	if !allowed {
+		d.RetryAfter = seconds((1 - tokens) / limit.rate())
+	}
+	return d, nil
+}
+
+func seconds(s float64) time.Duration {
+	if s <= 0 {
+		return 0
+	}
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Limit
+		wantErr bool
+	}{
+		{in: "", want: Limit{}},
+		{in: "100/1m", want: Limit{Requests: 100, Period: time.Minute}},
+		{in: "10/s", want: Limit{Requests: 10, Period: time.Second}},
+		{in: " 5 / 30s ", want: Limit{Requests: 5, Period: 30 * time.Second}},
+		{in: "100", wantErr: true},
+		{in: "x/1m", wantErr: true},
+		{in: "10/0s", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseLimit(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLimiter_LimitFor(t *testing.T) {
+	routes, err := ParseRoutes("get /subscriptions/summary=10/1m, /subscriptions/search=30/1m")
+	require.NoError(t, err)
+
+	l := New(NewMemoryStore(), Limit{Requests: 100, Period: time.Minute}, routes)
+
+	assert.Equal(t, 10, l.LimitFor("GET", "/subscriptions/summary").Requests)
+	assert.Equal(t, 100, l.LimitFor("POST", "/subscriptions/summary").Requests)
+	assert.Equal(t, 30, l.LimitFor("GET", "/subscriptions/search").Requests)
+	assert.Equal(t, 100, l.LimitFor("GET", "/subscriptions").Requests)
+}
+
+func TestLimiter_Allow_TokenBucket(t *testing.T) {
+	now := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	l := New(store, Limit{}, map[string]Limit{"/summary": {Requests: 2, Period: 10 * time.Second}})
+	ctx := context.Background()
+
+	d, err := l.Allow(ctx, "ip:1", "GET", "/summary")
+	require.NoError(t, err)
+	assert.True(t, d.Allowed)
+	assert.Equal(t, 1, d.Remaining)
+	assert.Equal(t, 5*time.Second, d.Reset)
+
+	d, _ = l.Allow(ctx, "ip:1", "GET", "/summary")
+	assert.True(t, d.Allowed)
+	assert.Equal(t, 0, d.Remaining)
+
+	d, _ = l.Allow(ctx, "ip:1", "GET", "/summary")
+	assert.False(t, d.Allowed)
+	assert.Equal(t, 5*time.Second, d.RetryAfter)
+
+	// У другого клиента своё ведро.
+	d, _ = l.Allow(ctx, "ip:2", "GET", "/summary")
+	assert.True(t, d.Allowed)
+
+	// За 5 секунд в ведро возвращается один токен.
+	now = now.Add(5 * time.Second)
+	d, _ = l.Allow(ctx, "ip:1", "GET", "/summary")
+	assert.True(t, d.Allowed)
+	assert.Equal(t, 0, d.Remaining)
+}
+
+func TestLimiter_Allow_Unlimited(t *testing.T) {
+	l := New(NewMemoryStore(), Limit{}, nil)
+
+	d, err := l.Allow(context.Background(), "ip:1", "GET", "/subscriptions")
+
+	require.NoError(t, err)
+	assert.True(t, d.Allowed)
+	assert.True(t, d.Limit.IsZero())
+}
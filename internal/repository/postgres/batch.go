@@ -0,0 +1,281 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"effective-mobile-task/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrBatchRolledBack помечает операции пакета, которые выполнились успешно,
+// но были отменены из-за ошибки в другой операции.
+var ErrBatchRolledBack = errors.New("операция отменена вместе с пакетом")
+
+type BatchOperationType string
+
+const (
+	BatchCreate BatchOperationType = "create"
+	BatchUpdate BatchOperationType = "update"
+	BatchDelete BatchOperationType = "delete"
+)
+
+// BatchOperation — одна операция пакета. Для create и update заполняется
+// Subscription, для delete — ID.
+type BatchOperation struct {
+	Type         BatchOperationType
+	ID           uuid.UUID
+	Subscription *models.Subscription
+}
+
+var subscriptionColumns = []string{"id", "user_id", "service_name", "service_id", "unit_price", "quantity", "start_date", "end_date", "trial_end_date", "status"}
+
+// subscriptionValues возвращает значения подписки в порядке subscriptionColumns.
+func subscriptionValues(sub *models.Subscription) []any {
+	status := sub.Status
+	if status == "" {
+		status = models.StatusActive
+	}
+	unitPrice, quantity := sub.UnitPrice, sub.Quantity
+	if quantity == 0 {
+		unitPrice, quantity = sub.Price, 1
+	}
+	return []any{sub.ID, sub.UserID, sub.ServiceName, sub.ServiceID, unitPrice, quantity, sub.StartDate, sub.EndDate, sub.TrialEndDate, status}
+}
+
+// subscriptionSelectColumns — колонки для чтения подписки. В отличие от
+// subscriptionColumns, цена места и число мест берутся действующие в текущем
+// месяце, состояние вычисляется с учётом истечения срока, а метки собираются
+// из subscription_tags.
+var subscriptionSelectColumns = []string{
+	"id", "user_id", "service_name", "service_id",
+	unitPriceHistory.at(priceMonth) + " AS unit_price",
+	seatsHistory.at(priceMonth) + " AS quantity",
+	"start_date", "end_date", "trial_end_date",
+	effectiveStatus + " AS status",
+	"status_changed_at",
+	"ARRAY(SELECT t.name FROM subscription_tags st JOIN tags t ON t.id = st.tag_id" +
+		" WHERE st.subscription_id = subscriptions.id ORDER BY t.name) AS tags",
+	"cost_centre_id",
+}
+
+// effectiveStatus — состояние подписки с учётом того, что после месяца
+// окончания активная или приостановленная подписка считается истёкшей.
+const effectiveStatus = "CASE WHEN subscriptions.status IN ('active', 'paused')" +
+	" AND subscriptions.end_date < " + currentMonth +
+	" THEN 'expired' ELSE subscriptions.status END"
+
+// scanSubscription читает строку, выбранную по subscriptionSelectColumns.
+func scanSubscription(row pgx.Row, sub *models.Subscription, extra ...any) error {
+	dest := append([]any{
+		&sub.ID, &sub.UserID, &sub.ServiceName, &sub.ServiceID, &sub.UnitPrice, &sub.Quantity, &sub.StartDate, &sub.EndDate, &sub.TrialEndDate,
+		&sub.Status, &sub.StatusChangedAt, &sub.Tags, &sub.CostCentreID,
+	}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return err
+	}
+	sub.Price = sub.UnitPrice * sub.Quantity
+	return nil
+}
+
+// ApplyBatch выполняет операции в одной транзакции и возвращает ошибку для
+// каждой операции в том же порядке.
+//
+// В атомарном режиме первая же ошибка откатывает весь пакет: подряд идущие
+// вставки выполняются одним COPY, обновления и удаления — одним pgx.Batch.
+// В режиме best effort каждая операция выполняется в своей точке сохранения,
+// и ошибка одной из них не влияет на остальные.
+func (r *SubscriptionRepository) ApplyBatch(ctx context.Context, ops []BatchOperation, atomic bool) ([]error, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.ApplyBatch - Begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var results []error
+	if atomic {
+		results = r.applyAtomic(ctx, tx, ops)
+	} else {
+		results = r.applyBestEffort(ctx, tx, ops)
+	}
+
+	if atomic && hasErrors(results) {
+		for i := range results {
+			if results[i] == nil {
+				results[i] = ErrBatchRolledBack
+			}
+		}
+		return results, nil
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.ApplyBatch - Commit: %w", err)
+	}
+
+	return results, nil
+}
+
+func (r *SubscriptionRepository) applyAtomic(ctx context.Context, tx pgx.Tx, ops []BatchOperation) []error {
+	results := make([]error, len(ops))
+
+	for start := 0; start < len(ops); {
+		end := start + 1
+		for end < len(ops) && (ops[end].Type == BatchCreate) == (ops[start].Type == BatchCreate) {
+			end++
+		}
+
+		var err error
+		if ops[start].Type == BatchCreate {
+			err = r.copySubscriptions(ctx, tx, ops[start:end])
+		} else {
+			err = r.sendModifications(ctx, tx, ops[start:end], results[start:end])
+		}
+		if err != nil {
+			for i := start; i < end; i++ {
+				if results[i] == nil {
+					results[i] = err
+				}
+			}
+			return results
+		}
+		if hasErrors(results[start:end]) {
+			return results
+		}
+
+		start = end
+	}
+
+	return results
+}
+
+func (r *SubscriptionRepository) copySubscriptions(ctx context.Context, tx pgx.Tx, ops []BatchOperation) error {
+	rows := make([][]any, 0, len(ops))
+	for _, op := range ops {
+		rows = append(rows, subscriptionValues(op.Subscription))
+	}
+
+	if _, err := copySubscriptions(ctx, tx, pgx.CopyFromRows(rows)); err != nil {
+		return fmt.Errorf("SubscriptionRepository.ApplyBatch - %w", err)
+	}
+
+	return nil
+}
+
+// copySubscriptions вставляет подписки из src одним COPY. COPY FROM в
+// таблицы под политиками RLS PostgreSQL не поддерживает, поэтому строки
+// копируются во временную таблицу и переносятся в subscriptions одним
+// INSERT: арендатор подставляется значением tenant_id по умолчанию, и
+// политика проверяет каждую строку.
+func copySubscriptions(ctx context.Context, tx pgx.Tx, src pgx.CopyFromSource) (int64, error) {
+	columns := strings.Join(subscriptionColumns, ", ")
+	if _, err := tx.Exec(ctx, "CREATE TEMP TABLE subscriptions_staging ON COMMIT DROP AS SELECT "+columns+" FROM subscriptions WITH NO DATA"); err != nil {
+		return 0, fmt.Errorf("Staging: %w", err)
+	}
+
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"subscriptions_staging"}, subscriptionColumns, src); err != nil {
+		return 0, fmt.Errorf("CopyFrom: %w", err)
+	}
+
+	res, err := tx.Exec(ctx, "INSERT INTO subscriptions ("+columns+") SELECT "+columns+" FROM subscriptions_staging")
+	if err != nil {
+		return 0, fmt.Errorf("Insert: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, "DROP TABLE subscriptions_staging"); err != nil {
+		return 0, fmt.Errorf("Staging: %w", err)
+	}
+
+	return res.RowsAffected(), nil
+}
+
+// sendModifications отправляет обновления и удаления одним pgx.Batch и
+// записывает результат каждой операции в results.
+func (r *SubscriptionRepository) sendModifications(ctx context.Context, tx pgx.Tx, ops []BatchOperation, results []error) error {
+	batch := &pgx.Batch{}
+	for _, op := range ops {
+		var (
+			sql  string
+			args []any
+			err  error
+		)
+		switch op.Type {
+		case BatchUpdate:
+			sql, args, err = r.updateQuery(op.Subscription)
+		case BatchDelete:
+			sql, args, err = r.deleteQuery(op.ID)
+		default:
+			err = fmt.Errorf("неизвестный тип операции %q", op.Type)
+		}
+		if err != nil {
+			return fmt.Errorf("SubscriptionRepository.ApplyBatch - ToSql: %w", err)
+		}
+		batch.Queue(sql, args...)
+	}
+
+	br := tx.SendBatch(ctx, batch)
+	defer br.Close()
+
+	for i := range ops {
+		res, err := br.Exec()
+		if err != nil {
+			results[i] = fmt.Errorf("SubscriptionRepository.ApplyBatch - Exec: %w", err)
+			return nil
+		}
+		if res.RowsAffected() == 0 {
+			results[i] = ErrNotFound
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (r *SubscriptionRepository) applyBestEffort(ctx context.Context, tx pgx.Tx, ops []BatchOperation) []error {
+	results := make([]error, len(ops))
+
+	for i, op := range ops {
+		results[i] = r.applyInSavepoint(ctx, tx, op)
+	}
+
+	return results
+}
+
+func (r *SubscriptionRepository) applyInSavepoint(ctx context.Context, tx pgx.Tx, op BatchOperation) error {
+	sp, err := tx.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("SubscriptionRepository.ApplyBatch - Savepoint: %w", err)
+	}
+	defer sp.Rollback(ctx)
+
+	switch op.Type {
+	case BatchCreate:
+		err = r.create(ctx, sp, op.Subscription)
+	case BatchUpdate:
+		err = r.update(ctx, sp, op.Subscription)
+	case BatchDelete:
+		err = r.delete(ctx, sp, op.ID)
+	default:
+		err = fmt.Errorf("неизвестный тип операции %q", op.Type)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := sp.Commit(ctx); err != nil {
+		return fmt.Errorf("SubscriptionRepository.ApplyBatch - Release: %w", err)
+	}
+
+	return nil
+}
+
+func hasErrors(errs []error) bool {
+	for _, err := range errs {
+		if err != nil {
+			return true
+		}
+	}
+	return false
+}
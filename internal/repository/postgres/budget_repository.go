@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"effective-mobile-task/internal/models"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	ErrBudgetNotFound = errors.New("budget not found")
+	// ErrBudgetConflict означает, что у пользователя уже есть бюджет на ту же
+	// область.
+	ErrBudgetConflict = errors.New("budget for this scope already exists")
+)
+
+var budgetColumns = []string{"id", "user_id", "scope", "category", "service_name", "amount", "thresholds", "created_at", "updated_at"}
+
+type BudgetRepository struct {
+	db  *DB
+	sqb sq.StatementBuilderType
+}
+
+func NewBudgetRepository(db *DB) *BudgetRepository {
+	return &BudgetRepository{
+		db:  db,
+		sqb: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *BudgetRepository) Create(ctx context.Context, b *models.Budget) error {
+	sql, args, err := r.sqb.Insert("budgets").
+		Columns("id", "user_id", "scope", "category", "service_name", "amount", "thresholds").
+		Values(b.ID, b.UserID, b.Scope, b.Category, b.ServiceName, b.Amount, b.Thresholds).
+		Suffix("RETURNING created_at, updated_at").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("BudgetRepository.Create - ToSql: %w", err)
+	}
+
+	if err := r.db.QueryRow(ctx, sql, args...).Scan(&b.CreatedAt, &b.UpdatedAt); err != nil {
+		if isUniqueViolation(err) {
+			return ErrBudgetConflict
+		}
+		return fmt.Errorf("BudgetRepository.Create - Scan: %w", err)
+	}
+
+	return nil
+}
+
+// List возвращает бюджеты пользователя. Без userID возвращаются бюджеты всех
+// пользователей.
+func (r *BudgetRepository) List(ctx context.Context, userID *uuid.UUID) ([]models.Budget, error) {
+	queryBuilder := r.sqb.Select(budgetColumns...).
+		From("budgets").
+		OrderBy("user_id", "scope", "COALESCE(category, service_name)")
+	if userID != nil {
+		queryBuilder = queryBuilder.Where(sq.Eq{"user_id": *userID})
+	}
+
+	sql, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("BudgetRepository.List - ToSql: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("BudgetRepository.List - Query: %w", err)
+	}
+
+	budgets, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Budget, error) {
+		var b models.Budget
+		err := scanBudget(row, &b)
+		return b, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("BudgetRepository.List - Scan: %w", err)
+	}
+
+	return budgets, nil
+}
+
+// Update меняет бюджет пользователя b.UserID. Если такого бюджета у
+// пользователя нет, возвращает ErrBudgetNotFound.
+func (r *BudgetRepository) Update(ctx context.Context, b *models.Budget) error {
+	sql, args, err := r.sqb.Update("budgets").
+		Set("scope", b.Scope).
+		Set("category", b.Category).
+		Set("service_name", b.ServiceName).
+		Set("amount", b.Amount).
+		Set("thresholds", b.Thresholds).
+		Set("updated_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": b.ID, "user_id": b.UserID}).
+		Suffix("RETURNING created_at, updated_at").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("BudgetRepository.Update - ToSql: %w", err)
+	}
+
+	if err := r.db.QueryRow(ctx, sql, args...).Scan(&b.CreatedAt, &b.UpdatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrBudgetNotFound
+		}
+		if isUniqueViolation(err) {
+			return ErrBudgetConflict
+		}
+		return fmt.Errorf("BudgetRepository.Update - Scan: %w", err)
+	}
+
+	return nil
+}
+
+func (r *BudgetRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	sql, args, err := r.sqb.Delete("budgets").
+		Where(sq.Eq{"id": id, "user_id": userID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("BudgetRepository.Delete - ToSql: %w", err)
+	}
+
+	res, err := r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("BudgetRepository.Delete - Exec: %w", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrBudgetNotFound
+	}
+
+	return nil
+}
+
+func scanBudget(row pgx.Row, b *models.Budget) error {
+	return row.Scan(&b.ID, &b.UserID, &b.Scope, &b.Category, &b.ServiceName, &b.Amount, &b.Thresholds, &b.CreatedAt, &b.UpdatedAt)
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"effective-mobile-task/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// chargebackTree — обход структуры организации: closure связывает каждый
+// узел с ним самим и всеми его потомками, paths — путь узла от верхнего
+// уровня и ключ сортировки, при котором дочерние узлы идут сразу за
+// родительским.
+const chargebackTree = "WITH RECURSIVE closure(ancestor_id, descendant_id) AS (" +
+	"SELECT id, id FROM cost_centres WHERE organisation_id = ?" +
+	" UNION ALL SELECT cl.ancestor_id, cc.id FROM closure cl JOIN cost_centres cc ON cc.parent_id = cl.descendant_id" +
+	"), paths(id, path, sort_key, depth) AS (" +
+	"SELECT id, name::text, ARRAY[code::text], 0 FROM cost_centres WHERE organisation_id = ? AND parent_id IS NULL" +
+	" UNION ALL SELECT cc.id, p.path || ' / ' || cc.name, p.sort_key || cc.code::text, p.depth + 1" +
+	" FROM paths p JOIN cost_centres cc ON cc.parent_id = p.id)"
+
+// SetCostCentre относит подписку на узел структуры организации или, если
+// costCentreID nil, снимает её с узла. Если подписки нет, возвращает
+// ErrNotFound, если нет узла — ErrCostCentreNotFound.
+func (r *SubscriptionRepository) SetCostCentre(ctx context.Context, id uuid.UUID, costCentreID *uuid.UUID) error {
+	sql, args, err := r.sqb.Update("subscriptions").
+		Set("cost_centre_id", costCentreID).
+		Where("id = ?", id).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("SubscriptionRepository.SetCostCentre - ToSql: %w", err)
+	}
+
+	res, err := r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return ErrCostCentreNotFound
+		}
+		return fmt.Errorf("SubscriptionRepository.SetCostCentre - Exec: %w", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Chargeback считает расходы на подписки по узлам структуры организации
+// orgID и месяцам периода фильтра: стоимость подписок, отнесённых на сам
+// узел, и вместе с подписками всех его потомков. Стоимость считается так же,
+// как в сводке, — со скидками, без пробных месяцев и приостановок. Узлы без
+// расходов в месяце не возвращаются.
+func (r *SubscriptionRepository) Chargeback(ctx context.Context, orgID uuid.UUID, filter GetSummaryFilter) ([]models.ChargebackRow, error) {
+	sql, args, err := r.sqb.Select(
+		"c.month",
+		"cc.id", "cc.parent_id", "cc.kind", "cc.code", "cc.name",
+		"p.path", "p.depth",
+		"COALESCE(SUM(c.amount) FILTER (WHERE cl.descendant_id = cc.id), 0)::integer",
+		"SUM(c.amount)::integer",
+	).
+		Prefix(chargebackTree, orgID, orgID).
+		FromSelect(r.monthlyCosts(filter), "c").
+		Join("subscriptions s ON s.id = c.subscription_id").
+		Join("closure cl ON cl.descendant_id = s.cost_centre_id").
+		Join("cost_centres cc ON cc.id = cl.ancestor_id").
+		Join("paths p ON p.id = cc.id").
+		GroupBy("c.month", "cc.id", "p.path", "p.sort_key", "p.depth").
+		OrderBy("c.month", "p.sort_key").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.Chargeback - ToSql: %w", err)
+	}
+
+	reader, done, err := r.snapshot(ctx, filter.AsOf)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	rows, err := reader.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.Chargeback - Query: %w", err)
+	}
+
+	report, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.ChargebackRow, error) {
+		var c models.ChargebackRow
+		err := row.Scan(&c.Month, &c.CostCentreID, &c.ParentID, &c.Kind, &c.Code, &c.Name, &c.Path, &c.Depth, &c.Direct, &c.Total)
+		return c, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.Chargeback - Scan: %w", err)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"effective-mobile-task/internal/models"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+)
+
+// monthlyCosts разворачивает подписки, подходящие под фильтр, в оплачиваемые
+// месяцы: одна строка — доля одного пользователя в одном месяце одной
+// подписки, её стоимость по цене, действовавшей в этом месяце (gross), и
+// стоимость после скидок (amount). Для подписки без участников строка одна —
+// владельца (payer_id = user_id) со всей стоимостью месяца.
+//
+// Даты фильтра задают период расчёта: учитываются месяцы с filter.StartDate
+// по filter.EndDate включительно. Без EndDate период заканчивается текущим
+// месяцем, без StartDate — начинается с первого месяца подписки. Месяцы
+// пробного периода и приостановки не оплачиваются. Фильтр по пользователю
+// отбирает его доли: и в своих подписках, и в общих подписках, где он
+// участник.
+//
+// Скидки, действующие в одном месяце, складываются: сначала вычитаются
+// проценты (в сумме не больше 100), затем фиксированные суммы. Стоимость
+// месяца со скидкой не бывает отрицательной.
+func (r *SubscriptionRepository) monthlyCosts(filter GetSummaryFilter) sq.SelectBuilder {
+	periodStart, periodEnd := filter.StartDate, filter.EndDate
+	userID := filter.UserID
+	filter.StartDate, filter.EndDate, filter.UserID = nil, nil, nil
+
+	const periodEndExpr = "COALESCE(?::date, " + currentMonth + ")"
+
+	billedBuilder := r.sqb.Select(
+		"subscriptions.id AS subscription_id",
+		"subscriptions.user_id",
+		"subscriptions.service_id",
+		"m.month::date AS month",
+		priceAt("m.month::date")+" AS gross",
+		"d.discount_percent",
+		"d.discount_fixed",
+	).
+		From("subscriptions").
+		JoinClause("CROSS JOIN LATERAL generate_series("+
+			"GREATEST(subscriptions.start_date, ?::date)::timestamp, "+
+			"LEAST(COALESCE(subscriptions.end_date, "+periodEndExpr+"), "+periodEndExpr+")::timestamp, "+
+			"interval '1 month') AS m(month)",
+			periodStart, periodEnd, periodEnd).
+		JoinClause("CROSS JOIN LATERAL (SELECT" +
+			" LEAST(COALESCE(SUM(sd.value) FILTER (WHERE sd.kind = 'percent'), 0), 100) AS discount_percent," +
+			" COALESCE(SUM(sd.value) FILTER (WHERE sd.kind = 'fixed'), 0) AS discount_fixed" +
+			" FROM subscription_discounts sd WHERE sd.subscription_id = subscriptions.id" +
+			" AND sd.start_month <= m.month AND (sd.end_month IS NULL OR sd.end_month >= m.month)) AS d").
+		Where("(subscriptions.trial_end_date IS NULL OR m.month > subscriptions.trial_end_date)").
+		Where("NOT EXISTS (SELECT 1 FROM subscription_pauses p" +
+			" WHERE p.subscription_id = subscriptions.id AND p.start_month <= m.month" +
+			" AND (p.end_month IS NULL OR p.end_month > m.month))")
+	if userID != nil {
+		billedBuilder = billedBuilder.Where("(subscriptions.user_id = ? OR subscriptions.id IN"+
+			" (SELECT sm.subscription_id FROM subscription_members sm WHERE sm.user_id = ?))", *userID, *userID)
+	}
+	billed := applySummaryFilter(billedBuilder, filter)
+
+	costs := r.sqb.Select(
+		"b.subscription_id",
+		"b.user_id",
+		"b.service_id",
+		"b.month",
+		"b.gross",
+		"GREATEST(b.gross - round(b.gross * b.discount_percent / 100.0)::integer - b.discount_fixed, 0)::integer AS amount",
+	).FromSelect(billed, "b")
+
+	shares := r.sqb.Select(
+		"c.subscription_id",
+		"c.user_id AS payer_id",
+		"s.user_id",
+		"c.service_id",
+		"c.month",
+		"s.gross",
+		"s.amount",
+	).
+		FromSelect(costs, "c").
+		JoinClause("CROSS JOIN LATERAL " + memberShares + " AS s")
+	if userID != nil {
+		shares = shares.Where(sq.Eq{"s.user_id": *userID})
+	}
+
+	return shares
+}
+
+// memberShares делит стоимость месяца подписки c между её владельцем и
+// участниками. Фиксированные суммы участников вычитаются первыми (по
+// порядку user_id, пока хватает стоимости месяца), остаток делится
+// пропорционально весам участников и владельца с округлением вниз, а
+// владелец получает всё, что не досталось участникам.
+var memberShares = "(WITH members AS (" +
+	"SELECT sm.user_id, sm.share_weight, sm.share_amount," +
+	" COALESCE(SUM(sm.share_amount) OVER (ORDER BY sm.user_id ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING), 0) AS fixed_before," +
+	" COALESCE(SUM(sm.share_amount) OVER (), 0) AS fixed_total," +
+	" COALESCE(SUM(sm.share_weight) OVER (), 0) + COALESCE((SELECT o.share_weight FROM subscription_members o" +
+	" WHERE o.subscription_id = c.subscription_id AND o.user_id = c.user_id), 1) AS weight_total" +
+	" FROM subscription_members sm WHERE sm.subscription_id = c.subscription_id AND sm.user_id <> c.user_id" +
+	"), shares AS (" +
+	"SELECT user_id, " + memberShare("c.gross") + " AS gross, " + memberShare("c.amount") + " AS amount FROM members" +
+	") SELECT user_id, gross, amount FROM shares" +
+	" UNION ALL SELECT c.user_id," +
+	" c.gross - COALESCE((SELECT SUM(gross) FROM shares), 0)::integer," +
+	" c.amount - COALESCE((SELECT SUM(amount) FROM shares), 0)::integer)"
+
+// memberShare — доля участника из members в стоимости total.
+func memberShare(total string) string {
+	return "(CASE WHEN share_amount IS NOT NULL" +
+		" THEN LEAST(share_amount, GREATEST(" + total + " - fixed_before, 0))" +
+		" ELSE GREATEST(" + total + " - fixed_total, 0) * share_weight / weight_total END)::integer"
+}
+
+// MonthlySpend считает стоимость подписок каждого пользователя по месяцам
+// периода фильтра. Период может лежать в будущем: тогда учитываются
+// запланированные изменения цен, мест и скидок, а также даты окончания
+// подписок. Месяцы без оплачиваемых подписок не возвращаются.
+func (r *SubscriptionRepository) MonthlySpend(ctx context.Context, filter GetSummaryFilter) ([]models.MonthlySpend, error) {
+	sql, args, err := r.sqb.Select("c.user_id", "c.month", "SUM(c.amount)").
+		FromSelect(r.monthlyCosts(filter), "c").
+		GroupBy("c.user_id", "c.month").
+		OrderBy("c.user_id", "c.month").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.MonthlySpend - ToSql: %w", err)
+	}
+
+	reader, done, err := r.snapshot(ctx, filter.AsOf)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	rows, err := reader.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.MonthlySpend - Query: %w", err)
+	}
+
+	spend, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.MonthlySpend, error) {
+		var s models.MonthlySpend
+		err := row.Scan(&s.UserID, &s.Month, &s.Amount)
+		return s, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.MonthlySpend - Scan: %w", err)
+	}
+
+	return spend, nil
+}
@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"effective-mobile-task/internal/config"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+)
+
+// testDB подключается к базе из TEST_DATABASE_URL, к которой применены все
+// миграции. Подключаться нужно ролью приложения, а не владельцем таблиц:
+// на суперпользователя политики RLS не действуют. Без переменной тест
+// пропускается.
+func testDB(t *testing.T) *DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL не задан")
+	}
+
+	poolConfig, err := pgxpool.ParseConfig(dsn)
+	require.NoError(t, err)
+	configurePool(poolConfig, config.PostgresConfig{})
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	return NewDB(pool)
+}
+
+// testTenant создаёт отдельного арендатора на время теста и возвращает
+// контекст, привязанный к нему. Данные арендатора удаляются вместе с ним.
+func testTenant(t *testing.T, db *DB) context.Context {
+	t.Helper()
+
+	ctx := context.Background()
+	tenantID := uuid.New()
+	_, err := db.pool.Exec(ctx, "INSERT INTO tenants (id, name) VALUES ($1, $2)", tenantID, t.Name())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		db.pool.Exec(context.Background(), "DELETE FROM tenants WHERE id = $1", tenantID)
+	})
+
+	tenantCtx, err := db.WithTenant(ctx, tenantID)
+	require.NoError(t, err)
+
+	return tenantCtx
+}
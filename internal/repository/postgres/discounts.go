@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"effective-mobile-task/internal/models"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrDiscountNotFound означает, что у подписки нет скидки с таким ID.
+var ErrDiscountNotFound = errors.New("discount not found")
+
+// AddDiscount сохраняет скидку подписки d.SubscriptionID. Если подписки нет,
+// возвращает ErrNotFound.
+func (r *SubscriptionRepository) AddDiscount(ctx context.Context, d *models.Discount) error {
+	sql, args, err := r.sqb.Insert("subscription_discounts").
+		Columns("id", "subscription_id", "user_id", "kind", "value", "start_month", "end_month", "promo_code").
+		Select(r.sqb.Select().
+			Column("?::uuid", d.ID).
+			Column("id").
+			Column("user_id").
+			Column("?::varchar", d.Kind).
+			Column("?::integer", d.Value).
+			Column("?::date", d.StartMonth).
+			Column("?::date", d.EndMonth).
+			Column("?::varchar", d.PromoCode).
+			From("subscriptions").
+			Where("id = ?", d.SubscriptionID)).
+		Suffix("RETURNING created_at").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("SubscriptionRepository.AddDiscount - ToSql: %w", err)
+	}
+
+	err = r.db.QueryRow(ctx, sql, args...).Scan(&d.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("SubscriptionRepository.AddDiscount - Scan: %w", err)
+	}
+
+	return nil
+}
+
+// ListDiscounts возвращает скидки подписки в порядке начала их действия.
+func (r *SubscriptionRepository) ListDiscounts(ctx context.Context, subscriptionID uuid.UUID) ([]models.Discount, error) {
+	sql, args, err := r.sqb.Select("id", "subscription_id", "kind", "value", "start_month", "end_month", "promo_code", "created_at").
+		From("subscription_discounts").
+		Where(sq.Eq{"subscription_id": subscriptionID}).
+		OrderBy("start_month", "created_at", "id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.ListDiscounts - ToSql: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.ListDiscounts - Query: %w", err)
+	}
+
+	discounts, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Discount, error) {
+		var d models.Discount
+		err := row.Scan(&d.ID, &d.SubscriptionID, &d.Kind, &d.Value, &d.StartMonth, &d.EndMonth, &d.PromoCode, &d.CreatedAt)
+		return d, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.ListDiscounts - Scan: %w", err)
+	}
+
+	return discounts, nil
+}
+
+// DeleteDiscount удаляет скидку подписки. Если такой скидки у подписки нет,
+// возвращает ErrDiscountNotFound.
+func (r *SubscriptionRepository) DeleteDiscount(ctx context.Context, subscriptionID, discountID uuid.UUID) error {
+	sql, args, err := r.sqb.Delete("subscription_discounts").
+		Where(sq.Eq{"id": discountID, "subscription_id": subscriptionID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("SubscriptionRepository.DeleteDiscount - ToSql: %w", err)
+	}
+
+	res, err := r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("SubscriptionRepository.DeleteDiscount - Exec: %w", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrDiscountNotFound
+	}
+
+	return nil
+}
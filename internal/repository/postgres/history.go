@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// snapshot возвращает, откуда читать подписки: без asOf — текущие данные,
+// иначе версии строк, действовавшие в момент asOf. Чтение истории идёт в
+// отдельной транзакции только для чтения, где search_path начинается со
+// схемы history: её представления подменяют таблицы с историей, а
+// остальные таблицы (каталог, метки, организации) читаются текущими.
+// done завершает транзакцию и должна быть вызвана после чтения.
+func (r *SubscriptionRepository) snapshot(ctx context.Context, asOf *time.Time) (q querier, done func(), err error) {
+	if asOf == nil {
+		return r.db, func() {}, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, nil, fmt.Errorf("SubscriptionRepository.snapshot - Begin: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, "SELECT set_config('search_path', 'history, public', true), set_config('app.as_of', $1, true)",
+		asOf.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		tx.Rollback(ctx)
+		return nil, nil, fmt.Errorf("SubscriptionRepository.snapshot - Exec: %w", err)
+	}
+
+	return tx, func() { tx.Rollback(ctx) }, nil
+}
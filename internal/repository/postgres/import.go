@@ -0,0 +1,211 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"effective-mobile-task/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// importColumns — колонки временной таблицы импорта: номер строки файла и
+// колонки подписки.
+var importColumns = append([]string{"line"}, subscriptionColumns...)
+
+// ImportedSubscription — подписка из файла импорта и номер её строки.
+type ImportedSubscription struct {
+	Line         int
+	Subscription *models.Subscription
+}
+
+// ImportChecks — проверки строк импорта, которым нужна база. Они
+// выполняются после того, как все строки скопированы во временную таблицу,
+// и обрабатывают все строки сразу, а не по запросу на строку.
+type ImportChecks struct {
+	// ResolveServices привязывает подписки к сервисам каталога по названию
+	// или псевдониму.
+	ResolveServices bool
+	// Authorize проверяет, можно ли создавать подписки пользователя. Она
+	// вызывается по разу для каждого пользователя из файла; ошибка прерывает
+	// импорт.
+	Authorize func(ctx context.Context, userID uuid.UUID) error
+	// AllowOverlap разрешает пересекающиеся подписки пользователя на один
+	// сервис. Иначе каждая строка, пересекающаяся с подпиской в базе или со
+	// строкой выше в том же файле, передаётся в Overlap вместе с ID этой
+	// подписки.
+	AllowOverlap bool
+	Overlap      func(line int, existingID uuid.UUID)
+	// Commit решает после проверок, сохранять ли подписки. Без неё
+	// подписки сохраняются.
+	Commit func() bool
+}
+
+// ImportSubscriptions копирует подписки во временную таблицу одним COPY, не
+// загружая их в память целиком, проверяет их по checks и вставляет в
+// subscriptions одной транзакцией. next возвращает очередную подписку или
+// nil, когда данные закончились; ошибка из next прерывает импорт. next
+// вызывается, пока идёт COPY, и не должна обращаться к базе. Возвращает
+// число вставленных подписок; если Commit запретила сохранение — 0.
+func (r *SubscriptionRepository) ImportSubscriptions(ctx context.Context, next func() (*ImportedSubscription, error), checks ImportChecks) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("SubscriptionRepository.ImportSubscriptions - Begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := stageImport(ctx, tx, next); err != nil {
+		return 0, fmt.Errorf("SubscriptionRepository.ImportSubscriptions - %w", err)
+	}
+
+	if checks.ResolveServices {
+		_, err := tx.Exec(ctx, "UPDATE subscriptions_import i SET service_id = s.id, service_name = s.name"+
+			" FROM service_aliases k JOIN services s ON s.id = k.service_id"+
+			" WHERE k.alias_key = lower(i.service_name)")
+		if err != nil {
+			return 0, fmt.Errorf("SubscriptionRepository.ImportSubscriptions - Resolve: %w", err)
+		}
+	}
+
+	if checks.Authorize != nil {
+		if err := authorizeImport(ctx, tx, checks.Authorize); err != nil {
+			return 0, fmt.Errorf("SubscriptionRepository.ImportSubscriptions - %w", err)
+		}
+	}
+
+	if !checks.AllowOverlap {
+		if err := findImportOverlaps(ctx, tx, checks.Overlap); err != nil {
+			return 0, fmt.Errorf("SubscriptionRepository.ImportSubscriptions - %w", err)
+		}
+	}
+
+	if checks.Commit != nil && !checks.Commit() {
+		return 0, nil
+	}
+
+	columns := strings.Join(subscriptionColumns, ", ")
+	res, err := tx.Exec(ctx, "INSERT INTO subscriptions ("+columns+") SELECT "+columns+" FROM subscriptions_import ORDER BY line")
+	if err != nil {
+		return 0, fmt.Errorf("SubscriptionRepository.ImportSubscriptions - Insert: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("SubscriptionRepository.ImportSubscriptions - Commit: %w", err)
+	}
+
+	return res.RowsAffected(), nil
+}
+
+// stageImport копирует подписки из next во временную таблицу
+// subscriptions_import. COPY FROM в таблицы под политиками RLS PostgreSQL
+// не поддерживает, а проверять строки удобнее до вставки, поэтому подписки
+// переносятся в subscriptions отдельным INSERT.
+func stageImport(ctx context.Context, tx pgx.Tx, next func() (*ImportedSubscription, error)) error {
+	columns := strings.Join(subscriptionColumns, ", ")
+	if _, err := tx.Exec(ctx, "CREATE TEMP TABLE subscriptions_import ON COMMIT DROP AS SELECT 0 AS line, "+columns+" FROM subscriptions WITH NO DATA"); err != nil {
+		return fmt.Errorf("Staging: %w", err)
+	}
+
+	source := pgx.CopyFromFunc(func() ([]any, error) {
+		row, err := next()
+		if err != nil || row == nil {
+			return nil, err
+		}
+		return append([]any{row.Line}, subscriptionValues(row.Subscription)...), nil
+	})
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"subscriptions_import"}, importColumns, source); err != nil {
+		return fmt.Errorf("CopyFrom: %w", err)
+	}
+
+	// Индекс нужен для поиска пересечений внутри файла. Временные таблицы
+	// не анализируются автоматически, а без статистики планировщик плохо
+	// выбирает план этого поиска.
+	if _, err := tx.Exec(ctx, "CREATE INDEX ON subscriptions_import (user_id, lower(service_name), line)"); err != nil {
+		return fmt.Errorf("Staging: %w", err)
+	}
+	if _, err := tx.Exec(ctx, "ANALYZE subscriptions_import"); err != nil {
+		return fmt.Errorf("Staging: %w", err)
+	}
+
+	return nil
+}
+
+// authorizeImport вызывает authorize для каждого пользователя из файла.
+func authorizeImport(ctx context.Context, tx pgx.Tx, authorize func(ctx context.Context, userID uuid.UUID) error) error {
+	rows, err := tx.Query(ctx, "SELECT DISTINCT user_id FROM subscriptions_import")
+	if err != nil {
+		return fmt.Errorf("Users: %w", err)
+	}
+	users, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return fmt.Errorf("Users: %w", err)
+	}
+
+	for _, userID := range users {
+		if err := authorize(ctx, userID); err != nil {
+			return fmt.Errorf("Authorize: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// importOverlaps находит для каждой строки импорта первую пересекающуюся с
+// ней подписку пользователя на тот же сервис: сначала среди строк выше в
+// файле, затем среди подписок в базе. Условия те же, что у FindOverlapping.
+const importOverlaps = `
+SELECT i.line, COALESCE(f.id, s.id)
+FROM subscriptions_import i
+LEFT JOIN LATERAL (
+	SELECT f.id FROM subscriptions_import f
+	WHERE f.user_id = i.user_id AND lower(f.service_name) = lower(i.service_name) AND f.line < i.line
+		AND f.start_date <= COALESCE(i.end_date, 'infinity'::date)
+		AND COALESCE(f.end_date, 'infinity'::date) >= i.start_date
+	ORDER BY f.line
+	LIMIT 1
+) f ON true
+LEFT JOIN LATERAL (
+	SELECT s.id FROM subscriptions s
+	WHERE s.user_id = i.user_id AND lower(s.service_name) = lower(i.service_name)
+		AND s.start_date <= COALESCE(i.end_date, 'infinity'::date)
+		AND COALESCE(s.end_date, 'infinity'::date) >= i.start_date
+	ORDER BY s.start_date
+	LIMIT 1
+) s ON true
+WHERE f.id IS NOT NULL OR s.id IS NOT NULL
+ORDER BY i.line`
+
+// findImportOverlaps передаёт в overlap строки импорта, пересекающиеся с
+// другими подписками, заблокировав пересечения так же, как LockOverlaps.
+func findImportOverlaps(ctx context.Context, tx pgx.Tx, overlap func(line int, existingID uuid.UUID)) error {
+	// До конца импорта никто не создаст пересекающуюся подписку на
+	// проверенные пары пользователь — сервис.
+	if err := lockOverlaps(ctx, tx, "SELECT user_id, service_name FROM subscriptions_import"); err != nil {
+		return fmt.Errorf("Lock: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, importOverlaps)
+	if err != nil {
+		return fmt.Errorf("Overlaps: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			line       int
+			existingID uuid.UUID
+		)
+		if err := rows.Scan(&line, &existingID); err != nil {
+			return fmt.Errorf("Overlaps: %w", err)
+		}
+		if overlap != nil {
+			overlap(line, existingID)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("Overlaps: %w", err)
+	}
+
+	return nil
+}
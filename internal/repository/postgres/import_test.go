@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"effective-mobile-task/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// importRows возвращает next для ImportSubscriptions по подпискам со
+// строками файла, начиная со второй.
+func importRows(subs ...*models.Subscription) func() (*ImportedSubscription, error) {
+	i := 0
+	return func() (*ImportedSubscription, error) {
+		if i == len(subs) {
+			return nil, nil
+		}
+		i++
+		return &ImportedSubscription{Line: i + 1, Subscription: subs[i-1]}, nil
+	}
+}
+
+func TestSubscriptionRepository_ImportSubscriptionsReportsOverlaps(t *testing.T) {
+	db := testDB(t)
+	ctx := testTenant(t, db)
+	repo := NewSubscriptionRepository(db)
+	userID := uuid.New()
+	month := models.MonthOf(time.Now())
+
+	existing := &models.Subscription{ID: uuid.New(), UserID: userID, ServiceName: "Okko", Price: 300, StartDate: month}
+	require.NoError(t, repo.Create(ctx, existing))
+
+	netflix := &models.Subscription{ID: uuid.New(), UserID: userID, ServiceName: "Netflix", Price: 400, StartDate: month}
+	rows := importRows(
+		netflix,
+		&models.Subscription{ID: uuid.New(), UserID: userID, ServiceName: "netflix", Price: 400, StartDate: month.AddMonths(1)},
+		&models.Subscription{ID: uuid.New(), UserID: userID, ServiceName: "Okko", Price: 300, StartDate: month},
+	)
+
+	var authorized []uuid.UUID
+	overlaps := make(map[int]uuid.UUID)
+	imported, err := repo.ImportSubscriptions(ctx, rows, ImportChecks{
+		// Проверка прав обращается к базе от имени того же арендатора, пока
+		// открыта транзакция импорта.
+		Authorize: func(ctx context.Context, userID uuid.UUID) error {
+			authorized = append(authorized, userID)
+			var visible int
+			return db.QueryRow(ctx, "SELECT count(*) FROM subscriptions").Scan(&visible)
+		},
+		Overlap: func(line int, existingID uuid.UUID) {
+			overlaps[line] = existingID
+		},
+		Commit: func() bool { return len(overlaps) == 0 },
+	})
+
+	require.NoError(t, err)
+	assert.Zero(t, imported)
+	assert.Equal(t, []uuid.UUID{userID}, authorized)
+	assert.Equal(t, map[int]uuid.UUID{3: netflix.ID, 4: existing.ID}, overlaps)
+
+	_, err = repo.GetByID(ctx, netflix.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestSubscriptionRepository_ImportSubscriptionsCommits(t *testing.T) {
+	db := testDB(t)
+	ctx := testTenant(t, db)
+	repo := NewSubscriptionRepository(db)
+
+	sub := &models.Subscription{ID: uuid.New(), UserID: uuid.New(), ServiceName: "Netflix", Price: 400, StartDate: models.MonthOf(time.Now())}
+	imported, err := repo.ImportSubscriptions(ctx, importRows(sub), ImportChecks{})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), imported)
+
+	got, err := repo.GetByID(ctx, sub.ID)
+	require.NoError(t, err)
+	assert.Equal(t, sub.ServiceName, got.ServiceName)
+}
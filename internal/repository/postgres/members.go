@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"effective-mobile-task/internal/models"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrMemberNotFound означает, что пользователь не участник подписки.
+var ErrMemberNotFound = errors.New("subscription member not found")
+
+// SetMember добавляет участника подписки или меняет его долю. Если подписки
+// нет, возвращает ErrNotFound.
+func (r *SubscriptionRepository) SetMember(ctx context.Context, m *models.SubscriptionMember) error {
+	sql, args, err := r.sqb.Insert("subscription_members").
+		Columns("subscription_id", "payer_id", "user_id", "share_weight", "share_amount").
+		Select(r.sqb.Select().
+			Column("id").
+			Column("user_id").
+			Column("?::uuid", m.UserID).
+			Column("?::integer", m.ShareWeight).
+			Column("?::integer", m.ShareAmount).
+			From("subscriptions").
+			Where("id = ?", m.SubscriptionID)).
+		Suffix("ON CONFLICT (subscription_id, user_id) DO UPDATE SET" +
+			" share_weight = EXCLUDED.share_weight, share_amount = EXCLUDED.share_amount" +
+			" RETURNING created_at").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("SubscriptionRepository.SetMember - ToSql: %w", err)
+	}
+
+	if err := r.db.QueryRow(ctx, sql, args...).Scan(&m.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("SubscriptionRepository.SetMember - Scan: %w", err)
+	}
+
+	return nil
+}
+
+// ListMembers возвращает участников подписки в порядке добавления.
+func (r *SubscriptionRepository) ListMembers(ctx context.Context, subscriptionID uuid.UUID) ([]models.SubscriptionMember, error) {
+	sql, args, err := r.sqb.Select("subscription_id", "user_id", "share_weight", "share_amount", "created_at").
+		From("subscription_members").
+		Where(sq.Eq{"subscription_id": subscriptionID}).
+		OrderBy("created_at", "user_id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.ListMembers - ToSql: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.ListMembers - Query: %w", err)
+	}
+
+	members, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.SubscriptionMember, error) {
+		var m models.SubscriptionMember
+		err := row.Scan(&m.SubscriptionID, &m.UserID, &m.ShareWeight, &m.ShareAmount, &m.CreatedAt)
+		return m, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.ListMembers - Scan: %w", err)
+	}
+
+	return members, nil
+}
+
+// RemoveMember исключает пользователя из участников подписки. Если он не
+// участник, возвращает ErrMemberNotFound.
+func (r *SubscriptionRepository) RemoveMember(ctx context.Context, subscriptionID, userID uuid.UUID) error {
+	sql, args, err := r.sqb.Delete("subscription_members").
+		Where(sq.Eq{"subscription_id": subscriptionID, "user_id": userID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("SubscriptionRepository.RemoveMember - ToSql: %w", err)
+	}
+
+	res, err := r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("SubscriptionRepository.RemoveMember - Exec: %w", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrMemberNotFound
+	}
+
+	return nil
+}
+
+// MemberShares возвращает доли участников общих подписок в месяце month,
+// которые они должны возместить владельцам. С userID возвращаются только
+// доли, которые пользователь должен сам или которые должны ему.
+func (r *SubscriptionRepository) MemberShares(ctx context.Context, month models.Month, userID *uuid.UUID) ([]models.MemberShare, error) {
+	queryBuilder := r.sqb.Select("c.subscription_id", "subscriptions.service_name", "c.payer_id", "c.user_id", "c.amount").
+		FromSelect(r.monthlyCosts(GetSummaryFilter{StartDate: &month, EndDate: &month}), "c").
+		Join("subscriptions ON subscriptions.id = c.subscription_id").
+		Where("c.user_id <> c.payer_id").
+		Where("c.amount > 0").
+		OrderBy("c.user_id", "c.payer_id", "subscriptions.service_name")
+	if userID != nil {
+		queryBuilder = queryBuilder.Where(sq.Or{sq.Eq{"c.user_id": *userID}, sq.Eq{"c.payer_id": *userID}})
+	}
+
+	sql, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.MemberShares - ToSql: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.MemberShares - Query: %w", err)
+	}
+
+	shares, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.MemberShare, error) {
+		var s models.MemberShare
+		err := row.Scan(&s.SubscriptionID, &s.ServiceName, &s.PayerID, &s.UserID, &s.Amount)
+		return s, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.MemberShares - Scan: %w", err)
+	}
+
+	return shares, nil
+}
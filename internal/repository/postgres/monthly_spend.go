@@ -0,0 +1,273 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"effective-mobile-task/internal/models"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// monthlySpendLock — блокировка обновления monthly_spend арендатора: два
+// обновления одного арендатора не должны пересчитывать одни и те же
+// подписки одновременно.
+const monthlySpendLock = "SELECT pg_advisory_xact_lock(hashtext('monthly_spend'), hashtext(current_setting('app.tenant_id', true)))"
+
+// MonthlySpendRefresh — итог обновления monthly_spend.
+type MonthlySpendRefresh struct {
+	// Rebuilt означает, что таблица построена заново, а не обновлена по
+	// изменившимся подпискам.
+	Rebuilt bool
+	// Subscriptions — число пересчитанных подписок при обновлении по
+	// изменениям.
+	Subscriptions int
+	// Horizon — последний месяц, по который построена таблица.
+	Horizon models.Month
+}
+
+// RefreshMonthlySpend пересчитывает в monthly_spend подписки, изменившиеся
+// после прошлого обновления. Если таблица ещё не построена или построена до
+// начала текущего месяца, она строится заново.
+func (r *SubscriptionRepository) RefreshMonthlySpend(ctx context.Context) (*MonthlySpendRefresh, error) {
+	return r.refreshMonthlySpendTx(ctx, false)
+}
+
+// RebuildMonthlySpend строит monthly_spend заново по всем подпискам
+// арендатора. Нужна после загрузки данных в обход триггеров и для
+// первоначального заполнения.
+func (r *SubscriptionRepository) RebuildMonthlySpend(ctx context.Context) (*MonthlySpendRefresh, error) {
+	return r.refreshMonthlySpendTx(ctx, true)
+}
+
+func (r *SubscriptionRepository) refreshMonthlySpendTx(ctx context.Context, rebuild bool) (*MonthlySpendRefresh, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.RefreshMonthlySpend - Begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	refresh, err := r.refreshMonthlySpend(ctx, tx, rebuild)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.RefreshMonthlySpend - Commit: %w", err)
+	}
+
+	return refresh, nil
+}
+
+func (r *SubscriptionRepository) refreshMonthlySpend(ctx context.Context, q querier, rebuild bool) (*MonthlySpendRefresh, error) {
+	if _, err := q.Exec(ctx, monthlySpendLock); err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.RefreshMonthlySpend - Lock: %w", err)
+	}
+
+	refresh := &MonthlySpendRefresh{Rebuilt: rebuild}
+	if !rebuild {
+		var current bool
+		err := q.QueryRow(ctx, "SELECT horizon = "+currentMonth+", horizon FROM monthly_spend_state").
+			Scan(&current, &refresh.Horizon)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("SubscriptionRepository.RefreshMonthlySpend - State: %w", err)
+		}
+		refresh.Rebuilt = !current
+	}
+
+	if refresh.Rebuilt {
+		if err := r.rebuildMonthlySpend(ctx, q, refresh); err != nil {
+			return nil, err
+		}
+		return refresh, nil
+	}
+
+	rows, err := q.Query(ctx, "DELETE FROM monthly_spend_dirty RETURNING subscription_id")
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.RefreshMonthlySpend - Dirty: %w", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.RefreshMonthlySpend - Dirty: %w", err)
+	}
+	if len(ids) == 0 {
+		return refresh, nil
+	}
+
+	if _, err := q.Exec(ctx, "DELETE FROM monthly_spend WHERE subscription_id = ANY($1)", ids); err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.RefreshMonthlySpend - Delete: %w", err)
+	}
+	if err := r.insertMonthlySpend(ctx, q, sq.Expr("c.subscription_id = ANY(?)", ids)); err != nil {
+		return nil, err
+	}
+	if _, err := q.Exec(ctx, "UPDATE monthly_spend_state SET refreshed_at = now()"); err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.RefreshMonthlySpend - State: %w", err)
+	}
+	refresh.Subscriptions = len(ids)
+
+	return refresh, nil
+}
+
+func (r *SubscriptionRepository) rebuildMonthlySpend(ctx context.Context, q querier, refresh *MonthlySpendRefresh) error {
+	for _, table := range []string{"monthly_spend_dirty", "monthly_spend"} {
+		if _, err := q.Exec(ctx, "DELETE FROM "+table); err != nil {
+			return fmt.Errorf("SubscriptionRepository.RebuildMonthlySpend - Delete: %w", err)
+		}
+	}
+
+	if err := r.insertMonthlySpend(ctx, q, nil); err != nil {
+		return err
+	}
+
+	err := q.QueryRow(ctx, "INSERT INTO monthly_spend_state (horizon) VALUES ("+currentMonth+")"+
+		" ON CONFLICT (tenant_id) DO UPDATE SET horizon = EXCLUDED.horizon, refreshed_at = now()"+
+		" RETURNING horizon").Scan(&refresh.Horizon)
+	if err != nil {
+		return fmt.Errorf("SubscriptionRepository.RebuildMonthlySpend - State: %w", err)
+	}
+
+	return nil
+}
+
+// insertMonthlySpend записывает в monthly_spend доли пользователей по
+// месяцам до текущего включительно для подписок, подходящих под where.
+func (r *SubscriptionRepository) insertMonthlySpend(ctx context.Context, q querier, where sq.Sqlizer) error {
+	costs := r.sqb.Select("c.subscription_id", "c.user_id", "subscriptions.service_name", "c.month", "c.gross", "c.amount").
+		FromSelect(r.monthlyCosts(GetSummaryFilter{}), "c").
+		Join("subscriptions ON subscriptions.id = c.subscription_id")
+	if where != nil {
+		costs = costs.Where(where)
+	}
+
+	sql, args, err := r.sqb.Insert("monthly_spend").
+		Columns("subscription_id", "user_id", "service_name", "month", "gross", "amount").
+		Select(costs).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("SubscriptionRepository.RefreshMonthlySpend - ToSql: %w", err)
+	}
+
+	if _, err := q.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("SubscriptionRepository.RefreshMonthlySpend - Insert: %w", err)
+	}
+
+	return nil
+}
+
+// rollupSummary сообщает, можно ли посчитать сводку по фильтру из
+// monthly_spend: в таблице нет категорий и меток, похожие названия ищутся
+// только по подпискам, а прошлых версий таблица не хранит.
+func rollupSummary(filter GetSummaryFilter) bool {
+	return filter.ServiceNameLike == nil && filter.Category == nil && len(filter.Tags) == 0 && filter.AsOf == nil
+}
+
+// summaryFromRollup считает сводку по monthly_spend, не изменяя её:
+// пересчёт остаётся фоновой задаче и команде. ok = false означает, что
+// таблица не построена на текущий месяц, период фильтра выходит за него или
+// среди подписок, отмеченных к пересчёту, есть попадающие под фильтр, и
+// сводку нужно считать по подпискам.
+func (r *SubscriptionRepository) summaryFromRollup(ctx context.Context, filter GetSummaryFilter) (totals *models.SummaryTotals, ok bool, err error) {
+	// Состояние, отметки и доли читаются из одного снимка.
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, false, fmt.Errorf("SubscriptionRepository.GetSummary - Begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		current bool
+		horizon models.Month
+	)
+	err = tx.QueryRow(ctx, "SELECT horizon = "+currentMonth+", horizon FROM monthly_spend_state").Scan(&current, &horizon)
+	if errors.Is(err, pgx.ErrNoRows) || err == nil && !current {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("SubscriptionRepository.GetSummary - State: %w", err)
+	}
+	if filter.EndDate != nil && filter.EndDate.After(horizon) {
+		return nil, false, nil
+	}
+
+	dirty, err := r.rollupDirty(ctx, tx, filter)
+	if err != nil {
+		return nil, false, err
+	}
+	if dirty {
+		return nil, false, nil
+	}
+
+	queryBuilder := r.sqb.Select("COALESCE(SUM(gross), 0)", "COALESCE(SUM(amount), 0)").
+		From("monthly_spend")
+	if filter.UserID != nil {
+		queryBuilder = queryBuilder.Where(sq.Eq{"user_id": *filter.UserID})
+	}
+	if filter.ServiceName != nil {
+		queryBuilder = queryBuilder.Where(sq.Eq{"service_name": *filter.ServiceName})
+	}
+	if filter.StartDate != nil {
+		queryBuilder = queryBuilder.Where(sq.GtOrEq{"month": *filter.StartDate})
+	}
+	if filter.EndDate != nil {
+		queryBuilder = queryBuilder.Where(sq.LtOrEq{"month": *filter.EndDate})
+	}
+
+	sql, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, false, fmt.Errorf("SubscriptionRepository.GetSummary - ToSql: %w", err)
+	}
+
+	totals = &models.SummaryTotals{}
+	if err := tx.QueryRow(ctx, sql, args...).Scan(&totals.Gross, &totals.Net); err != nil {
+		return nil, false, fmt.Errorf("SubscriptionRepository.GetSummary - Scan: %w", err)
+	}
+	totals.Discount = totals.Gross - totals.Net
+
+	return totals, true, nil
+}
+
+// rollupDirty сообщает, есть ли среди подписок, отмеченных к пересчёту,
+// такие, что могут попасть в сводку по filter: по текущим данным подписки,
+// по её участникам или по уже записанным в monthly_spend долям.
+func (r *SubscriptionRepository) rollupDirty(ctx context.Context, q querier, filter GetSummaryFilter) (bool, error) {
+	// Подзапросы собираются с плейсхолдерами «?»: нумерацию $n для всего
+	// запроса проставляет внешний построитель.
+	subscriptions := sq.Select("id").From("subscriptions")
+	spend := sq.Select("subscription_id").From("monthly_spend")
+	if filter.UserID != nil {
+		subscriptions = subscriptions.Where(sq.Eq{"user_id": *filter.UserID})
+		spend = spend.Where(sq.Eq{"user_id": *filter.UserID})
+	}
+	if filter.ServiceName != nil {
+		subscriptions = subscriptions.Where(sq.Eq{"service_name": *filter.ServiceName})
+		spend = spend.Where(sq.Eq{"service_name": *filter.ServiceName})
+	}
+
+	matches := sq.Or{
+		sq.Expr("d.subscription_id IN (?)", subscriptions),
+		sq.Expr("d.subscription_id IN (?)", spend),
+	}
+	if filter.UserID != nil {
+		members := sq.Select("subscription_id").From("subscription_members").Where(sq.Eq{"user_id": *filter.UserID})
+		matches = append(matches, sq.Expr("d.subscription_id IN (?)", members))
+	}
+
+	sql, args, err := r.sqb.Select("1").
+		From("monthly_spend_dirty d").
+		Where(matches).
+		Prefix("SELECT EXISTS (").
+		Suffix(")").
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("SubscriptionRepository.GetSummary - ToSql: %w", err)
+	}
+
+	var dirty bool
+	if err := q.QueryRow(ctx, sql, args...).Scan(&dirty); err != nil {
+		return false, fmt.Errorf("SubscriptionRepository.GetSummary - Dirty: %w", err)
+	}
+
+	return dirty, nil
+}
@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"effective-mobile-task/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptionRepository_GetSummaryDoesNotRefreshRollup(t *testing.T) {
+	db := testDB(t)
+	ctx := testTenant(t, db)
+	repo := NewSubscriptionRepository(db)
+	userID := uuid.New()
+	month := models.MonthOf(time.Now())
+
+	_, err := repo.RebuildMonthlySpend(ctx)
+	require.NoError(t, err)
+
+	sub := &models.Subscription{ID: uuid.New(), UserID: userID, ServiceName: "Netflix", Price: 400, StartDate: month}
+	require.NoError(t, repo.Create(ctx, sub))
+
+	// Подписка ещё не пересчитана: сводка считается по подпискам.
+	totals, err := repo.GetSummary(ctx, GetSummaryFilter{UserID: &userID, StartDate: &month, EndDate: &month})
+	require.NoError(t, err)
+	assert.Equal(t, 400, totals.Net)
+
+	var dirty int
+	require.NoError(t, db.QueryRow(ctx, "SELECT count(*) FROM monthly_spend_dirty").Scan(&dirty))
+	assert.Equal(t, 1, dirty)
+
+	// После фонового пересчёта та же сводка читается из monthly_spend.
+	_, err = repo.RefreshMonthlySpend(ctx)
+	require.NoError(t, err)
+	totals, err = repo.GetSummary(ctx, GetSummaryFilter{UserID: &userID, StartDate: &month, EndDate: &month})
+	require.NoError(t, err)
+	assert.Equal(t, 400, totals.Net)
+}
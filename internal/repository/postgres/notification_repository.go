@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"effective-mobile-task/internal/models"
+	sq "github.com/Masterminds/squirrel"
+)
+
+type NotificationRepository struct {
+	db  *DB
+	sqb sq.StatementBuilderType
+}
+
+func NewNotificationRepository(db *DB) *NotificationRepository {
+	return &NotificationRepository{
+		db:  db,
+		sqb: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// Record сохраняет уведомление, если такое же уведомление о подписке или
+// бюджете ещё не записано, и сообщает, было ли оно сохранено. Уведомление отправляется
+// только после успешной записи, поэтому повторная проверка его не дублирует.
+func (r *NotificationRepository) Record(ctx context.Context, n *models.Notification) (bool, error) {
+	sql, args, err := r.sqb.Insert("notifications").
+		Columns("id", "user_id", "subscription_id", "budget_id", "threshold", "kind", "due_at", "created_at").
+		Values(n.ID, n.UserID, n.SubscriptionID, n.BudgetID, n.Threshold, n.Kind, n.DueAt, n.CreatedAt).
+		Suffix("ON CONFLICT DO NOTHING").
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("NotificationRepository.Record - ToSql: %w", err)
+	}
+
+	res, err := r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return false, fmt.Errorf("NotificationRepository.Record - Exec: %w", err)
+	}
+
+	return res.RowsAffected() == 1, nil
+}
+
+// Forget удаляет запись об уведомлении, которое не удалось отправить, чтобы
+// следующая проверка попробовала ещё раз.
+func (r *NotificationRepository) Forget(ctx context.Context, n *models.Notification) error {
+	sql, args, err := r.sqb.Delete("notifications").
+		Where(sq.Eq{"id": n.ID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("NotificationRepository.Forget - ToSql: %w", err)
+	}
+
+	if _, err := r.db.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("NotificationRepository.Forget - Exec: %w", err)
+	}
+
+	return nil
+}
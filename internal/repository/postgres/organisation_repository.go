@@ -0,0 +1,233 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"effective-mobile-task/internal/models"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// foreignKeyViolation — код ошибки PostgreSQL при нарушении внешнего ключа.
+const foreignKeyViolation = "23503"
+
+var (
+	ErrOrganisationNotFound = errors.New("organisation not found")
+	// ErrOrganisationConflict означает, что организация с таким названием уже
+	// есть.
+	ErrOrganisationConflict = errors.New("organisation with this name already exists")
+	ErrCostCentreNotFound   = errors.New("cost centre not found")
+	// ErrCostCentreConflict означает, что в организации уже есть узел с таким
+	// кодом.
+	ErrCostCentreConflict = errors.New("cost centre with this code already exists")
+	// ErrCostCentreInUse означает, что у узла есть дочерние узлы.
+	ErrCostCentreInUse = errors.New("cost centre has children")
+	// ErrOrganisationUserNotFound означает, что пользователь не состоит в
+	// организации.
+	ErrOrganisationUserNotFound = errors.New("user is not a member of the organisation")
+)
+
+var costCentreColumns = []string{"id", "organisation_id", "parent_id", "kind", "code", "name", "created_at"}
+
+type OrganisationRepository struct {
+	db  *DB
+	sqb sq.StatementBuilderType
+}
+
+func NewOrganisationRepository(db *DB) *OrganisationRepository {
+	return &OrganisationRepository{
+		db:  db,
+		sqb: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *OrganisationRepository) CreateOrganisation(ctx context.Context, o *models.Organisation) error {
+	sql, args, err := r.sqb.Insert("organisations").
+		Columns("id", "name").
+		Values(o.ID, o.Name).
+		Suffix("RETURNING created_at").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("OrganisationRepository.CreateOrganisation - ToSql: %w", err)
+	}
+
+	if err := r.db.QueryRow(ctx, sql, args...).Scan(&o.CreatedAt); err != nil {
+		if isUniqueViolation(err) {
+			return ErrOrganisationConflict
+		}
+		return fmt.Errorf("OrganisationRepository.CreateOrganisation - Scan: %w", err)
+	}
+
+	return nil
+}
+
+func (r *OrganisationRepository) GetOrganisation(ctx context.Context, id uuid.UUID) (*models.Organisation, error) {
+	sql, args, err := r.sqb.Select("id", "name", "created_at").
+		From("organisations").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("OrganisationRepository.GetOrganisation - ToSql: %w", err)
+	}
+
+	var o models.Organisation
+	if err := r.db.QueryRow(ctx, sql, args...).Scan(&o.ID, &o.Name, &o.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrOrganisationNotFound
+		}
+		return nil, fmt.Errorf("OrganisationRepository.GetOrganisation - Scan: %w", err)
+	}
+
+	return &o, nil
+}
+
+func (r *OrganisationRepository) ListOrganisations(ctx context.Context) ([]models.Organisation, error) {
+	sql, args, err := r.sqb.Select("id", "name", "created_at").
+		From("organisations").
+		OrderBy("lower(name)").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("OrganisationRepository.ListOrganisations - ToSql: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("OrganisationRepository.ListOrganisations - Query: %w", err)
+	}
+
+	organisations, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.Organisation])
+	if err != nil {
+		return nil, fmt.Errorf("OrganisationRepository.ListOrganisations - Scan: %w", err)
+	}
+
+	return organisations, nil
+}
+
+// DeleteOrganisation удаляет организацию вместе с её структурой. Подписки,
+// отнесённые на её узлы, остаются без центра затрат.
+func (r *OrganisationRepository) DeleteOrganisation(ctx context.Context, id uuid.UUID) error {
+	sql, args, err := r.sqb.Delete("organisations").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("OrganisationRepository.DeleteOrganisation - ToSql: %w", err)
+	}
+
+	res, err := r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("OrganisationRepository.DeleteOrganisation - Exec: %w", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrOrganisationNotFound
+	}
+
+	return nil
+}
+
+func (r *OrganisationRepository) CreateCostCentre(ctx context.Context, c *models.CostCentre) error {
+	sql, args, err := r.sqb.Insert("cost_centres").
+		Columns("id", "organisation_id", "parent_id", "kind", "code", "name").
+		Values(c.ID, c.OrganisationID, c.ParentID, c.Kind, c.Code, c.Name).
+		Suffix("RETURNING created_at").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("OrganisationRepository.CreateCostCentre - ToSql: %w", err)
+	}
+
+	if err := r.db.QueryRow(ctx, sql, args...).Scan(&c.CreatedAt); err != nil {
+		if isUniqueViolation(err) {
+			return ErrCostCentreConflict
+		}
+		return fmt.Errorf("OrganisationRepository.CreateCostCentre - Scan: %w", err)
+	}
+
+	return nil
+}
+
+// GetCostCentre возвращает узел структуры организации orgID. Узел другой
+// организации считается ненайденным.
+func (r *OrganisationRepository) GetCostCentre(ctx context.Context, orgID, id uuid.UUID) (*models.CostCentre, error) {
+	sql, args, err := r.sqb.Select(costCentreColumns...).
+		From("cost_centres").
+		Where(sq.Eq{"id": id, "organisation_id": orgID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("OrganisationRepository.GetCostCentre - ToSql: %w", err)
+	}
+
+	var c models.CostCentre
+	if err := scanCostCentre(r.db.QueryRow(ctx, sql, args...), &c); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCostCentreNotFound
+		}
+		return nil, fmt.Errorf("OrganisationRepository.GetCostCentre - Scan: %w", err)
+	}
+
+	return &c, nil
+}
+
+func (r *OrganisationRepository) ListCostCentres(ctx context.Context, orgID uuid.UUID) ([]models.CostCentre, error) {
+	sql, args, err := r.sqb.Select(costCentreColumns...).
+		From("cost_centres").
+		Where(sq.Eq{"organisation_id": orgID}).
+		OrderBy("code").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("OrganisationRepository.ListCostCentres - ToSql: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("OrganisationRepository.ListCostCentres - Query: %w", err)
+	}
+
+	centres, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.CostCentre, error) {
+		var c models.CostCentre
+		err := scanCostCentre(row, &c)
+		return c, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("OrganisationRepository.ListCostCentres - Scan: %w", err)
+	}
+
+	return centres, nil
+}
+
+// DeleteCostCentre удаляет узел структуры, у которого нет дочерних узлов.
+// Подписки, отнесённые на узел, остаются без центра затрат.
+func (r *OrganisationRepository) DeleteCostCentre(ctx context.Context, orgID, id uuid.UUID) error {
+	sql, args, err := r.sqb.Delete("cost_centres").
+		Where(sq.Eq{"id": id, "organisation_id": orgID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("OrganisationRepository.DeleteCostCentre - ToSql: %w", err)
+	}
+
+	res, err := r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return ErrCostCentreInUse
+		}
+		return fmt.Errorf("OrganisationRepository.DeleteCostCentre - Exec: %w", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrCostCentreNotFound
+	}
+
+	return nil
+}
+
+func scanCostCentre(row pgx.Row, c *models.CostCentre) error {
+	return row.Scan(&c.ID, &c.OrganisationID, &c.ParentID, &c.Kind, &c.Code, &c.Name, &c.CreatedAt)
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation
+}
@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"effective-mobile-task/internal/models"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// AddUser включает пользователя в организацию. Повторное включение ничего
+// не меняет.
+func (r *OrganisationRepository) AddUser(ctx context.Context, u *models.OrganisationUser) error {
+	sql, args, err := r.sqb.Insert("organisation_users").
+		Columns("organisation_id", "user_id").
+		Values(u.OrganisationID, u.UserID).
+		Suffix("ON CONFLICT (organisation_id, user_id) DO UPDATE SET user_id = EXCLUDED.user_id RETURNING created_at").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("OrganisationRepository.AddUser - ToSql: %w", err)
+	}
+
+	if err := r.db.QueryRow(ctx, sql, args...).Scan(&u.CreatedAt); err != nil {
+		if isForeignKeyViolation(err) {
+			return ErrOrganisationNotFound
+		}
+		return fmt.Errorf("OrganisationRepository.AddUser - Scan: %w", err)
+	}
+
+	return nil
+}
+
+func (r *OrganisationRepository) ListUsers(ctx context.Context, orgID uuid.UUID) ([]models.OrganisationUser, error) {
+	sql, args, err := r.sqb.Select("organisation_id", "user_id", "created_at").
+		From("organisation_users").
+		Where(sq.Eq{"organisation_id": orgID}).
+		OrderBy("created_at", "user_id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("OrganisationRepository.ListUsers - ToSql: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("OrganisationRepository.ListUsers - Query: %w", err)
+	}
+
+	users, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.OrganisationUser])
+	if err != nil {
+		return nil, fmt.Errorf("OrganisationRepository.ListUsers - Scan: %w", err)
+	}
+
+	return users, nil
+}
+
+func (r *OrganisationRepository) RemoveUser(ctx context.Context, orgID, userID uuid.UUID) error {
+	sql, args, err := r.sqb.Delete("organisation_users").
+		Where(sq.Eq{"organisation_id": orgID, "user_id": userID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("OrganisationRepository.RemoveUser - ToSql: %w", err)
+	}
+
+	res, err := r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("OrganisationRepository.RemoveUser - Exec: %w", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrOrganisationUserNotFound
+	}
+
+	return nil
+}
+
+// SharesOrganisation сообщает, состоят ли оба пользователя хотя бы в одной
+// общей организации.
+func (r *OrganisationRepository) SharesOrganisation(ctx context.Context, a, b uuid.UUID) (bool, error) {
+	sql, args, err := r.sqb.Select("1").
+		From("organisation_users AS m").
+		Join("organisation_users AS o USING (organisation_id)").
+		Where(sq.Eq{"m.user_id": a, "o.user_id": b}).
+		Prefix("SELECT EXISTS (").
+		Suffix(")").
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("OrganisationRepository.SharesOrganisation - ToSql: %w", err)
+	}
+
+	var shares bool
+	if err := r.db.QueryRow(ctx, sql, args...).Scan(&shares); err != nil {
+		return false, fmt.Errorf("OrganisationRepository.SharesOrganisation - Scan: %w", err)
+	}
+
+	return shares, nil
+}
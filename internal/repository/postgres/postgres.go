@@ -2,10 +2,13 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"effective-mobile-task/internal/config"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -16,13 +19,36 @@ const (
 )
 
 
+// ErrRoleBypassesRLS возвращается, если приложение подключено к базе
+// суперпользователем или ролью с BYPASSRLS: политики RLS на них не
+// действуют, и арендаторы не изолированы друг от друга.
+var ErrRoleBypassesRLS = errors.New("роль подключения к postgres обходит политики RLS: приложению нужна роль NOSUPERUSER NOBYPASSRLS")
+
+
+// querier — общий интерфейс пула соединений и транзакции, чтобы одни и те же
+// запросы можно было выполнять как отдельно, так и внутри транзакции.
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+
 func NewPostgresDB(cfg config.PostgresConfig) (*pgxpool.Pool, error) {
 
 	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
 	defer cancel()
 
 
-	pool, err := pgxpool.New(ctx, cfg.DSN())
+	poolConfig, err := pgxpool.ParseConfig(cfg.DSN())
+	if err != nil {
+		return nil, fmt.Errorf("неверные параметры подключения к postgres: %w", err)
+	}
+	configurePool(poolConfig, cfg)
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("не удалось подключиться к postgres: %w", err)
 	}
@@ -33,10 +59,25 @@ func NewPostgresDB(cfg config.PostgresConfig) (*pgxpool.Pool, error) {
 		return nil, err
 	}
 
+	if err := checkRole(pool); err != nil {
+		pool.Close()
+		return nil, err
+	}
+
 	return pool, nil
 }
 
 
+// configurePool задаёт размер пула и привязку выдаваемых соединений к
+// арендатору из контекста запроса.
+func configurePool(poolConfig *pgxpool.Config, cfg config.PostgresConfig) {
+	if cfg.MaxConns > 0 {
+		poolConfig.MaxConns = cfg.MaxConns
+	}
+	poolConfig.PrepareConn = prepareTenant
+}
+
+
 func pingDatabase(pool *pgxpool.Pool) error {
 	
 	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
@@ -48,3 +89,20 @@ func pingDatabase(pool *pgxpool.Pool) error {
 
 	return nil
 }
+
+// checkRole проверяет, что на роль подключения действуют политики RLS.
+func checkRole(pool *pgxpool.Pool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+
+	var bypass bool
+	err := pool.QueryRow(ctx, "SELECT rolsuper OR rolbypassrls FROM pg_roles WHERE rolname = current_user").Scan(&bypass)
+	if err != nil {
+		return fmt.Errorf("не удалось проверить роль подключения к postgres: %w", err)
+	}
+	if bypass {
+		return ErrRoleBypassesRLS
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"effective-mobile-task/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const currentMonth = "date_trunc('month', now())::date"
+
+// priceMonth — месяц, цена которого считается текущей ценой подписки:
+// текущий месяц, но не раньше начала подписки и не позже её окончания.
+const priceMonth = "GREATEST(subscriptions.start_date," +
+	" LEAST(COALESCE(subscriptions.end_date, " + currentMonth + "), " + currentMonth + "))"
+
+// pricingHistory — история изменений одной составляющей цены подписки. До
+// первого изменения действует значение одноимённой колонки subscriptions.
+type pricingHistory struct {
+	table  string
+	column string
+}
+
+var (
+	unitPriceHistory = pricingHistory{table: "subscription_prices", column: "unit_price"}
+	seatsHistory     = pricingHistory{table: "subscription_seats", column: "quantity"}
+)
+
+// at возвращает SQL-выражение значения, действующего в месяце month.
+func (h pricingHistory) at(month string) string {
+	return "COALESCE((SELECT h." + h.column + " FROM " + h.table + " h" +
+		" WHERE h.subscription_id = subscriptions.id AND h.effective_from <= " + month +
+		" ORDER BY h.effective_from DESC LIMIT 1), subscriptions." + h.column + ")"
+}
+
+// change возвращает CTE для обновления подписки: если новое значение
+// отличается от действующего, оно записывается изменением с текущего месяца,
+// а прошлые месяцы сохраняют прежнее. Исходное значение в subscriptions не
+// меняется.
+func (h pricingHistory) change(name string, sub *models.Subscription, value int) (string, []any) {
+	month := "GREATEST(?::date, LEAST(COALESCE(?::date, " + currentMonth + "), " + currentMonth + "))"
+	sql := name + " AS (" +
+		"INSERT INTO " + h.table + " (subscription_id, user_id, effective_from, " + h.column + ")" +
+		" SELECT id, user_id, " + month + ", ?::integer FROM subscriptions" +
+		" WHERE id = ? AND " + h.at(month) + " IS DISTINCT FROM ?::integer" +
+		" ON CONFLICT (subscription_id, effective_from) DO UPDATE SET " + h.column + " = EXCLUDED." + h.column + ")"
+	args := []any{
+		sub.StartDate, sub.EndDate, value,
+		sub.ID, sub.StartDate, sub.EndDate, value,
+	}
+	return sql, args
+}
+
+// priceAt возвращает SQL-выражение цены подписки в месяце month: цену места
+// и число мест, действующие в этом месяце.
+func priceAt(month string) string {
+	return "(" + unitPriceHistory.at(month) + " * " + seatsHistory.at(month) + ")"
+}
+
+// pricingChanges — CTE для updateQuery, которые записывают изменения цены
+// места и числа мест в историю.
+func pricingChanges(sub *models.Subscription) (string, []any) {
+	priceSQL, priceArgs := unitPriceHistory.change("price_change", sub, sub.UnitPrice)
+	seatsSQL, seatsArgs := seatsHistory.change("seats_change", sub, sub.Quantity)
+	return "WITH " + priceSQL + ", " + seatsSQL, append(priceArgs, seatsArgs...)
+}
+
+// SchedulePrice записывает изменение цены места с месяца from. Изменение с
+// того же месяца заменяется.
+func (r *SubscriptionRepository) SchedulePrice(ctx context.Context, subscriptionID uuid.UUID, from models.Month, unitPrice int) error {
+	if err := r.schedule(ctx, unitPriceHistory, subscriptionID, from, unitPrice); err != nil {
+		return fmt.Errorf("SubscriptionRepository.SchedulePrice - %w", err)
+	}
+	return nil
+}
+
+// ScheduleSeats записывает изменение числа мест с месяца from. Изменение с
+// того же месяца заменяется.
+func (r *SubscriptionRepository) ScheduleSeats(ctx context.Context, subscriptionID uuid.UUID, from models.Month, quantity int) error {
+	if err := r.schedule(ctx, seatsHistory, subscriptionID, from, quantity); err != nil {
+		return fmt.Errorf("SubscriptionRepository.ScheduleSeats - %w", err)
+	}
+	return nil
+}
+
+func (r *SubscriptionRepository) schedule(ctx context.Context, h pricingHistory, subscriptionID uuid.UUID, from models.Month, value int) error {
+	sql, args, err := r.sqb.Insert(h.table).
+		Columns("subscription_id", "user_id", "effective_from", h.column).
+		Select(r.sqb.Select().
+			Column("id").
+			Column("user_id").
+			Column("?::date", from).
+			Column("?::integer", value).
+			From("subscriptions").
+			Where("id = ?", subscriptionID)).
+		Suffix("ON CONFLICT (subscription_id, effective_from) DO UPDATE SET " +
+			h.column + " = EXCLUDED." + h.column + ", created_at = now()").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ToSql: %w", err)
+	}
+
+	res, err := r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("Exec: %w", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// PriceHistory возвращает периоды цены подписки от её начала, включая
+// запланированные. Новый период начинается с каждым изменением цены места
+// или числа мест; изменения после окончания подписки не возвращаются.
+func (r *SubscriptionRepository) PriceHistory(ctx context.Context, subscriptionID uuid.UUID) ([]models.PricePeriod, error) {
+	changes := func(h pricingHistory) string {
+		return " UNION SELECT h.effective_from FROM " + h.table + " h" +
+			" WHERE h.subscription_id = subscriptions.id AND h.effective_from > subscriptions.start_date" +
+			" AND h.effective_from <= COALESCE(subscriptions.end_date, 'infinity'::date)"
+	}
+
+	sql, args, err := r.sqb.Select(
+		"months.effective_from",
+		unitPriceHistory.at("months.effective_from"),
+		seatsHistory.at("months.effective_from"),
+	).
+		From("subscriptions").
+		JoinClause("CROSS JOIN LATERAL (SELECT subscriptions.start_date"+
+			changes(unitPriceHistory)+changes(seatsHistory)+
+			") AS months(effective_from)").
+		Where("subscriptions.id = ?", subscriptionID).
+		OrderBy("months.effective_from").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.PriceHistory - ToSql: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.PriceHistory - Query: %w", err)
+	}
+
+	history, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.PricePeriod, error) {
+		var p models.PricePeriod
+		err := row.Scan(&p.EffectiveFrom, &p.UnitPrice, &p.Quantity)
+		p.Price = p.UnitPrice * p.Quantity
+		return p, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.PriceHistory - Scan: %w", err)
+	}
+
+	return history, nil
+}
@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"effective-mobile-task/internal/ratelimit"
+)
+
+// takeToken наполняет ведро токенами, накопившимися с прошлого запроса, и
+// забирает один, если он есть. Все выражения SET видят старую строку,
+// поэтому наполнение считается одинаково в каждом из них. Время берётся из
+// базы, чтобы расхождение часов реплик не влияло на лимиты.
+const takeToken = `
+INSERT INTO rate_limit_buckets AS b (key, tokens, allowed, updated_at)
+VALUES ($1, $2::float8 - 1, true, now())
+ON CONFLICT (key) DO UPDATE SET
+    allowed = LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at)::float8 * $3::float8) >= 1,
+    tokens = LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at)::float8 * $3::float8)
+        - CASE WHEN LEAST($2::float8, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at)::float8 * $3::float8) >= 1 THEN 1 ELSE 0 END,
+    updated_at = now()
+RETURNING tokens, allowed`
+
+// RateLimitStore хранит вёдра ограничения частоты запросов в Postgres, чтобы
+// лимиты действовали сразу на все реплики сервиса.
+type RateLimitStore struct {
+	db *DB
+}
+
+func NewRateLimitStore(db *DB) *RateLimitStore {
+	return &RateLimitStore{db: db}
+}
+
+func (s *RateLimitStore) Take(ctx context.Context, key string, limit ratelimit.Limit) (float64, bool, error) {
+	rate := float64(limit.Requests) / limit.Period.Seconds()
+
+	var (
+		tokens  float64
+		allowed bool
+	)
+	if err := s.db.pool.QueryRow(ctx, takeToken, key, float64(limit.Requests), rate).Scan(&tokens, &allowed); err != nil {
+		return 0, false, fmt.Errorf("RateLimitStore.Take - Scan: %w", err)
+	}
+
+	return tokens, allowed, nil
+}
+
+// DeleteIdle удаляет вёдра, к которым не обращались дольше idle. Такие
+// вёдра уже наполнились и ничем не отличаются от несозданных, если idle не
+// меньше самого длинного периода лимитов.
+func (s *RateLimitStore) DeleteIdle(ctx context.Context, idle time.Duration) (int64, error) {
+	res, err := s.db.pool.Exec(ctx, "DELETE FROM rate_limit_buckets WHERE updated_at < now() - $1::interval", idle)
+	if err != nil {
+		return 0, fmt.Errorf("RateLimitStore.DeleteIdle - Exec: %w", err)
+	}
+
+	return res.RowsAffected(), nil
+}
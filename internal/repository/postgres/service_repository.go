@@ -0,0 +1,277 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"effective-mobile-task/internal/models"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	ErrServiceNotFound = errors.New("service not found")
+	// ErrServiceConflict означает, что название или псевдоним уже заняты
+	// другим сервисом каталога.
+	ErrServiceConflict = errors.New("service name or alias already exists")
+)
+
+// uniqueViolation — код ошибки PostgreSQL при нарушении уникальности.
+const uniqueViolation = "23505"
+
+var serviceColumns = []string{
+	"s.id",
+	"s.name",
+	"ARRAY(SELECT a.alias FROM service_aliases a WHERE a.service_id = s.id AND a.alias <> s.name ORDER BY a.alias)",
+	"s.category",
+	"s.website",
+	"s.default_price",
+	"s.currency",
+}
+
+type ServiceRepository struct {
+	db *DB
+
+	sqb sq.StatementBuilderType
+}
+
+func NewServiceRepository(db *DB) *ServiceRepository {
+	return &ServiceRepository{
+		db:  db,
+		sqb: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// AliasKey приводит название или псевдоним к виду, по которому они
+// сравниваются в каталоге.
+func AliasKey(name string) string {
+	return strings.ToLower(name)
+}
+
+// Create добавляет сервис в каталог и привязывает к нему существующие
+// подписки, название которых совпадает с названием или псевдонимом сервиса.
+func (r *ServiceRepository) Create(ctx context.Context, svc *models.Service) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ServiceRepository.Create - Begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sql, args, err := r.sqb.Insert("services").
+		Columns("id", "name", "category", "website", "default_price", "currency").
+		Values(svc.ID, svc.Name, svc.Category, svc.Website, svc.DefaultPrice, svc.Currency).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ServiceRepository.Create - ToSql: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("ServiceRepository.Create - Exec: %w", err)
+	}
+
+	if err := r.saveAliases(ctx, tx, svc); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("ServiceRepository.Create - Commit: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ServiceRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Service, error) {
+	sql, args, err := r.sqb.Select(serviceColumns...).
+		From("services s").
+		Where(sq.Eq{"s.id": id}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ServiceRepository.GetByID - ToSql: %w", err)
+	}
+
+	return r.getOne(ctx, "GetByID", sql, args)
+}
+
+// Resolve ищет сервис по названию или любому из псевдонимов без учёта
+// регистра.
+func (r *ServiceRepository) Resolve(ctx context.Context, name string) (*models.Service, error) {
+	sql, args, err := r.sqb.Select(serviceColumns...).
+		From("services s").
+		Join("service_aliases k ON k.service_id = s.id").
+		Where(sq.Eq{"k.alias_key": AliasKey(name)}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ServiceRepository.Resolve - ToSql: %w", err)
+	}
+
+	return r.getOne(ctx, "Resolve", sql, args)
+}
+
+func (r *ServiceRepository) getOne(ctx context.Context, method, sql string, args []any) (*models.Service, error) {
+	var svc models.Service
+	err := scanService(r.db.QueryRow(ctx, sql, args...), &svc)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrServiceNotFound
+		}
+		return nil, fmt.Errorf("ServiceRepository.%s - Scan: %w", method, err)
+	}
+
+	return &svc, nil
+}
+
+// List возвращает сервисы каталога, при необходимости только одной категории.
+func (r *ServiceRepository) List(ctx context.Context, category *string) ([]models.Service, error) {
+	queryBuilder := r.sqb.Select(serviceColumns...).
+		From("services s").
+		OrderBy("s.name")
+
+	if category != nil {
+		queryBuilder = queryBuilder.Where("lower(s.category) = lower(?)", *category)
+	}
+
+	sql, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("ServiceRepository.List - ToSql: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ServiceRepository.List - Query: %w", err)
+	}
+	defer rows.Close()
+
+	services := make([]models.Service, 0)
+	for rows.Next() {
+		var svc models.Service
+		if err := scanService(rows, &svc); err != nil {
+			return nil, fmt.Errorf("ServiceRepository.List - Scan: %w", err)
+		}
+		services = append(services, svc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ServiceRepository.List - Rows: %w", err)
+	}
+
+	return services, nil
+}
+
+// Update сохраняет изменения сервиса, заменяет набор псевдонимов и
+// привязывает подписки с новыми написаниями названия.
+func (r *ServiceRepository) Update(ctx context.Context, svc *models.Service) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ServiceRepository.Update - Begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	sql, args, err := r.sqb.Update("services").
+		Set("name", svc.Name).
+		Set("category", svc.Category).
+		Set("website", svc.Website).
+		Set("default_price", svc.DefaultPrice).
+		Set("currency", svc.Currency).
+		Where(sq.Eq{"id": svc.ID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ServiceRepository.Update - ToSql: %w", err)
+	}
+
+	res, err := tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ServiceRepository.Update - Exec: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return ErrServiceNotFound
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM service_aliases WHERE service_id = $1", svc.ID); err != nil {
+		return fmt.Errorf("ServiceRepository.Update - Exec: %w", err)
+	}
+
+	if err := r.saveAliases(ctx, tx, svc); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("ServiceRepository.Update - Commit: %w", err)
+	}
+
+	return nil
+}
+
+// Delete удаляет сервис из каталога. Подписки остаются, но теряют привязку.
+func (r *ServiceRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	sql, args, err := r.sqb.Delete("services").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ServiceRepository.Delete - ToSql: %w", err)
+	}
+
+	res, err := r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("ServiceRepository.Delete - Exec: %w", err)
+	}
+
+	if res.RowsAffected() == 0 {
+		return ErrServiceNotFound
+	}
+
+	return nil
+}
+
+// saveAliases записывает название и псевдонимы сервиса в service_aliases,
+// привязывает к сервису ещё не привязанные подписки с такими названиями и
+// приводит название всех его подписок к каноническому.
+func (r *ServiceRepository) saveAliases(ctx context.Context, tx pgx.Tx, svc *models.Service) error {
+	names := append([]string{svc.Name}, svc.Aliases...)
+	keys := make([]string, 0, len(names))
+
+	queryBuilder := r.sqb.Insert("service_aliases").Columns("alias_key", "service_id", "alias")
+	for _, name := range names {
+		key := AliasKey(name)
+		keys = append(keys, key)
+		queryBuilder = queryBuilder.Values(key, svc.ID, name)
+	}
+
+	sql, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("ServiceRepository.saveAliases - ToSql: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, sql, args...); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return ErrServiceConflict
+		}
+		return fmt.Errorf("ServiceRepository.saveAliases - Exec: %w", err)
+	}
+
+	sql, args, err = r.sqb.Update("subscriptions").
+		Set("service_id", svc.ID).
+		Set("service_name", svc.Name).
+		Where(sq.Or{
+			sq.And{sq.Eq{"service_id": nil}, sq.Expr("lower(service_name) = ANY(?)", keys)},
+			sq.Eq{"service_id": svc.ID},
+		}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("ServiceRepository.saveAliases - ToSql: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("ServiceRepository.saveAliases - Exec: %w", err)
+	}
+
+	return nil
+}
+
+func scanService(row pgx.Row, svc *models.Service) error {
+	return row.Scan(&svc.ID, &svc.Name, &svc.Aliases, &svc.Category, &svc.Website, &svc.DefaultPrice, &svc.Currency)
+}
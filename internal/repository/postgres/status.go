@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"effective-mobile-task/internal/models"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrStatusChanged означает, что состояние подписки изменилось с момента,
+// когда его прочитали, и переход нужно проверить заново.
+var ErrStatusChanged = errors.New("subscription status changed concurrently")
+
+// StatusTransition — переход подписки в новое состояние. EndDate заполняется
+// только при отмене и становится новым месяцем окончания подписки.
+type StatusTransition struct {
+	Change  models.StatusChange
+	UserID  uuid.UUID
+	EndDate *models.Month
+}
+
+// ChangeStatus применяет переход в одной транзакции: меняет состояние
+// подписки, открывает или закрывает период приостановки и записывает
+// переход в историю.
+func (r *SubscriptionRepository) ChangeStatus(ctx context.Context, t StatusTransition) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("SubscriptionRepository.ChangeStatus - Begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	c := t.Change
+
+	updateBuilder := r.sqb.Update("subscriptions").
+		Set("status", c.ToStatus).
+		Set("status_changed_at", c.ChangedAt).
+		Where(sq.Eq{"id": c.SubscriptionID, "status": c.FromStatus})
+	if t.EndDate != nil {
+		updateBuilder = updateBuilder.Set("end_date", *t.EndDate)
+	}
+
+	sql, args, err := updateBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("SubscriptionRepository.ChangeStatus - ToSql: %w", err)
+	}
+	res, err := tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("SubscriptionRepository.ChangeStatus - Exec: %w", err)
+	}
+	if res.RowsAffected() == 0 {
+		return ErrStatusChanged
+	}
+
+	switch {
+	case c.ToStatus == models.StatusPaused:
+		sql, args, err = r.sqb.Insert("subscription_pauses").
+			Columns("id", "subscription_id", "user_id", "start_month").
+			Values(uuid.New(), c.SubscriptionID, t.UserID, c.EffectiveMonth).
+			ToSql()
+	case c.FromStatus == models.StatusPaused && c.ToStatus == models.StatusActive:
+		// Возобновить подписку раньше начала паузы нельзя: пауза схлопывается.
+		sql, args, err = r.sqb.Update("subscription_pauses").
+			Set("end_month", sq.Expr("GREATEST(?::date, start_month)", c.EffectiveMonth)).
+			Where(sq.Eq{"subscription_id": c.SubscriptionID, "end_month": nil}).
+			ToSql()
+	default:
+		sql = ""
+	}
+	if err != nil {
+		return fmt.Errorf("SubscriptionRepository.ChangeStatus - ToSql: %w", err)
+	}
+	if sql != "" {
+		if _, err := tx.Exec(ctx, sql, args...); err != nil {
+			return fmt.Errorf("SubscriptionRepository.ChangeStatus - Exec pause: %w", err)
+		}
+	}
+
+	sql, args, err = r.sqb.Insert("subscription_status_changes").
+		Columns("id", "subscription_id", "user_id", "from_status", "to_status", "effective_month", "changed_at").
+		Values(c.ID, c.SubscriptionID, t.UserID, c.FromStatus, c.ToStatus, c.EffectiveMonth, c.ChangedAt).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("SubscriptionRepository.ChangeStatus - ToSql: %w", err)
+	}
+	if _, err := tx.Exec(ctx, sql, args...); err != nil {
+		return fmt.Errorf("SubscriptionRepository.ChangeStatus - Exec history: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("SubscriptionRepository.ChangeStatus - Commit: %w", err)
+	}
+
+	return nil
+}
+
+// StatusHistory возвращает переходы подписки в порядке их совершения.
+func (r *SubscriptionRepository) StatusHistory(ctx context.Context, subscriptionID uuid.UUID) ([]models.StatusChange, error) {
+	sql, args, err := r.sqb.Select("id", "subscription_id", "from_status", "to_status", "effective_month", "changed_at").
+		From("subscription_status_changes").
+		Where(sq.Eq{"subscription_id": subscriptionID}).
+		OrderBy("changed_at", "id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.StatusHistory - ToSql: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.StatusHistory - Query: %w", err)
+	}
+
+	history, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.StatusChange, error) {
+		var c models.StatusChange
+		err := row.Scan(&c.ID, &c.SubscriptionID, &c.FromStatus, &c.ToStatus, &c.EffectiveMonth, &c.ChangedAt)
+		return c, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.StatusHistory - Scan: %w", err)
+	}
+
+	return history, nil
+}
@@ -4,25 +4,25 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"effective-mobile-task/internal/models"
 	sq "github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 var ErrNotFound = errors.New("subscription not found")
 
 type SubscriptionRepository struct {
-	db *pgxpool.Pool
+	db *DB
 	
 	sqb sq.StatementBuilderType
 }
 
 
-func NewSubscriptionRepository(db *pgxpool.Pool) *SubscriptionRepository {
+func NewSubscriptionRepository(db *DB) *SubscriptionRepository {
 	return &SubscriptionRepository{
 		db:  db,
 		sqb: sq.StatementBuilder.PlaceholderFormat(sq.Dollar), 
@@ -31,15 +31,19 @@ func NewSubscriptionRepository(db *pgxpool.Pool) *SubscriptionRepository {
 
 
 func (r *SubscriptionRepository) Create(ctx context.Context, sub *models.Subscription) error {
+	return r.create(ctx, r.db, sub)
+}
+
+func (r *SubscriptionRepository) create(ctx context.Context, q querier, sub *models.Subscription) error {
 	sql, args, err := r.sqb.Insert("subscriptions").
-		Columns("id", "user_id", "service_name", "price", "start_date", "end_date").
-		Values(sub.ID, sub.UserID, sub.ServiceName, sub.Price, sub.StartDate, sub.EndDate).
+		Columns(subscriptionColumns...).
+		Values(subscriptionValues(sub)...).
 		ToSql()
 	if err != nil {
 		return fmt.Errorf("SubscriptionRepository.Create - ToSql: %w", err)
 	}
 
-	_, err = r.db.Exec(ctx, sql, args...)
+	_, err = q.Exec(ctx, sql, args...)
 	if err != nil {
 		return fmt.Errorf("SubscriptionRepository.Create - Exec: %w", err)
 	}
@@ -49,7 +53,23 @@ func (r *SubscriptionRepository) Create(ctx context.Context, sub *models.Subscri
 
 
 func (r *SubscriptionRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Subscription, error) {
-	sql, args, err := r.sqb.Select("id", "user_id", "service_name", "price", "start_date", "end_date").
+	return r.getByID(ctx, r.db, id)
+}
+
+// GetByIDAsOf возвращает версию подписки, действовавшую в момент asOf.
+// Если подписки тогда не было, возвращает ErrNotFound.
+func (r *SubscriptionRepository) GetByIDAsOf(ctx context.Context, id uuid.UUID, asOf time.Time) (*models.Subscription, error) {
+	reader, done, err := r.snapshot(ctx, &asOf)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	return r.getByID(ctx, reader, id)
+}
+
+func (r *SubscriptionRepository) getByID(ctx context.Context, q querier, id uuid.UUID) (*models.Subscription, error) {
+	sql, args, err := r.sqb.Select(subscriptionSelectColumns...).
 		From("subscriptions").
 		Where(sq.Eq{"id": id}).
 		ToSql()
@@ -58,14 +78,7 @@ func (r *SubscriptionRepository) GetByID(ctx context.Context, id uuid.UUID) (*mo
 	}
 
 	var sub models.Subscription
-	err = r.db.QueryRow(ctx, sql, args...).Scan(
-		&sub.ID,
-		&sub.UserID,
-		&sub.ServiceName,
-		&sub.Price,
-		&sub.StartDate,
-		&sub.EndDate,
-	)
+	err = scanSubscription(q.QueryRow(ctx, sql, args...), &sub)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrNotFound
@@ -77,18 +90,16 @@ func (r *SubscriptionRepository) GetByID(ctx context.Context, id uuid.UUID) (*mo
 }
 
 func (r *SubscriptionRepository) Update(ctx context.Context, sub *models.Subscription) error {
-	sql, args, err := r.sqb.Update("subscriptions").
-		Set("service_name", sub.ServiceName).
-		Set("price", sub.Price).
-		Set("start_date", sub.StartDate).
-		Set("end_date", sub.EndDate).
-		Where(sq.Eq{"id": sub.ID}).
-		ToSql()
+	return r.update(ctx, r.db, sub)
+}
+
+func (r *SubscriptionRepository) update(ctx context.Context, q querier, sub *models.Subscription) error {
+	sql, args, err := r.updateQuery(sub)
 	if err != nil {
 		return fmt.Errorf("SubscriptionRepository.Update - ToSql: %w", err)
 	}
 
-	res, err := r.db.Exec(ctx, sql, args...)
+	res, err := q.Exec(ctx, sql, args...)
 	if err != nil {
 		return fmt.Errorf("SubscriptionRepository.Update - Exec: %w", err)
 	}
@@ -101,14 +112,16 @@ func (r *SubscriptionRepository) Update(ctx context.Context, sub *models.Subscri
 }
 
 func (r *SubscriptionRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	sql, args, err := r.sqb.Delete("subscriptions").
-		Where(sq.Eq{"id": id}).
-		ToSql()
+	return r.delete(ctx, r.db, id)
+}
+
+func (r *SubscriptionRepository) delete(ctx context.Context, q querier, id uuid.UUID) error {
+	sql, args, err := r.deleteQuery(id)
 	if err != nil {
 		return fmt.Errorf("SubscriptionRepository.Delete - ToSql: %w", err)
 	}
 
-	res, err := r.db.Exec(ctx, sql, args...)
+	res, err := q.Exec(ctx, sql, args...)
 	if err != nil {
 		return fmt.Errorf("SubscriptionRepository.Delete - Exec: %w", err)
 	}
@@ -120,39 +133,407 @@ func (r *SubscriptionRepository) Delete(ctx context.Context, id uuid.UUID) error
 	return nil
 }
 
+// updateQuery обновляет подписку. Изменение цены места или числа мест не
+// перезаписывает исходные значения, а записывается в историю с текущего
+// месяца.
+func (r *SubscriptionRepository) updateQuery(sub *models.Subscription) (string, []any, error) {
+	cte, cteArgs := pricingChanges(sub)
+	return r.sqb.Update("subscriptions").
+		Prefix(cte, cteArgs...).
+		Set("service_name", sub.ServiceName).
+		Set("service_id", sub.ServiceID).
+		Set("start_date", sub.StartDate).
+		Set("end_date", sub.EndDate).
+		Set("trial_end_date", sub.TrialEndDate).
+		Where(sq.Eq{"id": sub.ID}).
+		ToSql()
+}
+
+func (r *SubscriptionRepository) deleteQuery(id uuid.UUID) (string, []any, error) {
+	return r.sqb.Delete("subscriptions").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+}
+
+// OverlapQuery описывает период подписки, для которого ищется пересечение
+// с уже существующими подписками пользователя на тот же сервис.
+type OverlapQuery struct {
+	UserID      uuid.UUID
+	ServiceName string
+	StartDate   models.Month
+	EndDate     *models.Month
+	// ExcludeIDs — подписки, которые не считаются пересечениями: сама
+	// проверяемая подписка и те, что заменяются вместе с ней.
+	ExcludeIDs []uuid.UUID
+}
+
+func (r *SubscriptionRepository) FindOverlapping(ctx context.Context, q OverlapQuery) (*models.Subscription, error) {
+	queryBuilder := r.sqb.Select(subscriptionSelectColumns...).
+		From("subscriptions").
+		Where(sq.Eq{"user_id": q.UserID}).
+		Where("lower(service_name) = lower(?)", q.ServiceName).
+		Where("start_date <= COALESCE(?::date, 'infinity'::date)", q.EndDate).
+		Where("COALESCE(end_date, 'infinity'::date) >= ?", q.StartDate).
+		OrderBy("start_date").
+		Limit(1)
+
+	if len(q.ExcludeIDs) > 0 {
+		queryBuilder = queryBuilder.Where(sq.NotEq{"id": q.ExcludeIDs})
+	}
+
+	sql, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.FindOverlapping - ToSql: %w", err)
+	}
+
+	var sub models.Subscription
+	err = scanSubscription(r.db.QueryRow(ctx, sql, args...), &sub)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("SubscriptionRepository.FindOverlapping - Scan: %w", err)
+	}
+
+	return &sub, nil
+}
+
+// OverlapKey — пользователь и сервис, подписки на который у пользователя
+// не должны пересекаться.
+type OverlapKey struct {
+	UserID      uuid.UUID
+	ServiceName string
+}
+
+// overlapLockKey — ключ блокировки пересечений для строки с колонками
+// user_id и service_name. Совпадение хэшей разных пар лишь заставляет их
+// ждать друг друга.
+const overlapLockKey = "hashtext(current_setting('app.tenant_id', true) || ':' || user_id || ':' || lower(service_name))"
+
+// lockOverlaps блокирует до конца транзакции пары пользователь — сервис,
+// которые выбирает keys — запрос с колонками user_id и service_name.
+// Блокировки берутся в одном порядке, чтобы транзакции не ждали друг
+// друга по кругу.
+func lockOverlaps(ctx context.Context, q querier, keys string, args ...any) error {
+	_, err := q.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtext('subscriptions_overlap'), k.key)"+
+		" FROM (SELECT DISTINCT "+overlapLockKey+" AS key FROM ("+keys+") keys) k ORDER BY k.key", args...)
+	return err
+}
+
+// LockOverlaps выполняет fn в транзакции, заблокировав подписки
+// пользователей на сервисы из keys: другая транзакция, блокирующая те же
+// пары, ждёт конца этой. Запросы с контекстом, переданным в fn, выполняются
+// в этой транзакции, поэтому проверка пересечений и запись подписки не
+// разделяются чужой записью.
+func (r *SubscriptionRepository) LockOverlaps(ctx context.Context, keys []OverlapKey, fn func(ctx context.Context) error) error {
+	return r.db.InTx(ctx, func(ctx context.Context) error {
+		users := make([]uuid.UUID, len(keys))
+		names := make([]string, len(keys))
+		for i, k := range keys {
+			users[i], names[i] = k.UserID, k.ServiceName
+		}
+
+		err := lockOverlaps(ctx, r.db, "SELECT * FROM unnest($1::uuid[], $2::text[]) AS k(user_id, service_name)", users, names)
+		if err != nil {
+			return fmt.Errorf("SubscriptionRepository.LockOverlaps - Exec: %w", err)
+		}
+
+		return fn(ctx)
+	})
+}
+
 type GetSummaryFilter struct {
 	UserID      *uuid.UUID
 	ServiceName *string
-	StartDate   *time.Time 
-	EndDate     *time.Time 
+	// ServiceNameLike отбирает подписки, название которых начинается с
+	// указанной строки или похоже на неё (pg_trgm), без учёта регистра.
+	ServiceNameLike *string
+	Category        *string
+	// Tags отбирает подписки с метками: с любой из них (TagsMatchAny) или
+	// со всеми сразу (TagsMatchAll).
+	Tags      []string
+	TagsMatch TagsMatch
+	StartDate *models.Month
+	EndDate   *models.Month
+	// AsOf считает по версиям подписок, действовавшим в этот момент.
+	// Период расчёта от него не зависит.
+	AsOf *time.Time
 }
 
-func (r *SubscriptionRepository) GetSummary(ctx context.Context, filter GetSummaryFilter) (int, error) {
-	queryBuilder := r.sqb.Select("COALESCE(SUM(price), 0)").From("subscriptions")
+type TagsMatch string
+
+const (
+	TagsMatchAny TagsMatch = "any"
+	TagsMatchAll TagsMatch = "all"
+)
 
+// applySummaryFilter добавляет к запросу условия фильтра сводки.
+func applySummaryFilter(queryBuilder sq.SelectBuilder, filter GetSummaryFilter) sq.SelectBuilder {
 	if filter.UserID != nil {
 		queryBuilder = queryBuilder.Where(sq.Eq{"user_id": *filter.UserID})
 	}
 	if filter.ServiceName != nil {
 		queryBuilder = queryBuilder.Where(sq.Eq{"service_name": *filter.ServiceName})
 	}
+	if filter.ServiceNameLike != nil {
+		queryBuilder = queryBuilder.Where(serviceNameMatch(*filter.ServiceNameLike))
+	}
+	if filter.Category != nil {
+		queryBuilder = queryBuilder.Where("service_id IN (SELECT id FROM services WHERE lower(category) = lower(?))", *filter.Category)
+	}
+	if len(filter.Tags) > 0 {
+		queryBuilder = queryBuilder.Where(tagsMatch(filter.Tags, filter.TagsMatch))
+	}
 	if filter.StartDate != nil {
 		queryBuilder = queryBuilder.Where(sq.GtOrEq{"start_date": *filter.StartDate})
 	}
 	if filter.EndDate != nil {
 		queryBuilder = queryBuilder.Where(sq.LtOrEq{"start_date": *filter.EndDate})
 	}
+	return queryBuilder
+}
+
+// tagsMatch — условие на метки подписки.
+func tagsMatch(tags []string, match TagsMatch) sq.Sqlizer {
+	if match == TagsMatchAll {
+		return sq.Expr("subscriptions.id IN (SELECT st.subscription_id FROM subscription_tags st"+
+			" JOIN tags t ON t.id = st.tag_id WHERE t.name = ANY(?)"+
+			" GROUP BY st.subscription_id HAVING count(*) = ?)", tags, len(tags))
+	}
+	return sq.Expr("subscriptions.id IN (SELECT st.subscription_id FROM subscription_tags st"+
+		" JOIN tags t ON t.id = st.tag_id WHERE t.name = ANY(?))", tags)
+}
+
+// serviceNameMatch — условие нечёткого совпадения названия сервиса с text.
+func serviceNameMatch(text string) sq.Sqlizer {
+	return sq.Or{
+		sq.Expr("lower(service_name) LIKE ?", likePrefix(text)),
+		sq.Expr("lower(service_name) % lower(?)", text),
+	}
+}
+
+// likePrefix превращает строку в шаблон LIKE для поиска по префиксу,
+// экранируя спецсимволы шаблона.
+func likePrefix(text string) string {
+	return likeEscaper.Replace(strings.ToLower(text)) + "%"
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// SearchQuery — параметры нечёткого поиска подписок по названию сервиса.
+type SearchQuery struct {
+	Text   string
+	Filter GetSummaryFilter
+	Limit  int
+}
+
+// SearchSubscriptions ищет подписки по названию сервиса. Сначала идут
+// совпадения по префиксу, затем остальные по убыванию сходства pg_trgm.
+func (r *SubscriptionRepository) SearchSubscriptions(ctx context.Context, q SearchQuery) ([]models.SubscriptionMatch, error) {
+	queryBuilder := applySummaryFilter(
+		r.sqb.Select(subscriptionSelectColumns...).
+			Column(sq.Expr("similarity(lower(service_name), lower(?)) AS score", q.Text)).
+			From("subscriptions").
+			Where(serviceNameMatch(q.Text)),
+		q.Filter,
+	).
+		OrderByClause("lower(service_name) LIKE ? DESC", likePrefix(q.Text)).
+		OrderBy("score DESC", "service_name", "id").
+		Limit(uint64(q.Limit))
+
+	sql, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.SearchSubscriptions - ToSql: %w", err)
+	}
+
+	reader, done, err := r.snapshot(ctx, q.Filter.AsOf)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	rows, err := reader.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.SearchSubscriptions - Query: %w", err)
+	}
+	defer rows.Close()
+
+	matches := make([]models.SubscriptionMatch, 0)
+	for rows.Next() {
+		var m models.SubscriptionMatch
+		if err := scanSubscription(rows, &m.Subscription, &m.Score); err != nil {
+			return nil, fmt.Errorf("SubscriptionRepository.SearchSubscriptions - Scan: %w", err)
+		}
+		matches = append(matches, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.SearchSubscriptions - Rows: %w", err)
+	}
+
+	return matches, nil
+}
+
+// GetSummary считает, сколько стоили подписки за период фильтра: цена
+// каждой подписки умножается на число оплачиваемых месяцев в периоде.
+// Возвращает стоимость до скидок и после них. Если фильтр позволяет, сводка
+// читается из monthly_spend.
+func (r *SubscriptionRepository) GetSummary(ctx context.Context, filter GetSummaryFilter) (*models.SummaryTotals, error) {
+	if rollupSummary(filter) {
+		totals, ok, err := r.summaryFromRollup(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return totals, nil
+		}
+	}
+
+	queryBuilder := r.sqb.Select("COALESCE(SUM(c.gross), 0)", "COALESCE(SUM(c.amount), 0)").
+		FromSelect(r.monthlyCosts(filter), "c")
 
 	sql, args, err := queryBuilder.ToSql()
 	if err != nil {
-		return 0, fmt.Errorf("SubscriptionRepository.GetSummary - ToSql: %w", err)
+		return nil, fmt.Errorf("SubscriptionRepository.GetSummary - ToSql: %w", err)
 	}
 
-	var total int
-	err = r.db.QueryRow(ctx, sql, args...).Scan(&total)
+	reader, done, err := r.snapshot(ctx, filter.AsOf)
 	if err != nil {
-		return 0, fmt.Errorf("SubscriptionRepository.GetSummary - Scan: %w", err)
+		return nil, err
 	}
+	defer done()
 
-	return total, nil
+	var totals models.SummaryTotals
+	err = reader.QueryRow(ctx, sql, args...).Scan(&totals.Gross, &totals.Net)
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.GetSummary - Scan: %w", err)
+	}
+	totals.Discount = totals.Gross - totals.Net
+
+	return &totals, nil
+}
+
+// GetSummaryByCategory считает стоимость подписок за период фильтра отдельно
+// по каждой категории каталога. Подписки без сервиса из каталога или без
+// категории попадают в группу с пустой категорией.
+func (r *SubscriptionRepository) GetSummaryByCategory(ctx context.Context, filter GetSummaryFilter) ([]models.CategorySummary, error) {
+	queryBuilder := r.sqb.Select("services.category", "COALESCE(SUM(c.amount), 0)").
+		FromSelect(r.monthlyCosts(filter), "c").
+		LeftJoin("services ON services.id = c.service_id").
+		GroupBy("services.category").
+		OrderBy("2 DESC", "1")
+
+	sql, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.GetSummaryByCategory - ToSql: %w", err)
+	}
+
+	reader, done, err := r.snapshot(ctx, filter.AsOf)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	rows, err := reader.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.GetSummaryByCategory - Query: %w", err)
+	}
+	defer rows.Close()
+
+	summaries := make([]models.CategorySummary, 0)
+	for rows.Next() {
+		var summary models.CategorySummary
+		if err := rows.Scan(&summary.Category, &summary.TotalPrice); err != nil {
+			return nil, fmt.Errorf("SubscriptionRepository.GetSummaryByCategory - Scan: %w", err)
+		}
+		summaries = append(summaries, summary)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.GetSummaryByCategory - Rows: %w", err)
+	}
+
+	return summaries, nil
+}
+
+// ListSubscriptions возвращает страницу подписок, подходящих под фильтр.
+func (r *SubscriptionRepository) ListSubscriptions(ctx context.Context, filter GetSummaryFilter, limit, offset int) ([]models.Subscription, error) {
+	queryBuilder := applySummaryFilter(r.sqb.Select(subscriptionSelectColumns...).From("subscriptions"), filter).
+		OrderBy("user_id", "start_date", "id").
+		Limit(uint64(limit)).
+		Offset(uint64(offset))
+
+	sql, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.ListSubscriptions - ToSql: %w", err)
+	}
+
+	reader, done, err := r.snapshot(ctx, filter.AsOf)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	rows, err := reader.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.ListSubscriptions - Query: %w", err)
+	}
+	defer rows.Close()
+
+	subs := make([]models.Subscription, 0)
+	for rows.Next() {
+		var sub models.Subscription
+		if err := scanSubscription(rows, &sub); err != nil {
+			return nil, fmt.Errorf("SubscriptionRepository.ListSubscriptions - Scan: %w", err)
+		}
+		subs = append(subs, sub)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.ListSubscriptions - Rows: %w", err)
+	}
+
+	return subs, nil
+}
+
+// ExportSubscriptions передаёт в fn по одной подписке, подходящие под фильтр.
+// Строки читаются из курсора pgx по мере поступления, поэтому выгрузка
+// любого размера не накапливается в памяти. Ошибка из fn прерывает чтение.
+func (r *SubscriptionRepository) ExportSubscriptions(ctx context.Context, filter GetSummaryFilter, fn func(*models.Subscription) error) error {
+	queryBuilder := applySummaryFilter(r.sqb.Select(subscriptionSelectColumns...).From("subscriptions"), filter).
+		OrderBy("user_id", "start_date", "id")
+
+	sql, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("SubscriptionRepository.ExportSubscriptions - ToSql: %w", err)
+	}
+
+	reader, done, err := r.snapshot(ctx, filter.AsOf)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	rows, err := reader.Query(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("SubscriptionRepository.ExportSubscriptions - Query: %w", err)
+	}
+	defer rows.Close()
+
+	var sub models.Subscription
+	for rows.Next() {
+		sub.ServiceID, sub.EndDate, sub.TrialEndDate, sub.StatusChangedAt, sub.Tags, sub.CostCentreID = nil, nil, nil, nil, nil, nil
+		if err := scanSubscription(rows, &sub); err != nil {
+			return fmt.Errorf("SubscriptionRepository.ExportSubscriptions - Scan: %w", err)
+		}
+		if err := fn(&sub); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("SubscriptionRepository.ExportSubscriptions - Rows: %w", err)
+	}
+
+	return nil
 }
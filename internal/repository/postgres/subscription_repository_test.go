@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"effective-mobile-task/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptionRepository_LockOverlapsSerializesCheckAndWrite(t *testing.T) {
+	db := testDB(t)
+	ctx := testTenant(t, db)
+	repo := NewSubscriptionRepository(db)
+	userID := uuid.New()
+	month := models.MonthOf(time.Now())
+	key := OverlapKey{UserID: userID, ServiceName: "Netflix"}
+
+	// Обе транзакции проверяют пересечение и только потом пишут; без
+	// блокировки обе увидели бы пустую таблицу.
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = repo.LockOverlaps(ctx, []OverlapKey{key}, func(ctx context.Context) error {
+				_, err := repo.FindOverlapping(ctx, OverlapQuery{UserID: userID, ServiceName: "netflix", StartDate: month})
+				if !errors.Is(err, ErrNotFound) {
+					return err
+				}
+				time.Sleep(100 * time.Millisecond)
+				return repo.Create(ctx, &models.Subscription{ID: uuid.New(), UserID: userID, ServiceName: "Netflix", Price: 400, StartDate: month})
+			})
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+	var created int
+	require.NoError(t, db.QueryRow(ctx, "SELECT count(*) FROM subscriptions WHERE user_id = $1", userID).Scan(&created))
+	assert.Equal(t, 1, created)
+}
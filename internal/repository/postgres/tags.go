@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// AddTags вешает метки на подписку и возвращает все её метки. Метки, которых
+// ещё нет у владельца подписки, создаются; уже висящие на подписке метки
+// пропускаются.
+func (r *SubscriptionRepository) AddTags(ctx context.Context, subscriptionID uuid.UUID, tags []string) ([]string, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.AddTags - Begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	userID, err := r.subscriptionOwner(ctx, tx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	insertTags := r.sqb.Insert("tags").Columns("id", "user_id", "name")
+	for _, tag := range tags {
+		insertTags = insertTags.Values(uuid.New(), userID, tag)
+	}
+	sql, args, err := insertTags.Suffix("ON CONFLICT (tenant_id, user_id, name) DO NOTHING").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.AddTags - ToSql: %w", err)
+	}
+	if _, err := tx.Exec(ctx, sql, args...); err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.AddTags - Exec: %w", err)
+	}
+
+	sql, args, err = r.sqb.Insert("subscription_tags").
+		Columns("subscription_id", "tag_id", "user_id").
+		Select(r.sqb.Select().
+			Column(sq.Expr("?::uuid", subscriptionID)).
+			Columns("id", "user_id").
+			From("tags").
+			Where(sq.Eq{"user_id": userID}).
+			Where("name = ANY(?)", tags)).
+		Suffix("ON CONFLICT DO NOTHING").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.AddTags - ToSql: %w", err)
+	}
+	if _, err := tx.Exec(ctx, sql, args...); err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.AddTags - Exec: %w", err)
+	}
+
+	result, err := r.subscriptionTags(ctx, tx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.AddTags - Commit: %w", err)
+	}
+
+	return result, nil
+}
+
+// RemoveTag снимает метку с подписки и возвращает оставшиеся метки. Сама
+// метка пользователя остаётся, даже если больше ни на чём не висит.
+func (r *SubscriptionRepository) RemoveTag(ctx context.Context, subscriptionID uuid.UUID, tag string) ([]string, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.RemoveTag - Begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := r.subscriptionOwner(ctx, tx, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	sql, args, err := r.sqb.Delete("subscription_tags").
+		Where(sq.Eq{"subscription_id": subscriptionID}).
+		Where("tag_id IN (SELECT id FROM tags WHERE name = ?)", tag).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.RemoveTag - ToSql: %w", err)
+	}
+	if _, err := tx.Exec(ctx, sql, args...); err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.RemoveTag - Exec: %w", err)
+	}
+
+	result, err := r.subscriptionTags(ctx, tx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.RemoveTag - Commit: %w", err)
+	}
+
+	return result, nil
+}
+
+// subscriptionOwner возвращает владельца подписки и блокирует её строку до
+// конца транзакции, чтобы подписку не удалили, пока меняются её метки.
+func (r *SubscriptionRepository) subscriptionOwner(ctx context.Context, q querier, subscriptionID uuid.UUID) (uuid.UUID, error) {
+	var userID uuid.UUID
+	err := q.QueryRow(ctx, "SELECT user_id FROM subscriptions WHERE id = $1 FOR SHARE", subscriptionID).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, ErrNotFound
+		}
+		return uuid.Nil, fmt.Errorf("SubscriptionRepository.subscriptionOwner - Scan: %w", err)
+	}
+	return userID, nil
+}
+
+func (r *SubscriptionRepository) subscriptionTags(ctx context.Context, q querier, subscriptionID uuid.UUID) ([]string, error) {
+	rows, err := q.Query(ctx, "SELECT t.name FROM subscription_tags st JOIN tags t ON t.id = st.tag_id"+
+		" WHERE st.subscription_id = $1 ORDER BY t.name", subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.subscriptionTags - Query: %w", err)
+	}
+
+	tags, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.subscriptionTags - Collect: %w", err)
+	}
+	return tags, nil
+}
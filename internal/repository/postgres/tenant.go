@@ -0,0 +1,261 @@
+package postgres
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"effective-mobile-task/internal/auth"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DefaultTenantID — арендатор, которому принадлежат данные, созданные до
+// появления арендаторов.
+var DefaultTenantID = uuid.Nil
+
+// ErrTenantNotFound означает, что арендатора с таким ID или токеном нет.
+var ErrTenantNotFound = errors.New("tenant not found")
+
+type (
+	tenantIDKey struct{}
+	txKey       struct{}
+)
+
+// tenantSetting — ключ, под которым в CustomData соединения запоминается
+// заданный в нём арендатор.
+const tenantSetting = "app.tenant_id"
+
+// DB — пул соединений с учётом арендатора. Соединение, выданное пулом для
+// запроса или транзакции, получает арендатора из контекста, к которому его
+// привязал WithTenant: в соединении задаётся переменная app.tenant_id, и
+// политики RLS пропускают только строки арендатора. Запросы с любым другим
+// контекстом выполняются без арендатора и не видят ни одной строки.
+// Соединение занято только на время запроса или транзакции, а не всего
+// HTTP-запроса. Запросы с контекстом, к которому InTx привязал транзакцию,
+// выполняются в ней. Пул должен быть создан NewPostgresDB.
+type DB struct {
+	pool *pgxpool.Pool
+}
+
+func NewDB(pool *pgxpool.Pool) *DB {
+	return &DB{pool: pool}
+}
+
+// conn — соединение или транзакция, через которые DB выполняет запросы.
+type conn interface {
+	querier
+	Begin(ctx context.Context) (pgx.Tx, error)
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+}
+
+// txConn — транзакция, которую InTx привязал к контексту. Транзакции,
+// начатые в ней, становятся точками сохранения и наследуют её параметры.
+type txConn struct {
+	pgx.Tx
+}
+
+func (c txConn) BeginTx(ctx context.Context, _ pgx.TxOptions) (pgx.Tx, error) {
+	return c.Begin(ctx)
+}
+
+func (db *DB) conn(ctx context.Context) conn {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return txConn{tx}
+	}
+	return db.pool
+}
+
+func (db *DB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return db.conn(ctx).Exec(ctx, sql, args...)
+}
+
+func (db *DB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return db.conn(ctx).Query(ctx, sql, args...)
+}
+
+func (db *DB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return db.conn(ctx).QueryRow(ctx, sql, args...)
+}
+
+func (db *DB) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	return db.conn(ctx).CopyFrom(ctx, tableName, columnNames, rowSrc)
+}
+
+func (db *DB) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	return db.conn(ctx).SendBatch(ctx, b)
+}
+
+func (db *DB) Begin(ctx context.Context) (pgx.Tx, error) {
+	return db.conn(ctx).Begin(ctx)
+}
+
+func (db *DB) BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
+	return db.conn(ctx).BeginTx(ctx, txOptions)
+}
+
+// InTx выполняет fn в транзакции. Запросы с контекстом, переданным в fn,
+// выполняются в этой транзакции, а транзакции, начатые в ней, становятся
+// точками сохранения. Ошибка fn откатывает транзакцию.
+func (db *DB) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("DB.InTx - Begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("DB.InTx - Commit: %w", err)
+	}
+
+	return nil
+}
+
+// WithTenant возвращает контекст, запросы с которым выполняются от имени
+// арендатора tenantID. Если арендатора нет, возвращает ErrTenantNotFound.
+func (db *DB) WithTenant(ctx context.Context, tenantID uuid.UUID) (context.Context, error) {
+	var exists bool
+	err := db.pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM tenants WHERE id = $1)", tenantID).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("DB.WithTenant - Scan: %w", err)
+	}
+	if !exists {
+		return nil, ErrTenantNotFound
+	}
+
+	return context.WithValue(ctx, tenantIDKey{}, tenantID), nil
+}
+
+// TenantFromContext возвращает арендатора, к которому WithTenant привязал
+// контекст.
+func TenantFromContext(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(tenantIDKey{}).(uuid.UUID)
+	return id, ok
+}
+
+// prepareTenant задаёт в выдаваемом пулом соединении арендатора из
+// контекста запроса, а без арендатора сбрасывает его. Повторно тот же
+// арендатор не задаётся. Соединение, в котором задать арендатора не
+// удалось, закрывается, чтобы запрос не выполнился от имени чужого
+// арендатора.
+func prepareTenant(ctx context.Context, c *pgx.Conn) (bool, error) {
+	tenant := ""
+	if id, ok := TenantFromContext(ctx); ok {
+		tenant = id.String()
+	}
+
+	data := c.PgConn().CustomData()
+	if current, ok := data[tenantSetting].(string); ok && current == tenant {
+		return true, nil
+	}
+
+	if _, err := c.Exec(ctx, "SELECT set_config('app.tenant_id', $1, false)", tenant); err != nil {
+		return false, fmt.Errorf("DB - set tenant: %w", err)
+	}
+	data[tenantSetting] = tenant
+
+	return true, nil
+}
+
+// ResolveToken возвращает, от чьего имени действует владелец токена
+// доступа. Токен арендатора даёт права администратора без привязки к
+// пользователю, токен пользователя — его роль.
+func (db *DB) ResolveToken(ctx context.Context, token string) (auth.Principal, error) {
+	sum := sha256.Sum256([]byte(token))
+
+	var (
+		p    auth.Principal
+		role string
+	)
+	err := db.pool.QueryRow(ctx, `
+		SELECT id, NULL::uuid, 'admin' FROM tenants WHERE api_token_hash = $1
+		UNION ALL
+		SELECT tenant_id, user_id, role FROM access_tokens WHERE token_hash = $1
+		LIMIT 1`, hex.EncodeToString(sum[:])).Scan(&p.TenantID, &p.UserID, &role)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return auth.Principal{}, ErrTenantNotFound
+		}
+		return auth.Principal{}, fmt.Errorf("DB.ResolveToken - Scan: %w", err)
+	}
+	p.Role = auth.Role(role)
+
+	return p, nil
+}
+
+// EachTenant по очереди выполняет fn от имени каждого арендатора. Ошибка
+// одного арендатора не мешает остальным; все ошибки возвращаются вместе.
+func (db *DB) EachTenant(ctx context.Context, fn func(ctx context.Context) error) error {
+	rows, err := db.pool.Query(ctx, "SELECT id FROM tenants ORDER BY id")
+	if err != nil {
+		return fmt.Errorf("DB.EachTenant - Query: %w", err)
+	}
+	tenants, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return fmt.Errorf("DB.EachTenant - Scan: %w", err)
+	}
+
+	var errs []error
+	for _, tenantID := range tenants {
+		if ctx.Err() != nil {
+			break
+		}
+
+		if err := fn(context.WithValue(ctx, tenantIDKey{}, tenantID)); err != nil {
+			errs = append(errs, fmt.Errorf("арендатор %s: %w", tenantID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// listenRetryDelay — пауза перед повторной подпиской после потери
+// соединения.
+const listenRetryDelay = 3 * time.Second
+
+// Listen подписывается на уведомления канала channel и передаёт их
+// содержимое в fn, пока не отменён ctx. Для подписки держится отдельное
+// соединение; если оно теряется, Listen подписывается заново, а onError
+// получает ошибку. Уведомления, отправленные без подписки, теряются.
+func (db *DB) Listen(ctx context.Context, channel string, fn func(payload string), onError func(error)) {
+	for ctx.Err() == nil {
+		if err := db.listen(ctx, channel, fn); err != nil && ctx.Err() == nil {
+			onError(err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(listenRetryDelay):
+			}
+		}
+	}
+}
+
+func (db *DB) listen(ctx context.Context, channel string, fn func(payload string)) error {
+	c, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("DB.Listen - Acquire: %w", err)
+	}
+	// Соединение с подпиской не возвращается в пул.
+	conn := c.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return fmt.Errorf("DB.Listen - Exec: %w", err)
+	}
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("DB.Listen - WaitForNotification: %w", err)
+		}
+		fn(n.Payload)
+	}
+}
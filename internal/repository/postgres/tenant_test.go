@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"effective-mobile-task/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDB_TenantRowsInvisibleToOtherTenants(t *testing.T) {
+	db := testDB(t)
+	// Суперпользователь видит строки всех арендаторов, и проверка ниже
+	// ничего бы не доказала.
+	require.NoError(t, checkRole(db.pool), "TEST_DATABASE_URL должен подключаться ролью приложения")
+
+	ctxA := testTenant(t, db)
+	ctxB := testTenant(t, db)
+	subscriptions := NewSubscriptionRepository(db)
+
+	sub := &models.Subscription{
+		ID:          uuid.New(),
+		UserID:      uuid.New(),
+		ServiceName: "Yandex Plus",
+		Price:       400,
+		StartDate:   models.MonthOf(time.Now()),
+	}
+	require.NoError(t, subscriptions.Create(ctxB, sub))
+
+	_, err := subscriptions.GetByID(ctxA, sub.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Даже запрос без условия на арендатора не видит чужих строк.
+	var visible int
+	require.NoError(t, db.QueryRow(ctxA, "SELECT count(*) FROM subscriptions WHERE id = $1", sub.ID).Scan(&visible))
+	assert.Zero(t, visible)
+
+	res, err := db.Exec(ctxA, "UPDATE subscriptions SET service_name = 'changed' WHERE id = $1", sub.ID)
+	require.NoError(t, err)
+	assert.Zero(t, res.RowsAffected())
+
+	stored, err := subscriptions.GetByID(ctxB, sub.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Yandex Plus", stored.ServiceName)
+}
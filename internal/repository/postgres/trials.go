@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"effective-mobile-task/internal/models"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+)
+
+// trialConversionAt — момент, когда подписка становится платной: начало
+// месяца, следующего за пробным периодом. Месяцы хранятся в UTC.
+const trialConversionAt = "((subscriptions.trial_end_date + interval '1 month') AT TIME ZONE 'UTC')"
+
+// TrialQuery выбирает подписки, которые становятся платными в промежутке
+// (From, To]. Без UserID выбираются подписки всех пользователей.
+type TrialQuery struct {
+	From   time.Time
+	To     time.Time
+	UserID *uuid.UUID
+}
+
+// ConvertingTrials возвращает активные подписки, пробный период которых
+// заканчивается в заданный промежуток, в порядке перехода на оплату.
+// Подписки, которые закончатся вместе с пробным периодом, не попадают в
+// выборку: платить по ним не придётся.
+func (r *SubscriptionRepository) ConvertingTrials(ctx context.Context, q TrialQuery) ([]models.TrialConversion, error) {
+	queryBuilder := r.sqb.Select(subscriptionSelectColumns...).
+		Column(trialConversionAt+" AS converts_at").
+		From("subscriptions").
+		Where("subscriptions.trial_end_date IS NOT NULL").
+		Where(effectiveStatus+" = ?", models.StatusActive).
+		Where("(subscriptions.end_date IS NULL OR subscriptions.end_date > subscriptions.trial_end_date)").
+		Where(trialConversionAt+" > ?", q.From).
+		Where(trialConversionAt+" <= ?", q.To).
+		OrderBy("converts_at", "id")
+	if q.UserID != nil {
+		queryBuilder = queryBuilder.Where(sq.Eq{"user_id": *q.UserID})
+	}
+
+	sql, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.ConvertingTrials - ToSql: %w", err)
+	}
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.ConvertingTrials - Query: %w", err)
+	}
+	defer rows.Close()
+
+	trials := make([]models.TrialConversion, 0)
+	for rows.Next() {
+		var t models.TrialConversion
+		if err := scanSubscription(rows, &t.Subscription, &t.ConvertsAt); err != nil {
+			return nil, fmt.Errorf("SubscriptionRepository.ConvertingTrials - Scan: %w", err)
+		}
+		trials = append(trials, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("SubscriptionRepository.ConvertingTrials - Rows: %w", err)
+	}
+
+	return trials, nil
+}
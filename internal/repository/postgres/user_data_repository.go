@@ -0,0 +1,220 @@
+package postgres
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"effective-mobile-task/internal/models"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// personalDataTable — таблица, в которой есть данные пользователя.
+type personalDataTable struct {
+	// section — имя раздела в архиве выгрузки.
+	section    string
+	table      string
+	userColumn string
+}
+
+// personalDataTables перечисляет все таблицы с данными пользователя.
+// Новая таблица, ссылающаяся на пользователя, должна быть добавлена сюда,
+// иначе она не попадёт ни в выгрузку, ни в удаление. Таблицы истории
+// стоят первыми: удаление идёт с конца списка, и история очищается после
+// текущих строк.
+var personalDataTables = []personalDataTable{
+	{section: "subscriptions_history", table: "subscriptions_history", userColumn: "user_id"},
+	{section: "subscription_prices_history", table: "subscription_prices_history", userColumn: "user_id"},
+	{section: "subscription_seats_history", table: "subscription_seats_history", userColumn: "user_id"},
+	{section: "subscription_discounts_history", table: "subscription_discounts_history", userColumn: "user_id"},
+	{section: "subscription_pauses_history", table: "subscription_pauses_history", userColumn: "user_id"},
+	{section: "subscription_members_history", table: "subscription_members_history", userColumn: "user_id"},
+	{section: "subscriptions", table: "subscriptions", userColumn: "user_id"},
+	{section: "tags", table: "tags", userColumn: "user_id"},
+	{section: "subscription_tags", table: "subscription_tags", userColumn: "user_id"},
+	{section: "subscription_pauses", table: "subscription_pauses", userColumn: "user_id"},
+	{section: "subscription_status_changes", table: "subscription_status_changes", userColumn: "user_id"},
+	{section: "subscription_prices", table: "subscription_prices", userColumn: "user_id"},
+	{section: "subscription_seats", table: "subscription_seats", userColumn: "user_id"},
+	{section: "subscription_discounts", table: "subscription_discounts", userColumn: "user_id"},
+	{section: "budgets", table: "budgets", userColumn: "user_id"},
+	{section: "subscription_members", table: "subscription_members", userColumn: "user_id"},
+	{section: "notifications", table: "notifications", userColumn: "user_id"},
+	{section: "organisation_users", table: "organisation_users", userColumn: "user_id"},
+	{section: "access_tokens", table: "access_tokens", userColumn: "user_id"},
+	{section: "monthly_spend", table: "monthly_spend", userColumn: "user_id"},
+}
+
+// UserDataSink принимает разделы выгрузки персональных данных.
+type UserDataSink interface {
+	BeginSection(name string) error
+	WriteRow(row []byte) error
+	EndSection() error
+}
+
+type UserDataRepository struct {
+	db  *DB
+	sqb sq.StatementBuilderType
+	// receiptSecret — ключ, которым подписывается user_id в квитанциях.
+	receiptSecret []byte
+}
+
+func NewUserDataRepository(db *DB, receiptSecret []byte) *UserDataRepository {
+	return &UserDataRepository{
+		db:            db,
+		sqb:           sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+		receiptSecret: receiptSecret,
+	}
+}
+
+// HasUserData сообщает, есть ли у пользователя хоть какие-то данные.
+func (r *UserDataRepository) HasUserData(ctx context.Context, userID uuid.UUID) (bool, error) {
+	for _, t := range personalDataTables {
+		sql, args, err := r.sqb.Select("1").
+			From(t.table).
+			Where(sq.Eq{t.userColumn: userID}).
+			Prefix("SELECT EXISTS (").
+			Suffix(")").
+			ToSql()
+		if err != nil {
+			return false, fmt.Errorf("UserDataRepository.HasUserData - ToSql: %w", err)
+		}
+
+		var exists bool
+		if err := r.db.QueryRow(ctx, sql, args...).Scan(&exists); err != nil {
+			return false, fmt.Errorf("UserDataRepository.HasUserData - Scan: %w", err)
+		}
+		if exists {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// ExportUserData передаёт в sink все строки пользователя, раздел за
+// разделом. Каждая строка отдаётся как JSON, собранный самим Postgres
+// (to_jsonb), поэтому новые колонки попадают в выгрузку автоматически.
+// Все разделы читаются из одного снимка данных.
+func (r *UserDataRepository) ExportUserData(ctx context.Context, userID uuid.UUID, sink UserDataSink) error {
+	tx, err := r.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return fmt.Errorf("UserDataRepository.ExportUserData - Begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, t := range personalDataTables {
+		if err := r.exportSection(ctx, tx, t, userID, sink); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *UserDataRepository) exportSection(ctx context.Context, tx pgx.Tx, t personalDataTable, userID uuid.UUID, sink UserDataSink) error {
+	sql, args, err := r.sqb.Select("to_jsonb(t)").
+		From(t.table + " AS t").
+		Where(sq.Eq{"t." + t.userColumn: userID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("UserDataRepository.ExportUserData - ToSql: %w", err)
+	}
+
+	if err := sink.BeginSection(t.section); err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("UserDataRepository.ExportUserData - Query %s: %w", t.table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row []byte
+		if err := rows.Scan(&row); err != nil {
+			return fmt.Errorf("UserDataRepository.ExportUserData - Scan %s: %w", t.table, err)
+		}
+		if err := sink.WriteRow(row); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("UserDataRepository.ExportUserData - Rows %s: %w", t.table, err)
+	}
+
+	return sink.EndSection()
+}
+
+// EraseUserData удаляет все строки пользователя в одной
+// транзакции и сохраняет квитанцию об удалении.
+func (r *UserDataRepository) EraseUserData(ctx context.Context, userID uuid.UUID) (*models.ErasureReceipt, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("UserDataRepository.EraseUserData - Begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Удаляемые строки не должны сохраниться прошлыми версиями в истории.
+	if _, err := tx.Exec(ctx, "SELECT set_config('app.erasing', 'on', true)"); err != nil {
+		return nil, fmt.Errorf("UserDataRepository.EraseUserData - Exec: %w", err)
+	}
+
+	receipt := &models.ErasureReceipt{
+		ID:          uuid.New(),
+		SubjectHash: SubjectHash(r.receiptSecret, userID),
+		ErasedAt:    time.Now().UTC(),
+		Affected:    make(map[string]int64, len(personalDataTables)),
+	}
+
+	// Таблицы обрабатываются в обратном порядке: зависимые таблицы
+	// добавляются в список после тех, на которые ссылаются.
+	for i := len(personalDataTables) - 1; i >= 0; i-- {
+		t := personalDataTables[i]
+
+		sql, args, err := r.sqb.Delete(t.table).
+			Where(sq.Eq{t.userColumn: userID}).
+			ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("UserDataRepository.EraseUserData - ToSql: %w", err)
+		}
+
+		res, err := tx.Exec(ctx, sql, args...)
+		if err != nil {
+			return nil, fmt.Errorf("UserDataRepository.EraseUserData - Exec %s: %w", t.table, err)
+		}
+		receipt.Affected[t.table] += res.RowsAffected()
+	}
+
+	sql, args, err := r.sqb.Insert("erasure_receipts").
+		Columns("id", "subject_hash", "erased_at", "affected").
+		Values(receipt.ID, receipt.SubjectHash, receipt.ErasedAt, receipt.Affected).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("UserDataRepository.EraseUserData - ToSql: %w", err)
+	}
+	if _, err := tx.Exec(ctx, sql, args...); err != nil {
+		return nil, fmt.Errorf("UserDataRepository.EraseUserData - Insert receipt: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("UserDataRepository.EraseUserData - Commit: %w", err)
+	}
+
+	return receipt, nil
+}
+
+// SubjectHash возвращает HMAC-SHA256 идентификатора пользователя для
+// квитанций. Без ключа по хэшу нельзя проверить, чья это квитанция:
+// простой хэш UUID легко сопоставить со списком известных пользователей.
+func SubjectHash(secret []byte, userID uuid.UUID) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(userID[:])
+	return hex.EncodeToString(mac.Sum(nil))
+}
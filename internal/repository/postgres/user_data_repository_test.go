@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"effective-mobile-task/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersonalDataTables_HistoryErasedAfterLiveTables(t *testing.T) {
+	// EraseUserData идёт по списку с конца, поэтому таблица истории должна
+	// стоять раньше своей таблицы.
+	position := make(map[string]int, len(personalDataTables))
+	for i, table := range personalDataTables {
+		position[table.table] = i
+	}
+
+	for _, table := range personalDataTables {
+		live, ok := strings.CutSuffix(table.table, "_history")
+		if !ok {
+			continue
+		}
+		require.Contains(t, position, live)
+		assert.Less(t, position[table.table], position[live], table.table)
+	}
+}
+
+func TestUserDataRepository_EraseUserDataLeavesNoHistory(t *testing.T) {
+	db := testDB(t)
+	ctx := testTenant(t, db)
+	subscriptions := NewSubscriptionRepository(db)
+	userID := uuid.New()
+
+	sub := &models.Subscription{
+		ID:          uuid.New(),
+		UserID:      userID,
+		ServiceName: "Yandex Plus",
+		Price:       400,
+		StartDate:   models.MonthOf(time.Now()),
+	}
+	require.NoError(t, subscriptions.Create(ctx, sub))
+
+	// Изменение в отдельной транзакции сохраняет прежнюю версию в истории.
+	sub.Price = 500
+	require.NoError(t, subscriptions.Update(ctx, sub))
+
+	var versions int
+	require.NoError(t, db.QueryRow(ctx, "SELECT count(*) FROM subscriptions_history WHERE user_id = $1", userID).Scan(&versions))
+	require.Positive(t, versions)
+
+	_, err := NewUserDataRepository(db, []byte("secret")).EraseUserData(ctx, userID)
+	require.NoError(t, err)
+
+	for _, table := range personalDataTables {
+		if !strings.HasSuffix(table.table, "_history") {
+			continue
+		}
+		var left int
+		require.NoError(t, db.QueryRow(ctx, "SELECT count(*) FROM "+table.table+" WHERE user_id = $1", userID).Scan(&left))
+		assert.Zero(t, left, table.table)
+	}
+}
+
+func TestSubjectHash_KeyedBySecret(t *testing.T) {
+	userID := uuid.New()
+	plain := sha256.Sum256(userID[:])
+
+	hash := SubjectHash([]byte("secret"), userID)
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, SubjectHash([]byte("secret"), userID))
+	assert.NotEqual(t, hex.EncodeToString(plain[:]), hash)
+	assert.NotEqual(t, hash, SubjectHash([]byte("other"), userID))
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"effective-mobile-task/internal/models"
+	"effective-mobile-task/internal/repository/postgres"
+	"github.com/google/uuid"
+)
+
+// BatchMode определяет, как пакет реагирует на ошибку в одной из операций.
+type BatchMode string
+
+const (
+	// BatchModeAtomic — всё или ничего: при любой ошибке пакет откатывается.
+	BatchModeAtomic BatchMode = "atomic"
+	// BatchModeBestEffort — успешные операции сохраняются, ошибочные пропускаются.
+	BatchModeBestEffort BatchMode = "best_effort"
+)
+
+const maxBatchSize = 1000
+
+type BatchOperationDTO struct {
+	Op     postgres.BatchOperationType `json:"op" validate:"required,oneof=create update delete" swaggertype:"string" enums:"create,update,delete"`
+	ID     *uuid.UUID                  `json:"id,omitempty" validate:"required_unless=Op create"`
+	Create *CreateSubscriptionDTO      `json:"create,omitempty" validate:"required_if=Op create,omitempty"`
+	Update *UpdateSubscriptionDTO      `json:"update,omitempty" validate:"required_if=Op update,omitempty"`
+}
+
+type BatchRequestDTO struct {
+	Mode       BatchMode           `json:"mode,omitempty" validate:"omitempty,oneof=atomic best_effort" swaggertype:"string" enums:"atomic,best_effort"`
+	Operations []BatchOperationDTO `json:"operations" validate:"required,min=1,max=1000,dive"`
+}
+
+type BatchItemStatus string
+
+const (
+	BatchItemSucceeded BatchItemStatus = "succeeded"
+	BatchItemFailed    BatchItemStatus = "failed"
+	// BatchItemSkipped — операция корректна, но не применена, потому что
+	// атомарный пакет был отменён из-за другой операции.
+	BatchItemSkipped BatchItemStatus = "skipped"
+)
+
+type BatchItemResult struct {
+	Index  int                         `json:"index"`
+	Op     postgres.BatchOperationType `json:"op" swaggertype:"string"`
+	ID     *uuid.UUID                  `json:"id,omitempty"`
+	Status BatchItemStatus             `json:"status" swaggertype:"string" enums:"succeeded,failed,skipped"`
+	Error  string                      `json:"error,omitempty"`
+
+	// Err — исходная ошибка операции; текст для клиента заполняет обработчик.
+	Err error `json:"-"`
+}
+
+type BatchResult struct {
+	Mode      BatchMode         `json:"mode" swaggertype:"string"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Items     []BatchItemResult `json:"items"`
+}
+
+// Batch выполняет пакет операций создания, обновления и удаления в одной
+// транзакции. Бизнес-правила проверяются для каждой операции до обращения к БД.
+func (s *SubscriptionService) Batch(ctx context.Context, dto BatchRequestDTO) (*BatchResult, error) {
+	if len(dto.Operations) > maxBatchSize {
+		return nil, fmt.Errorf("%w: не более %d операций в пакете", ErrInvalidSubscription, maxBatchSize)
+	}
+
+	mode := dto.Mode
+	if mode == "" {
+		mode = BatchModeAtomic
+	}
+	atomic := mode == BatchModeAtomic
+
+	result := &BatchResult{
+		Mode:  mode,
+		Items: make([]BatchItemResult, len(dto.Operations)),
+	}
+
+	ops := make([]postgres.BatchOperation, 0, len(dto.Operations))
+	positions := make([]int, 0, len(dto.Operations))
+
+	for i, opDTO := range dto.Operations {
+		item := &result.Items[i]
+		item.Index = i
+		item.Op = opDTO.Op
+
+		op, err := s.prepareBatchOperation(ctx, opDTO)
+		if err != nil && !isDomainError(err) {
+			return nil, err
+		}
+		if op != nil {
+			item.ID = &op.ID
+		}
+		if err != nil {
+			item.Err = err
+			continue
+		}
+
+		ops = append(ops, *op)
+		positions = append(positions, i)
+	}
+
+	var subs []*models.Subscription
+	for j, op := range ops {
+		if op.Subscription != nil && result.Items[positions[j]].Err == nil {
+			subs = append(subs, op.Subscription)
+		}
+	}
+
+	// Пересечения с сохранёнными подписками проверяются под блокировкой в
+	// той же транзакции, что и запись пакета.
+	err := s.lockOverlaps(ctx, subs, func(ctx context.Context) error {
+		if !s.allowOverlap {
+			if err := s.checkStoredOverlaps(ctx, ops, positions, result.Items); err != nil {
+				return err
+			}
+			checkBatchOverlaps(ops, positions, result.Items)
+		}
+
+		if atomic && hasFailedItems(result.Items) {
+			for i := range result.Items {
+				if result.Items[i].Err == nil {
+					result.Items[i].Err = postgres.ErrBatchRolledBack
+				}
+			}
+			return nil
+		}
+
+		// В режиме best effort операции, не прошедшие проверку пересечений,
+		// в БД не отправляются.
+		validOps := ops[:0:0]
+		validPositions := positions[:0:0]
+		for j, pos := range positions {
+			if result.Items[pos].Err == nil {
+				validOps = append(validOps, ops[j])
+				validPositions = append(validPositions, pos)
+			}
+		}
+
+		if len(validOps) > 0 {
+			errs, err := s.repo.ApplyBatch(ctx, validOps, atomic)
+			if err != nil {
+				return fmt.Errorf("не удалось выполнить пакет операций: %w", err)
+			}
+			for j, pos := range validPositions {
+				result.Items[pos].Err = errs[j]
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.finalize(), nil
+}
+
+// checkStoredOverlaps ищет пересечения подписок пакета с уже сохранёнными.
+// Сохранённые версии подписок, которые пакет удаляет или обновляет, не
+// учитываются: удалённых не будет, а новые периоды обновлённых сверяет
+// checkBatchOverlaps. Если обновление само не прошло проверку, его прежняя
+// версия остаётся в базе, и проверка повторяется уже с ней.
+func (s *SubscriptionService) checkStoredOverlaps(ctx context.Context, ops []postgres.BatchOperation, positions []int, items []BatchItemResult) error {
+	for {
+		var replaced []uuid.UUID
+		for j, op := range ops {
+			if op.Type != postgres.BatchCreate && items[positions[j]].Err == nil {
+				replaced = append(replaced, op.ID)
+			}
+		}
+
+		updateFailed := false
+		for j, op := range ops {
+			if op.Subscription == nil || items[positions[j]].Err != nil {
+				continue
+			}
+			if err := s.checkOverlap(ctx, op.Subscription, replaced...); err != nil {
+				if !isDomainError(err) {
+					return err
+				}
+				items[positions[j]].Err = err
+				updateFailed = updateFailed || op.Type == postgres.BatchUpdate
+			}
+		}
+		if !updateFailed {
+			return nil
+		}
+	}
+}
+
+func (s *SubscriptionService) prepareBatchOperation(ctx context.Context, dto BatchOperationDTO) (*postgres.BatchOperation, error) {
+	switch dto.Op {
+	case postgres.BatchCreate:
+		if dto.Create == nil {
+			return nil, &ValidationError{Field: "create", Rule: RuleRequired}
+		}
+		if err := s.authorize(ctx, ActionCreate, &dto.Create.UserID); err != nil {
+			return nil, err
+		}
+		sub, err := s.newSubscription(ctx, *dto.Create)
+		if err != nil {
+			return nil, err
+		}
+		return &postgres.BatchOperation{Type: postgres.BatchCreate, ID: sub.ID, Subscription: sub}, nil
+
+	case postgres.BatchUpdate:
+		if dto.ID == nil || dto.Update == nil {
+			return nil, &ValidationError{Field: "update", Rule: RuleRequired}
+		}
+		op := &postgres.BatchOperation{Type: postgres.BatchUpdate, ID: *dto.ID}
+		sub, err := s.applyUpdate(ctx, *dto.ID, *dto.Update)
+		if err != nil {
+			return op, err
+		}
+		if err := s.authorize(ctx, ActionUpdate, &sub.UserID); err != nil {
+			return op, err
+		}
+		op.Subscription = sub
+		return op, nil
+
+	case postgres.BatchDelete:
+		if dto.ID == nil {
+			return nil, &ValidationError{Field: "id", Rule: RuleRequired}
+		}
+		if err := s.authorize(ctx, ActionDelete, nil); err != nil {
+			return nil, err
+		}
+		return &postgres.BatchOperation{Type: postgres.BatchDelete, ID: *dto.ID}, nil
+	}
+
+	return nil, &ValidationError{Field: "op", Rule: RuleRequired}
+}
+
+// checkBatchOverlaps ищет пересечения между подписками внутри самого пакета:
+// проверка в БД их не видит, потому что они ещё не сохранены.
+func checkBatchOverlaps(ops []postgres.BatchOperation, positions []int, items []BatchItemResult) {
+	periods := make(periodIndex)
+
+	for j, op := range ops {
+		if op.Subscription == nil || items[positions[j]].Err != nil {
+			continue
+		}
+		if existingID, ok := periods.overlapping(op.Subscription); ok {
+			items[positions[j]].Err = &OverlapError{ExistingID: existingID}
+			continue
+		}
+		periods.add(op.Subscription)
+	}
+}
+
+func hasFailedItems(items []BatchItemResult) bool {
+	for _, item := range items {
+		if item.Err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *BatchResult) finalize() *BatchResult {
+	r.Succeeded, r.Failed = 0, 0
+	for i := range r.Items {
+		item := &r.Items[i]
+		switch {
+		case item.Err == nil:
+			item.Status = BatchItemSucceeded
+			r.Succeeded++
+		case errors.Is(item.Err, postgres.ErrBatchRolledBack):
+			item.Status = BatchItemSkipped
+		default:
+			item.Status = BatchItemFailed
+			r.Failed++
+		}
+	}
+	return r
+}
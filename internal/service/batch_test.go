@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"effective-mobile-task/internal/models"
+	"effective-mobile-task/internal/repository/postgres"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newCreateOp(userID uuid.UUID, name string, start models.Month) BatchOperationDTO {
+	return BatchOperationDTO{
+		Op: postgres.BatchCreate,
+		Create: &CreateSubscriptionDTO{
+			UserID:      userID,
+			ServiceName: name,
+			Price:       100,
+			StartDate:   start,
+		},
+	}
+}
+
+func TestSubscriptionService_Batch_AtomicSkipsOnValidationError(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+
+	end := models.NewMonth(2025, time.January)
+	invalid := newCreateOp(uuid.New(), "Broken", models.NewMonth(2025, time.July))
+	invalid.Create.EndDate = &end
+
+	mockRepo.On("FindOverlapping", mock.Anything, mock.Anything).Return(nil, postgres.ErrNotFound)
+
+	result, err := service.Batch(context.Background(), BatchRequestDTO{
+		Operations: []BatchOperationDTO{
+			newCreateOp(uuid.New(), "Netflix", models.NewMonth(2025, time.July)),
+			invalid,
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, BatchModeAtomic, result.Mode)
+	assert.Equal(t, 0, result.Succeeded)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, BatchItemSkipped, result.Items[0].Status)
+	assert.Equal(t, BatchItemFailed, result.Items[1].Status)
+	assert.ErrorIs(t, result.Items[1].Err, ErrInvalidSubscription)
+	mockRepo.AssertNotCalled(t, "ApplyBatch", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSubscriptionService_Batch_BestEffortDetectsOverlapInsideBatch(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+
+	userID := uuid.New()
+	deleteID := uuid.New()
+
+	mockRepo.On("FindOverlapping", mock.Anything, mock.Anything).Return(nil, postgres.ErrNotFound)
+	mockRepo.On("ApplyBatch", mock.Anything, mock.MatchedBy(func(ops []postgres.BatchOperation) bool {
+		return len(ops) == 2 && ops[0].Type == postgres.BatchCreate && ops[1].Type == postgres.BatchDelete
+	}), false).Return([]error{nil, postgres.ErrNotFound}, nil)
+
+	result, err := service.Batch(context.Background(), BatchRequestDTO{
+		Mode: BatchModeBestEffort,
+		Operations: []BatchOperationDTO{
+			newCreateOp(userID, "Netflix", models.NewMonth(2025, time.July)),
+			newCreateOp(userID, "netflix", models.NewMonth(2025, time.August)),
+			{Op: postgres.BatchDelete, ID: &deleteID},
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Succeeded)
+	assert.Equal(t, 2, result.Failed)
+	assert.Equal(t, BatchItemSucceeded, result.Items[0].Status)
+	assert.ErrorIs(t, result.Items[1].Err, ErrOverlappingSubscription)
+	assert.ErrorIs(t, result.Items[2].Err, postgres.ErrNotFound)
+	mockRepo.AssertExpectations(t)
+}
+
+// mockStoredSubscription настраивает FindOverlapping так, будто в базе
+// лежит stored и пересекается с любой проверяемой подпиской, пока её не
+// исключат из проверки.
+func mockStoredSubscription(m *MockRepository, stored *models.Subscription) {
+	excluded := func(q postgres.OverlapQuery) bool {
+		for _, id := range q.ExcludeIDs {
+			if id == stored.ID {
+				return true
+			}
+		}
+		return false
+	}
+	m.On("FindOverlapping", mock.Anything, mock.MatchedBy(excluded)).Return(nil, postgres.ErrNotFound)
+	m.On("FindOverlapping", mock.Anything, mock.MatchedBy(func(q postgres.OverlapQuery) bool {
+		return !excluded(q)
+	})).Return(stored, nil).Maybe()
+}
+
+func TestSubscriptionService_Batch_DeleteAndCreateReplacement(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+
+	userID := uuid.New()
+	stored := &models.Subscription{
+		ID:          uuid.New(),
+		UserID:      userID,
+		ServiceName: "Netflix",
+		Price:       100,
+		StartDate:   models.NewMonth(2025, time.July),
+	}
+
+	mockStoredSubscription(mockRepo, stored)
+	mockRepo.On("ApplyBatch", mock.Anything, mock.Anything, true).Return([]error{nil, nil}, nil)
+
+	result, err := service.Batch(context.Background(), BatchRequestDTO{
+		Operations: []BatchOperationDTO{
+			{Op: postgres.BatchDelete, ID: &stored.ID},
+			newCreateOp(userID, "Netflix", models.NewMonth(2025, time.July)),
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Succeeded)
+	assert.Equal(t, 0, result.Failed)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSubscriptionService_Batch_ShortenAndCreateSuccessor(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+
+	userID := uuid.New()
+	stored := &models.Subscription{
+		ID:          uuid.New(),
+		UserID:      userID,
+		ServiceName: "Netflix",
+		Price:       100,
+		UnitPrice:   100,
+		Quantity:    1,
+		StartDate:   models.NewMonth(2025, time.January),
+	}
+	newEnd := models.NewMonth(2025, time.June)
+
+	mockRepo.On("GetByID", mock.Anything, stored.ID).Return(stored, nil)
+	mockStoredSubscription(mockRepo, stored)
+	mockRepo.On("ApplyBatch", mock.Anything, mock.Anything, true).Return([]error{nil, nil}, nil)
+
+	result, err := service.Batch(context.Background(), BatchRequestDTO{
+		Operations: []BatchOperationDTO{
+			{Op: postgres.BatchUpdate, ID: &stored.ID, Update: &UpdateSubscriptionDTO{
+				ServiceName: "Netflix",
+				Price:       100,
+				StartDate:   stored.StartDate,
+				EndDate:     &newEnd,
+			}},
+			newCreateOp(userID, "Netflix", models.NewMonth(2025, time.July)),
+		},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Succeeded)
+	assert.Equal(t, 0, result.Failed)
+	mockRepo.AssertExpectations(t)
+}
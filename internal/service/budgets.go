@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"effective-mobile-task/internal/models"
+	"effective-mobile-task/internal/repository/postgres"
+	"github.com/google/uuid"
+)
+
+// defaultBudgetThresholds — пороги уведомлений, если пользователь их не указал.
+var defaultBudgetThresholds = []int{80, 100}
+
+type BudgetRepository interface {
+	Create(ctx context.Context, b *models.Budget) error
+	List(ctx context.Context, userID *uuid.UUID) ([]models.Budget, error)
+	Update(ctx context.Context, b *models.Budget) error
+	Delete(ctx context.Context, userID, id uuid.UUID) error
+}
+
+// SummaryRepository считает стоимость подписок так же, как сводка.
+type SummaryRepository interface {
+	GetSummary(ctx context.Context, filter postgres.GetSummaryFilter) (*models.SummaryTotals, error)
+}
+
+// BudgetService ведёт месячные бюджеты пользователей и считает расходы в
+// их рамках.
+type BudgetService struct {
+	repo      BudgetRepository
+	summaries SummaryRepository
+	policy    Authorizer
+	now       func() time.Time
+}
+
+// NewBudgetService создаёт сервис бюджетов. Бюджеты пользователя меняются
+// с теми же правами, что и его подписки.
+func NewBudgetService(repo BudgetRepository, summaries SummaryRepository, policy Authorizer) *BudgetService {
+	return &BudgetService{repo: repo, summaries: summaries, policy: policy, now: time.Now}
+}
+
+// BudgetDTO — бюджет пользователя. Category обязательна для бюджета на
+// категорию, ServiceName — для бюджета на сервис.
+type BudgetDTO struct {
+	Scope       models.BudgetScope `json:"scope" validate:"required,oneof=overall category service" enums:"overall,category,service"`
+	Category    *string            `json:"category,omitempty" validate:"omitempty,min=2,max=100"`
+	ServiceName *string            `json:"service_name,omitempty" validate:"omitempty,min=2,max=100"`
+	Amount      int                `json:"amount" validate:"required,gt=0"`
+	Thresholds  []int              `json:"thresholds,omitempty" validate:"max=10,dive,gt=0,lte=1000" example:"80,100"`
+}
+
+func (s *BudgetService) Create(ctx context.Context, userID uuid.UUID, dto BudgetDTO) (*models.Budget, error) {
+	if err := authorize(ctx, s.policy, ActionCreate, &userID); err != nil {
+		return nil, err
+	}
+
+	b, err := newBudget(uuid.New(), userID, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, b); err != nil {
+		return nil, fmt.Errorf("не удалось создать бюджет: %w", err)
+	}
+
+	return b, nil
+}
+
+func (s *BudgetService) List(ctx context.Context, userID uuid.UUID) ([]models.Budget, error) {
+	return s.repo.List(ctx, &userID)
+}
+
+func (s *BudgetService) Update(ctx context.Context, userID, id uuid.UUID, dto BudgetDTO) (*models.Budget, error) {
+	if err := authorize(ctx, s.policy, ActionUpdate, &userID); err != nil {
+		return nil, err
+	}
+
+	b, err := newBudget(id, userID, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, b); err != nil {
+		return nil, fmt.Errorf("не удалось обновить бюджет: %w", err)
+	}
+
+	return b, nil
+}
+
+func (s *BudgetService) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	if err := authorize(ctx, s.policy, ActionUpdate, &userID); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, userID, id)
+}
+
+// Status возвращает расходы пользователя в рамках каждого его бюджета за
+// текущий месяц.
+func (s *BudgetService) Status(ctx context.Context, userID uuid.UUID) ([]models.BudgetStatus, error) {
+	budgets, err := s.repo.List(ctx, &userID)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить бюджеты: %w", err)
+	}
+
+	month := models.MonthOf(s.now())
+	statuses := make([]models.BudgetStatus, 0, len(budgets))
+	for _, b := range budgets {
+		status, err := budgetStatus(ctx, s.summaries, b, month)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, *status)
+	}
+
+	return statuses, nil
+}
+
+// newBudget проверяет, что область бюджета задана полностью, и приводит
+// пороги к возрастающему списку без повторов.
+func newBudget(id, userID uuid.UUID, dto BudgetDTO) (*models.Budget, error) {
+	b := &models.Budget{ID: id, UserID: userID, Scope: dto.Scope, Amount: dto.Amount}
+
+	switch dto.Scope {
+	case models.BudgetOverall:
+		if dto.Category != nil || dto.ServiceName != nil {
+			return nil, &ValidationError{Field: "scope", Rule: RuleBudgetScope}
+		}
+	case models.BudgetCategory:
+		if dto.Category == nil || dto.ServiceName != nil {
+			return nil, &ValidationError{Field: "category", Rule: RuleBudgetScope}
+		}
+		category := strings.Join(strings.Fields(*dto.Category), " ")
+		b.Category = &category
+	case models.BudgetService:
+		if dto.ServiceName == nil || dto.Category != nil {
+			return nil, &ValidationError{Field: "service_name", Rule: RuleBudgetScope}
+		}
+		name, err := canonicalServiceName(*dto.ServiceName)
+		if err != nil {
+			return nil, err
+		}
+		b.ServiceName = &name
+	default:
+		return nil, &ValidationError{Field: "scope", Rule: RuleInvalidFormat}
+	}
+
+	if dto.Amount <= 0 {
+		return nil, &ValidationError{Field: "amount", Rule: RulePricePositive}
+	}
+
+	b.Thresholds = slices.Clone(dto.Thresholds)
+	if len(b.Thresholds) == 0 {
+		b.Thresholds = slices.Clone(defaultBudgetThresholds)
+	}
+	slices.Sort(b.Thresholds)
+	b.Thresholds = slices.Compact(b.Thresholds)
+	if b.Thresholds[0] <= 0 {
+		return nil, &ValidationError{Field: "thresholds", Rule: RuleBudgetThreshold}
+	}
+
+	return b, nil
+}
+
+// budgetFilter отбирает подписки, расходы на которые ограничивает бюджет, в
+// месяце month.
+func budgetFilter(b models.Budget, month models.Month) postgres.GetSummaryFilter {
+	return postgres.GetSummaryFilter{
+		UserID:      &b.UserID,
+		ServiceName: b.ServiceName,
+		Category:    b.Category,
+		StartDate:   &month,
+		EndDate:     &month,
+	}
+}
+
+// budgetStatus считает расходы в рамках бюджета в месяце month и прогноз на
+// следующий месяц. Расходы считаются после скидок, как в сводке.
+func budgetStatus(ctx context.Context, summaries SummaryRepository, b models.Budget, month models.Month) (*models.BudgetStatus, error) {
+	spent, err := summaries.GetSummary(ctx, budgetFilter(b, month))
+	if err != nil {
+		return nil, fmt.Errorf("не удалось посчитать расходы по бюджету: %w", err)
+	}
+	projected, err := summaries.GetSummary(ctx, budgetFilter(b, month.AddMonths(1)))
+	if err != nil {
+		return nil, fmt.Errorf("не удалось посчитать прогноз расходов по бюджету: %w", err)
+	}
+
+	status := &models.BudgetStatus{
+		Budget:            b,
+		Month:             month,
+		Spent:             spent.Net,
+		Remaining:         max(b.Amount-spent.Net, 0),
+		SpentPercent:      spent.Net * 100 / b.Amount,
+		Projected:         projected.Net,
+		ProjectedPercent:  projected.Net * 100 / b.Amount,
+		ReachedThresholds: []int{},
+	}
+	for _, threshold := range b.Thresholds {
+		if status.Spent*100 >= b.Amount*threshold {
+			status.ReachedThresholds = append(status.ReachedThresholds, threshold)
+		}
+	}
+
+	return status, nil
+}
+
+// BudgetAlerter уведомляет пользователей, когда расходы месяца достигают
+// порогов их бюджетов.
+type BudgetAlerter struct {
+	budgets       BudgetRepository
+	summaries     SummaryRepository
+	notifications NotificationRepository
+	notifier      Notifier
+	tenants       TenantScope
+	now           func() time.Time
+}
+
+func NewBudgetAlerter(budgets BudgetRepository, summaries SummaryRepository, notifications NotificationRepository, notifier Notifier) *BudgetAlerter {
+	return &BudgetAlerter{
+		budgets:       budgets,
+		summaries:     summaries,
+		notifications: notifications,
+		notifier:      notifier,
+		now:           time.Now,
+	}
+}
+
+// SetTenants включает проверку бюджетов каждого арендатора по очереди.
+func (a *BudgetAlerter) SetTenants(scope TenantScope) {
+	a.tenants = scope
+}
+
+// Run проверяет бюджеты раз в interval, пока не отменён ctx. Ошибка одной
+// проверки не останавливает следующие: о ней сообщается через onError.
+func (a *BudgetAlerter) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	check := func(ctx context.Context) error {
+		_, err := a.CheckOnce(ctx)
+		return err
+	}
+
+	for {
+		if err := inTenants(ctx, a.tenants, check); err != nil && ctx.Err() == nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckOnce отправляет уведомления о достигнутых порогах бюджетов и
+// возвращает число отправленных уведомлений. О каждом пороге пользователь
+// уведомляется не чаще раза в месяц.
+func (a *BudgetAlerter) CheckOnce(ctx context.Context) (int, error) {
+	budgets, err := a.budgets.List(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("не удалось получить бюджеты: %w", err)
+	}
+
+	now := a.now()
+	month := models.MonthOf(now)
+
+	sent := 0
+	for _, b := range budgets {
+		status, err := budgetStatus(ctx, a.summaries, b, month)
+		if err != nil {
+			return sent, err
+		}
+
+		for _, threshold := range status.ReachedThresholds {
+			n := models.Notification{
+				ID:        uuid.New(),
+				UserID:    b.UserID,
+				BudgetID:  &status.ID,
+				Threshold: &threshold,
+				Kind:      models.NotificationBudgetThreshold,
+				DueAt:     month.Time(),
+				CreatedAt: now,
+				Budget:    status,
+			}
+
+			recorded, err := a.notifications.Record(ctx, &n)
+			if err != nil {
+				return sent, fmt.Errorf("не удалось сохранить уведомление: %w", err)
+			}
+			if !recorded {
+				continue
+			}
+
+			if err := a.notifier.Notify(ctx, n); err != nil {
+				// Запись убирается, чтобы уведомление ушло при следующей проверке.
+				if forgetErr := a.notifications.Forget(ctx, &n); forgetErr != nil {
+					return sent, fmt.Errorf("не удалось отменить запись неотправленного уведомления: %w", forgetErr)
+				}
+				return sent, fmt.Errorf("не удалось отправить уведомление: %w", err)
+			}
+			sent++
+		}
+	}
+
+	return sent, nil
+}
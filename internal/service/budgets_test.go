@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"effective-mobile-task/internal/models"
+	"effective-mobile-task/internal/repository/postgres"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockBudgetRepository struct {
+	mock.Mock
+}
+
+func (m *MockBudgetRepository) Create(ctx context.Context, b *models.Budget) error {
+	args := m.Called(ctx, b)
+	return args.Error(0)
+}
+
+func (m *MockBudgetRepository) List(ctx context.Context, userID *uuid.UUID) ([]models.Budget, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Budget), args.Error(1)
+}
+
+func (m *MockBudgetRepository) Update(ctx context.Context, b *models.Budget) error {
+	args := m.Called(ctx, b)
+	return args.Error(0)
+}
+
+func (m *MockBudgetRepository) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	args := m.Called(ctx, userID, id)
+	return args.Error(0)
+}
+
+var budgetNow = time.Date(2025, time.July, 20, 12, 0, 0, 0, time.UTC)
+
+func TestBudgetService_Create(t *testing.T) {
+	mockBudgets := new(MockBudgetRepository)
+	service := NewBudgetService(mockBudgets, new(MockRepository), nil)
+
+	userID := uuid.New()
+	category := "  Музыка   и видео "
+	mockBudgets.On("Create", mock.Anything, mock.AnythingOfType("*models.Budget")).Return(nil)
+
+	got, err := service.Create(context.Background(), userID, BudgetDTO{
+		Scope:      models.BudgetCategory,
+		Category:   &category,
+		Amount:     1000,
+		Thresholds: []int{100, 50, 100},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, userID, got.UserID)
+	require.NotNil(t, got.Category)
+	assert.Equal(t, "Музыка и видео", *got.Category)
+	assert.Equal(t, []int{50, 100}, got.Thresholds)
+	mockBudgets.AssertExpectations(t)
+}
+
+func TestBudgetService_Create_DefaultThresholds(t *testing.T) {
+	mockBudgets := new(MockBudgetRepository)
+	service := NewBudgetService(mockBudgets, new(MockRepository), nil)
+
+	mockBudgets.On("Create", mock.Anything, mock.AnythingOfType("*models.Budget")).Return(nil)
+
+	got, err := service.Create(context.Background(), uuid.New(), BudgetDTO{Scope: models.BudgetOverall, Amount: 1000})
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{80, 100}, got.Thresholds)
+}
+
+func TestBudgetService_Create_InvalidScope(t *testing.T) {
+	name := "Netflix"
+	category := "Видео"
+	tests := []struct {
+		name string
+		dto  BudgetDTO
+	}{
+		{name: "overall with service", dto: BudgetDTO{Scope: models.BudgetOverall, ServiceName: &name, Amount: 100}},
+		{name: "category without category", dto: BudgetDTO{Scope: models.BudgetCategory, Amount: 100}},
+		{name: "service with category", dto: BudgetDTO{Scope: models.BudgetService, ServiceName: &name, Category: &category, Amount: 100}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockBudgets := new(MockBudgetRepository)
+			service := NewBudgetService(mockBudgets, new(MockRepository), nil)
+
+			_, err := service.Create(context.Background(), uuid.New(), tt.dto)
+
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, RuleBudgetScope, validationErr.Rule)
+			mockBudgets.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestBudgetService_Status(t *testing.T) {
+	mockBudgets := new(MockBudgetRepository)
+	mockSummaries := new(MockRepository)
+	service := NewBudgetService(mockBudgets, mockSummaries, nil)
+	service.now = func() time.Time { return budgetNow }
+
+	userID := uuid.New()
+	name := "Netflix"
+	budget := models.Budget{ID: uuid.New(), UserID: userID, Scope: models.BudgetService, ServiceName: &name, Amount: 1000, Thresholds: []int{80, 100}}
+	july := models.NewMonth(2025, time.July)
+	august := models.NewMonth(2025, time.August)
+
+	mockBudgets.On("List", mock.Anything, &userID).Return([]models.Budget{budget}, nil)
+	mockSummaries.On("GetSummary", mock.Anything, postgres.GetSummaryFilter{UserID: &userID, ServiceName: &name, StartDate: &july, EndDate: &july}).
+		Return(&models.SummaryTotals{Gross: 1000, Discount: 100, Net: 900}, nil)
+	mockSummaries.On("GetSummary", mock.Anything, postgres.GetSummaryFilter{UserID: &userID, ServiceName: &name, StartDate: &august, EndDate: &august}).
+		Return(&models.SummaryTotals{Gross: 1200, Net: 1200}, nil)
+
+	got, err := service.Status(context.Background(), userID)
+
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, july, got[0].Month)
+	assert.Equal(t, 900, got[0].Spent)
+	assert.Equal(t, 100, got[0].Remaining)
+	assert.Equal(t, 90, got[0].SpentPercent)
+	assert.Equal(t, 1200, got[0].Projected)
+	assert.Equal(t, 120, got[0].ProjectedPercent)
+	assert.Equal(t, []int{80}, got[0].ReachedThresholds)
+	mockSummaries.AssertExpectations(t)
+}
+
+func TestBudgetAlerter_CheckOnce(t *testing.T) {
+	mockBudgets := new(MockBudgetRepository)
+	mockSummaries := new(MockRepository)
+	mockNotifications := new(MockNotificationRepository)
+	mockNotifier := new(MockNotifier)
+	alerter := NewBudgetAlerter(mockBudgets, mockSummaries, mockNotifications, mockNotifier)
+	alerter.now = func() time.Time { return budgetNow }
+
+	budget := models.Budget{ID: uuid.New(), UserID: uuid.New(), Scope: models.BudgetOverall, Amount: 1000, Thresholds: []int{80, 100}}
+
+	mockBudgets.On("List", mock.Anything, (*uuid.UUID)(nil)).Return([]models.Budget{budget}, nil)
+	mockSummaries.On("GetSummary", mock.Anything, mock.Anything).Return(&models.SummaryTotals{Gross: 1100, Net: 1100}, nil)
+	// О пороге 80% пользователь уже уведомлён в этом месяце.
+	mockNotifications.On("Record", mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return *n.Threshold == 80
+	})).Return(false, nil)
+	mockNotifications.On("Record", mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return *n.Threshold == 100 && *n.BudgetID == budget.ID &&
+			n.Kind == models.NotificationBudgetThreshold && n.DueAt.Equal(models.NewMonth(2025, time.July).Time())
+	})).Return(true, nil)
+	mockNotifier.On("Notify", mock.Anything, mock.Anything).Return(nil).Once()
+
+	sent, err := alerter.CheckOnce(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	mockNotifications.AssertExpectations(t)
+	mockNotifier.AssertExpectations(t)
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"effective-mobile-task/internal/models"
+	"effective-mobile-task/internal/repository/postgres"
+	"github.com/google/uuid"
+)
+
+// defaultCurrency — валюта сервиса, если она не указана.
+const defaultCurrency = "RUB"
+
+type ServiceRepository interface {
+	ServiceResolver
+	Create(ctx context.Context, svc *models.Service) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Service, error)
+	List(ctx context.Context, category *string) ([]models.Service, error)
+	Update(ctx context.Context, svc *models.Service) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// CatalogService ведёт каталог сервисов, к которым привязываются подписки.
+// Менять каталог может только администратор.
+type CatalogService struct {
+	repo   ServiceRepository
+	policy Authorizer
+}
+
+func NewCatalogService(repo ServiceRepository, policy Authorizer) *CatalogService {
+	return &CatalogService{repo: repo, policy: policy}
+}
+
+type ServiceDTO struct {
+	Name         string   `json:"name" validate:"required,min=2,max=100"`
+	Aliases      []string `json:"aliases" validate:"max=50,dive,required,min=2,max=100"`
+	Category     *string  `json:"category,omitempty" validate:"omitempty,min=2,max=100"`
+	Website      *string  `json:"website,omitempty" validate:"omitempty,url,max=2048"`
+	DefaultPrice *int     `json:"default_price,omitempty" validate:"omitempty,gte=0"`
+	Currency     string   `json:"currency,omitempty" validate:"omitempty,len=3,alpha"`
+}
+
+func (s *CatalogService) Create(ctx context.Context, dto ServiceDTO) (*models.Service, error) {
+	if err := authorize(ctx, s.policy, ActionAdminister, nil); err != nil {
+		return nil, err
+	}
+
+	svc, err := newService(uuid.New(), dto)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, svc); err != nil {
+		return nil, fmt.Errorf("не удалось создать сервис: %w", err)
+	}
+
+	return svc, nil
+}
+
+func (s *CatalogService) GetByID(ctx context.Context, id uuid.UUID) (*models.Service, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *CatalogService) List(ctx context.Context, category *string) ([]models.Service, error) {
+	return s.repo.List(ctx, category)
+}
+
+func (s *CatalogService) Update(ctx context.Context, id uuid.UUID, dto ServiceDTO) (*models.Service, error) {
+	if err := authorize(ctx, s.policy, ActionAdminister, nil); err != nil {
+		return nil, err
+	}
+
+	svc, err := newService(id, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, svc); err != nil {
+		return nil, fmt.Errorf("не удалось обновить сервис: %w", err)
+	}
+
+	return svc, nil
+}
+
+func (s *CatalogService) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := authorize(ctx, s.policy, ActionAdminister, nil); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// newService нормализует название и псевдонимы так же, как названия подписок,
+// и убирает псевдонимы, совпадающие с названием или друг с другом.
+func newService(id uuid.UUID, dto ServiceDTO) (*models.Service, error) {
+	name, err := canonicalName("name", dto.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{postgres.AliasKey(name): true}
+	aliases := make([]string, 0, len(dto.Aliases))
+	for _, raw := range dto.Aliases {
+		alias, err := canonicalName("aliases", raw)
+		if err != nil {
+			return nil, err
+		}
+		if key := postgres.AliasKey(alias); !seen[key] {
+			seen[key] = true
+			aliases = append(aliases, alias)
+		}
+	}
+
+	var category *string
+	if dto.Category != nil {
+		c := strings.Join(strings.Fields(*dto.Category), " ")
+		category = &c
+	}
+
+	currency := strings.ToUpper(dto.Currency)
+	if currency == "" {
+		currency = defaultCurrency
+	}
+
+	return &models.Service{
+		ID:           id,
+		Name:         name,
+		Aliases:      aliases,
+		Category:     category,
+		Website:      dto.Website,
+		DefaultPrice: dto.DefaultPrice,
+		Currency:     currency,
+	}, nil
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"effective-mobile-task/internal/models"
+	"effective-mobile-task/internal/repository/postgres"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockServiceRepository struct {
+	mock.Mock
+}
+
+func (m *MockServiceRepository) Resolve(ctx context.Context, name string) (*models.Service, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Service), args.Error(1)
+}
+
+func (m *MockServiceRepository) Create(ctx context.Context, svc *models.Service) error {
+	args := m.Called(ctx, svc)
+	return args.Error(0)
+}
+
+func (m *MockServiceRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Service, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Service), args.Error(1)
+}
+
+func (m *MockServiceRepository) List(ctx context.Context, category *string) ([]models.Service, error) {
+	args := m.Called(ctx, category)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Service), args.Error(1)
+}
+
+func (m *MockServiceRepository) Update(ctx context.Context, svc *models.Service) error {
+	args := m.Called(ctx, svc)
+	return args.Error(0)
+}
+
+func (m *MockServiceRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestCatalogService_Create_NormalizesAliases(t *testing.T) {
+	mockRepo := new(MockServiceRepository)
+	catalog := NewCatalogService(mockRepo, nil)
+
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Service")).Return(nil)
+
+	svc, err := catalog.Create(context.Background(), ServiceDTO{
+		Name:    " Yandex  Plus",
+		Aliases: []string{"Яндекс Плюс", "yandex plus", "ЯНДЕКС  ПЛЮС"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Yandex Plus", svc.Name)
+	assert.Equal(t, []string{"Яндекс Плюс"}, svc.Aliases)
+	assert.Equal(t, "RUB", svc.Currency)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSubscriptionService_Create_ResolvesCatalogAlias(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockCatalog := new(MockServiceRepository)
+	service := NewSubscriptionService(mockRepo, WithServiceCatalog(mockCatalog))
+
+	catalogued := &models.Service{ID: uuid.New(), Name: "Yandex Plus"}
+	mockCatalog.On("Resolve", mock.Anything, "Яндекс Плюс").Return(catalogued, nil)
+	mockRepo.On("FindOverlapping", mock.Anything, mock.Anything).Return(nil, postgres.ErrNotFound)
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Subscription")).Return(nil)
+
+	sub, err := service.Create(context.Background(), CreateSubscriptionDTO{
+		UserID:      uuid.New(),
+		ServiceName: "Яндекс  Плюс",
+		Price:       400,
+		StartDate:   models.NewMonth(2025, time.July),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Yandex Plus", sub.ServiceName)
+	require.NotNil(t, sub.ServiceID)
+	assert.Equal(t, catalogued.ID, *sub.ServiceID)
+	mockCatalog.AssertExpectations(t)
+}
+
+func TestSubscriptionService_Create_UnknownServiceStaysUnlinked(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockCatalog := new(MockServiceRepository)
+	service := NewSubscriptionService(mockRepo, WithServiceCatalog(mockCatalog))
+
+	mockCatalog.On("Resolve", mock.Anything, "Home Gym").Return(nil, postgres.ErrServiceNotFound)
+	mockRepo.On("FindOverlapping", mock.Anything, mock.Anything).Return(nil, postgres.ErrNotFound)
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Subscription")).Return(nil)
+
+	sub, err := service.Create(context.Background(), CreateSubscriptionDTO{
+		UserID:      uuid.New(),
+		ServiceName: "Home Gym",
+		Price:       1500,
+		StartDate:   models.NewMonth(2025, time.July),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Home Gym", sub.ServiceName)
+	assert.Nil(t, sub.ServiceID)
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"effective-mobile-task/internal/models"
+	"github.com/google/uuid"
+)
+
+// DiscountDTO — скидка на подписку. Без StartMonth скидка начинается с
+// первого оплачиваемого месяца: после пробного периода или с начала
+// подписки. Срок задаётся либо EndMonth, либо числом месяцев Months; без
+// них скидка действует до конца подписки.
+type DiscountDTO struct {
+	Kind       models.DiscountKind `json:"kind" validate:"required,oneof=percent fixed" enums:"percent,fixed"`
+	Value      int                 `json:"value" validate:"required,gt=0"`
+	StartMonth *models.Month       `json:"start_month,omitempty" swaggertype:"string" example:"07-2025"`
+	EndMonth   *models.Month       `json:"end_month,omitempty" swaggertype:"string" example:"09-2025"`
+	Months     int                 `json:"months,omitempty" validate:"omitempty,gt=0" example:"3"`
+	PromoCode  *string             `json:"promo_code,omitempty" validate:"omitempty,max=64" example:"WELCOME50"`
+}
+
+// AddDiscount добавляет скидку на подписку и возвращает её.
+func (s *SubscriptionService) AddDiscount(ctx context.Context, id uuid.UUID, dto DiscountDTO) (*models.Discount, error) {
+	sub, err := s.authorizedSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := newDiscount(sub, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.AddDiscount(ctx, d); err != nil {
+		return nil, fmt.Errorf("не удалось добавить скидку: %w", err)
+	}
+
+	return d, nil
+}
+
+// ListDiscounts возвращает скидки подписки.
+func (s *SubscriptionService) ListDiscounts(ctx context.Context, id uuid.UUID) ([]models.Discount, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDiscounts(ctx, id)
+}
+
+// DeleteDiscount удаляет скидку подписки.
+func (s *SubscriptionService) DeleteDiscount(ctx context.Context, id, discountID uuid.UUID) error {
+	if _, err := s.authorizedSubscription(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.DeleteDiscount(ctx, id, discountID)
+}
+
+// newDiscount строит скидку из dto и проверяет, что она укладывается в
+// период подписки.
+func newDiscount(sub *models.Subscription, dto DiscountDTO) (*models.Discount, error) {
+	switch dto.Kind {
+	case models.DiscountPercent:
+		if dto.Value <= 0 || dto.Value > 100 {
+			return nil, &ValidationError{Field: "value", Rule: RuleDiscountPercent}
+		}
+	case models.DiscountFixed:
+		if dto.Value <= 0 {
+			return nil, &ValidationError{Field: "value", Rule: RulePricePositive}
+		}
+	default:
+		return nil, &ValidationError{Field: "kind", Rule: RuleInvalidFormat}
+	}
+
+	start := sub.StartDate
+	if sub.TrialEndDate != nil {
+		start = sub.TrialEndDate.AddMonths(1)
+	}
+	if dto.StartMonth != nil {
+		start = *dto.StartMonth
+	}
+
+	end := dto.EndMonth
+	if dto.Months > 0 {
+		if end != nil {
+			return nil, &ValidationError{Field: "months", Rule: RuleDiscountTerm}
+		}
+		last := start.AddMonths(dto.Months - 1)
+		end = &last
+	}
+
+	if end != nil && end.Before(start) {
+		return nil, &ValidationError{Field: "end_month", Rule: RuleEndBeforeStart}
+	}
+	if start.Before(sub.StartDate) || (sub.EndDate != nil && start.After(*sub.EndDate)) {
+		return nil, &ValidationError{Field: "start_month", Rule: RuleDiscountOutsidePeriod}
+	}
+	if end != nil && sub.EndDate != nil && end.After(*sub.EndDate) {
+		return nil, &ValidationError{Field: "end_month", Rule: RuleDiscountOutsidePeriod}
+	}
+
+	return &models.Discount{
+		ID:             uuid.New(),
+		SubscriptionID: sub.ID,
+		Kind:           dto.Kind,
+		Value:          dto.Value,
+		StartMonth:     start,
+		EndMonth:       end,
+		PromoCode:      dto.PromoCode,
+	}, nil
+}
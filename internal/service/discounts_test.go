@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"effective-mobile-task/internal/models"
+	"effective-mobile-task/internal/repository/postgres"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptionService_AddDiscount(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+
+	trialEnd := models.NewMonth(2025, time.February)
+	sub := &models.Subscription{ID: uuid.New(), Price: 400, StartDate: models.NewMonth(2025, time.January), TrialEndDate: &trialEnd}
+	code := "WELCOME50"
+
+	mockRepo.On("GetByID", mock.Anything, sub.ID).Return(sub, nil)
+	mockRepo.On("AddDiscount", mock.Anything, mock.AnythingOfType("*models.Discount")).Return(nil)
+
+	got, err := service.AddDiscount(context.Background(), sub.ID, DiscountDTO{
+		Kind:      models.DiscountPercent,
+		Value:     50,
+		Months:    3,
+		PromoCode: &code,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, sub.ID, got.SubscriptionID)
+	assert.Equal(t, models.NewMonth(2025, time.March), got.StartMonth, "скидка начинается после пробного периода")
+	require.NotNil(t, got.EndMonth)
+	assert.Equal(t, models.NewMonth(2025, time.May), *got.EndMonth)
+	assert.Equal(t, &code, got.PromoCode)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSubscriptionService_AddDiscount_Rejected(t *testing.T) {
+	start := models.NewMonth(2025, time.January)
+	end := models.NewMonth(2025, time.June)
+	march := models.NewMonth(2025, time.March)
+	july := models.NewMonth(2025, time.July)
+	before := models.NewMonth(2024, time.December)
+
+	tests := []struct {
+		name string
+		dto  DiscountDTO
+		rule string
+	}{
+		{name: "percent over 100", dto: DiscountDTO{Kind: models.DiscountPercent, Value: 150}, rule: RuleDiscountPercent},
+		{name: "end and months", dto: DiscountDTO{Kind: models.DiscountFixed, Value: 100, EndMonth: &march, Months: 2}, rule: RuleDiscountTerm},
+		{name: "end before start", dto: DiscountDTO{Kind: models.DiscountFixed, Value: 100, StartMonth: &march, EndMonth: &start}, rule: RuleEndBeforeStart},
+		{name: "start before subscription", dto: DiscountDTO{Kind: models.DiscountFixed, Value: 100, StartMonth: &before}, rule: RuleDiscountOutsidePeriod},
+		{name: "end after subscription", dto: DiscountDTO{Kind: models.DiscountFixed, Value: 100, EndMonth: &july}, rule: RuleDiscountOutsidePeriod},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			service := NewSubscriptionService(mockRepo)
+
+			sub := &models.Subscription{ID: uuid.New(), Price: 400, StartDate: start, EndDate: &end}
+			mockRepo.On("GetByID", mock.Anything, sub.ID).Return(sub, nil)
+
+			_, err := service.AddDiscount(context.Background(), sub.ID, tt.dto)
+
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.rule, validationErr.Rule)
+			mockRepo.AssertNotCalled(t, "AddDiscount", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestSubscriptionService_ListDiscounts_NotFound(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+
+	id := uuid.New()
+	mockRepo.On("GetByID", mock.Anything, id).Return(nil, postgres.ErrNotFound)
+
+	_, err := service.ListDiscounts(context.Background(), id)
+
+	assert.ErrorIs(t, err, postgres.ErrNotFound)
+	mockRepo.AssertNotCalled(t, "ListDiscounts", mock.Anything, mock.Anything)
+}
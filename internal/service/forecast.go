@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"effective-mobile-task/internal/models"
+	"effective-mobile-task/internal/repository/postgres"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultForecastMonths = 12
+	maxForecastMonths     = 60
+)
+
+// Forecast прогнозирует расходы каждого пользователя на подписки фильтра
+// на months месяцев вперёд, начиная со следующего месяца. Учитываются даты
+// окончания подписок и запланированные изменения цен, мест и скидок;
+// приостановленные подписки в прогноз не попадают. Даты фильтра
+// игнорируются. months <= 0 означает срок по умолчанию.
+func (s *SubscriptionService) Forecast(ctx context.Context, filter postgres.GetSummaryFilter, months int) (*models.Forecast, error) {
+	switch {
+	case months <= 0:
+		months = defaultForecastMonths
+	case months > maxForecastMonths:
+		months = maxForecastMonths
+	}
+
+	from := models.MonthOf(s.now()).AddMonths(1)
+	to := from.AddMonths(months - 1)
+	filter.StartDate, filter.EndDate = &from, &to
+
+	spend, err := s.repo.MonthlySpend(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось спрогнозировать расходы: %w", err)
+	}
+
+	forecast := &models.Forecast{From: from, To: to, Users: []models.UserForecast{}}
+	index := make(map[uuid.UUID]int)
+	for _, m := range spend {
+		i, ok := index[m.UserID]
+		if !ok {
+			i = len(forecast.Users)
+			index[m.UserID] = i
+			forecast.Users = append(forecast.Users, newUserForecast(m.UserID, from, months))
+		}
+
+		user := &forecast.Users[i]
+		user.Months[monthsBetween(from, m.Month)].Amount = m.Amount
+		user.Total += m.Amount
+		forecast.Total += m.Amount
+	}
+
+	return forecast, nil
+}
+
+// newUserForecast возвращает прогноз пользователя с нулевыми расходами в
+// каждом месяце, чтобы в ответе не было пропусков.
+func newUserForecast(userID uuid.UUID, from models.Month, months int) models.UserForecast {
+	f := models.UserForecast{UserID: userID, Months: make([]models.MonthlySpend, months)}
+	for i := range f.Months {
+		f.Months[i] = models.MonthlySpend{UserID: userID, Month: from.AddMonths(i)}
+	}
+	return f
+}
+
+// monthsBetween возвращает число месяцев от from до to.
+func monthsBetween(from, to models.Month) int {
+	return (to.Year()-from.Year())*12 + int(to.Month()-from.Month())
+}
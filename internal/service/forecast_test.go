@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"effective-mobile-task/internal/models"
+	"effective-mobile-task/internal/repository/postgres"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptionService_Forecast(t *testing.T) {
+	mockRepo := new(MockRepository)
+	now := time.Date(2025, time.November, 10, 9, 0, 0, 0, time.UTC)
+	service := NewSubscriptionService(mockRepo, WithClock(func() time.Time { return now }))
+
+	first, second := uuid.New(), uuid.New()
+	december := models.NewMonth(2025, time.December)
+	february := models.NewMonth(2026, time.February)
+
+	mockRepo.On("MonthlySpend", mock.Anything, postgres.GetSummaryFilter{UserID: nil, StartDate: &december, EndDate: &february}).
+		Return([]models.MonthlySpend{
+			{UserID: first, Month: december, Amount: 400},
+			{UserID: first, Month: february, Amount: 500},
+			{UserID: second, Month: models.NewMonth(2026, time.January), Amount: 300},
+		}, nil)
+
+	got, err := service.Forecast(context.Background(), postgres.GetSummaryFilter{}, 3)
+
+	require.NoError(t, err)
+	assert.Equal(t, december, got.From)
+	assert.Equal(t, february, got.To)
+	assert.Equal(t, 1200, got.Total)
+	require.Len(t, got.Users, 2)
+
+	assert.Equal(t, first, got.Users[0].UserID)
+	assert.Equal(t, 900, got.Users[0].Total)
+	amounts := make([]int, 0, 3)
+	for _, m := range got.Users[0].Months {
+		amounts = append(amounts, m.Amount)
+	}
+	assert.Equal(t, []int{400, 0, 500}, amounts, "месяцы без расходов заполняются нулями")
+
+	assert.Equal(t, second, got.Users[1].UserID)
+	assert.Equal(t, 300, got.Users[1].Months[1].Amount)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSubscriptionService_Forecast_DefaultMonths(t *testing.T) {
+	mockRepo := new(MockRepository)
+	now := time.Date(2025, time.November, 10, 9, 0, 0, 0, time.UTC)
+	service := NewSubscriptionService(mockRepo, WithClock(func() time.Time { return now }))
+
+	mockRepo.On("MonthlySpend", mock.Anything, mock.MatchedBy(func(f postgres.GetSummaryFilter) bool {
+		return f.EndDate.Equal(models.NewMonth(2026, time.November))
+	})).Return([]models.MonthlySpend{}, nil)
+
+	got, err := service.Forecast(context.Background(), postgres.GetSummaryFilter{}, 0)
+
+	require.NoError(t, err)
+	assert.Empty(t, got.Users)
+	mockRepo.AssertExpectations(t)
+}
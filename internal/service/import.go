@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"effective-mobile-task/internal/models"
+	"effective-mobile-task/internal/repository/postgres"
+	"github.com/google/uuid"
+)
+
+// maxImportErrors ограничивает число ошибок в отчёте, чтобы файл из
+// миллиона некорректных строк не превращался в такой же огромный ответ.
+const maxImportErrors = 1000
+
+type ImportLineError struct {
+	Line  int    `json:"line"`
+	Field string `json:"field,omitempty"`
+	Error string `json:"error"`
+
+	// Err — исходная ошибка строки; текст для клиента заполняет обработчик.
+	Err error `json:"-"`
+}
+
+type ImportReport struct {
+	DryRun      bool              `json:"dry_run"`
+	Committed   bool              `json:"committed"`
+	TotalRows   int               `json:"total_rows"`
+	ValidRows   int               `json:"valid_rows"`
+	InvalidRows int               `json:"invalid_rows"`
+	Imported    int64             `json:"imported"`
+	Errors      []ImportLineError `json:"errors"`
+}
+
+func (r *ImportReport) addError(line int, err error) {
+	r.InvalidRows++
+	if len(r.Errors) >= maxImportErrors {
+		return
+	}
+
+	lineErr := ImportLineError{Line: line, Err: err}
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		lineErr.Field = validationErr.Field
+	}
+	r.Errors = append(r.Errors, lineErr)
+}
+
+// Import читает подписки из источника построчно и проверяет каждую строку
+// по тем же правилам, что и Create. Строки, прошедшие проверки без базы,
+// копируются во временную таблицу, где каталог, права и пересечения
+// проверяются сразу для всего файла. В режиме dryRun данные только
+// проверяются; иначе все строки вставляются одной транзакцией, которая
+// откатывается, если хотя бы одна строка содержит ошибку.
+func (s *SubscriptionService) Import(ctx context.Context, src RowSource, dryRun bool) (*ImportReport, error) {
+	report := &ImportReport{DryRun: dryRun, Errors: []ImportLineError{}}
+
+	next := func() (*postgres.ImportedSubscription, error) {
+		for {
+			row, err := src.Next()
+			if errors.Is(err, io.EOF) {
+				return nil, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			report.TotalRows++
+
+			sub, err := prepareImportRow(row)
+			if err != nil {
+				if !isDomainError(err) {
+					return nil, err
+				}
+				report.addError(row.Line, err)
+				continue
+			}
+
+			report.ValidRows++
+			return &postgres.ImportedSubscription{Line: row.Line, Subscription: sub}, nil
+		}
+	}
+
+	checks := postgres.ImportChecks{
+		ResolveServices: s.catalog != nil,
+		AllowOverlap:    s.allowOverlap,
+		Overlap: func(line int, existingID uuid.UUID) {
+			report.ValidRows--
+			report.addError(line, &OverlapError{ExistingID: existingID})
+		},
+		Commit: func() bool {
+			return !dryRun && report.InvalidRows == 0
+		},
+	}
+	if s.policy != nil {
+		checks.Authorize = func(ctx context.Context, userID uuid.UUID) error {
+			return s.authorize(ctx, ActionCreate, &userID)
+		}
+	}
+
+	imported, err := s.repo.ImportSubscriptions(ctx, next, checks)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось импортировать подписки: %w", err)
+	}
+
+	// Ошибки проверок в базе приходят после ошибок разбора всех строк,
+	// поэтому отчёт упорядочивается по номерам строк.
+	sort.SliceStable(report.Errors, func(i, j int) bool {
+		return report.Errors[i].Line < report.Errors[j].Line
+	})
+	report.Imported = imported
+	report.Committed = checks.Commit()
+
+	return report, nil
+}
+
+// prepareImportRow проверяет строку файла и собирает из неё подписку.
+// Проверки, которым нужна база, выполняет ImportSubscriptions.
+func prepareImportRow(row *ImportRow) (*models.Subscription, error) {
+	if row.Err != nil {
+		return nil, row.Err
+	}
+
+	if err := validateCreateDTO(row.DTO); err != nil {
+		return nil, err
+	}
+
+	return buildSubscription(row.DTO)
+}
+
+// validateCreateDTO повторяет проверки тегов validate из CreateSubscriptionDTO
+// для данных, которые пришли не через JSON-обработчик.
+func validateCreateDTO(dto CreateSubscriptionDTO) error {
+	switch {
+	case dto.UserID == uuid.Nil:
+		return &ValidationError{Field: ImportFieldUserID, Rule: RuleRequired}
+	case strings.TrimSpace(dto.ServiceName) == "":
+		return &ValidationError{Field: ImportFieldServiceName, Rule: RuleRequired}
+	case dto.Price <= 0 && dto.UnitPrice <= 0:
+		return &ValidationError{Field: ImportFieldPrice, Rule: RulePricePositive}
+	case dto.StartDate.IsZero():
+		return &ValidationError{Field: ImportFieldStartDate, Rule: RuleRequired}
+	}
+	return nil
+}
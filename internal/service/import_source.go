@@ -0,0 +1,305 @@
+package service
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"effective-mobile-task/internal/models"
+	"github.com/google/uuid"
+)
+
+// ImportRow — одна строка импортируемого файла. Если строку не удалось
+// разобрать, Err содержит причину, а DTO заполнено частично.
+type ImportRow struct {
+	Line int
+	DTO  CreateSubscriptionDTO
+	Err  error
+}
+
+// RowSource последовательно читает строки файла импорта.
+// По окончании данных Next возвращает io.EOF.
+type RowSource interface {
+	Next() (*ImportRow, error)
+}
+
+// Поля подписки, которые можно загрузить из CSV.
+const (
+	ImportFieldUserID       = "user_id"
+	ImportFieldServiceName  = "service_name"
+	ImportFieldPrice        = "price"
+	ImportFieldStartDate    = "start_date"
+	ImportFieldEndDate      = "end_date"
+	ImportFieldTrialEndDate = "trial_end_date"
+	ImportFieldUnitPrice    = "unit_price"
+	ImportFieldQuantity     = "quantity"
+)
+
+var (
+	requiredImportFields = []string{ImportFieldUserID, ImportFieldServiceName, ImportFieldStartDate}
+	optionalImportFields = []string{ImportFieldPrice, ImportFieldEndDate, ImportFieldTrialEndDate, ImportFieldUnitPrice, ImportFieldQuantity}
+)
+
+// ErrMissingColumn — в заголовке CSV нет обязательной колонки.
+var ErrMissingColumn = errors.New("в файле нет обязательной колонки")
+
+// MissingColumnError указывает, какой колонки не хватает в заголовке CSV.
+type MissingColumnError struct {
+	Column string
+}
+
+func (e *MissingColumnError) Error() string {
+	return fmt.Sprintf("в файле нет обязательной колонки %q", e.Column)
+}
+
+func (e *MissingColumnError) Unwrap() error {
+	return ErrMissingColumn
+}
+
+type CSVOptions struct {
+	// Delimiter — разделитель полей, по умолчанию запятая.
+	Delimiter rune
+	// Headers сопоставляет поле подписки с названием колонки в файле,
+	// если они различаются, например service_name → "Сервис".
+	Headers map[string]string
+}
+
+type csvSource struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+// NewCSVSource читает заголовок CSV и возвращает источник строк.
+// Названия колонок сравниваются без учёта регистра и пробелов по краям.
+func NewCSVSource(r io.Reader, opts CSVOptions) (RowSource, error) {
+	reader := csv.NewReader(r)
+	if opts.Delimiter != 0 {
+		reader.Comma = opts.Delimiter
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, &MissingColumnError{Column: ImportFieldUserID}
+		}
+		return nil, fmt.Errorf("не удалось прочитать заголовок CSV: %w", err)
+	}
+
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		positions[normalizeHeader(name)] = i
+	}
+
+	columns := make(map[string]int)
+	lookup := func(field string) (int, bool) {
+		name := field
+		if mapped, ok := opts.Headers[field]; ok && mapped != "" {
+			name = mapped
+		}
+		idx, ok := positions[normalizeHeader(name)]
+		return idx, ok
+	}
+
+	for _, field := range requiredImportFields {
+		idx, ok := lookup(field)
+		if !ok {
+			return nil, &MissingColumnError{Column: field}
+		}
+		columns[field] = idx
+	}
+	for _, field := range optionalImportFields {
+		if idx, ok := lookup(field); ok {
+			columns[field] = idx
+		}
+	}
+
+	// Цена задаётся либо целиком, либо ценой места.
+	_, hasPrice := columns[ImportFieldPrice]
+	_, hasUnitPrice := columns[ImportFieldUnitPrice]
+	if !hasPrice && !hasUnitPrice {
+		return nil, &MissingColumnError{Column: ImportFieldPrice}
+	}
+
+	return &csvSource{reader: reader, columns: columns}, nil
+}
+
+func (s *csvSource) Next() (*ImportRow, error) {
+	for {
+		record, err := s.reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return &ImportRow{
+				Line: parseErr.StartLine,
+				Err:  &ValidationError{Field: "", Rule: RuleInvalidFormat},
+			}, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("не удалось прочитать CSV: %w", err)
+		}
+
+		if isBlankRecord(record) {
+			continue
+		}
+
+		line, _ := s.reader.FieldPos(0)
+		row := &ImportRow{Line: line}
+		row.DTO, row.Err = s.parseRecord(record)
+		return row, nil
+	}
+}
+
+func (s *csvSource) parseRecord(record []string) (CreateSubscriptionDTO, error) {
+	var dto CreateSubscriptionDTO
+
+	value := func(field string) string {
+		idx, ok := s.columns[field]
+		if !ok || idx >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[idx])
+	}
+
+	userID, err := uuid.Parse(value(ImportFieldUserID))
+	if err != nil {
+		return dto, &ValidationError{Field: ImportFieldUserID, Rule: RuleInvalidFormat}
+	}
+	dto.UserID = userID
+
+	dto.ServiceName = value(ImportFieldServiceName)
+
+	if raw := value(ImportFieldPrice); raw != "" || value(ImportFieldUnitPrice) == "" {
+		if dto.Price, err = parsePrice(ImportFieldPrice, raw); err != nil {
+			return dto, err
+		}
+	}
+
+	if raw := value(ImportFieldUnitPrice); raw != "" {
+		if dto.UnitPrice, err = parsePrice(ImportFieldUnitPrice, raw); err != nil {
+			return dto, err
+		}
+	}
+
+	if raw := value(ImportFieldQuantity); raw != "" {
+		dto.Quantity, err = strconv.Atoi(raw)
+		if err != nil || dto.Quantity <= 0 {
+			return dto, &ValidationError{Field: ImportFieldQuantity, Rule: RuleInvalidFormat}
+		}
+	}
+
+	dto.StartDate, err = models.ParseMonth(value(ImportFieldStartDate))
+	if err != nil {
+		return dto, &ValidationError{Field: ImportFieldStartDate, Rule: RuleInvalidFormat}
+	}
+
+	if raw := value(ImportFieldEndDate); raw != "" {
+		end, err := models.ParseMonth(raw)
+		if err != nil {
+			return dto, &ValidationError{Field: ImportFieldEndDate, Rule: RuleInvalidFormat}
+		}
+		dto.EndDate = &end
+	}
+
+	if raw := value(ImportFieldTrialEndDate); raw != "" {
+		trialEnd, err := models.ParseMonth(raw)
+		if err != nil {
+			return dto, &ValidationError{Field: ImportFieldTrialEndDate, Rule: RuleInvalidFormat}
+		}
+		dto.TrialEndDate = &trialEnd
+	}
+
+	return dto, nil
+}
+
+func normalizeHeader(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func isBlankRecord(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// parsePrice разбирает цену в рублях, записанную как целое число или
+// десятичная дробь: "399", "399.00", "1 299,00". Копейки не поддерживаются,
+// поэтому дробная часть должна быть нулевой.
+func parsePrice(field, raw string) (int, error) {
+	cleaned := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\u00a0', '\u202f':
+			return -1
+		case ',':
+			return '.'
+		}
+		return r
+	}, raw)
+
+	whole, fraction, _ := strings.Cut(cleaned, ".")
+	if strings.Trim(fraction, "0") != "" {
+		return 0, &ValidationError{Field: field, Rule: RulePriceFraction}
+	}
+
+	price, err := strconv.Atoi(whole)
+	if err != nil {
+		return 0, &ValidationError{Field: field, Rule: RuleInvalidFormat}
+	}
+
+	return price, nil
+}
+
+// maxNDJSONLineSize ограничивает длину одной строки NDJSON.
+const maxNDJSONLineSize = 1 << 20
+
+type ndjsonSource struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// NewNDJSONSource возвращает источник строк для формата NDJSON: по одному
+// JSON-объекту подписки в строке, как в теле POST /subscriptions.
+func NewNDJSONSource(r io.Reader) RowSource {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxNDJSONLineSize)
+	return &ndjsonSource{scanner: scanner}
+}
+
+func (s *ndjsonSource) Next() (*ImportRow, error) {
+	for s.scanner.Scan() {
+		s.line++
+
+		data := strings.TrimSpace(s.scanner.Text())
+		if data == "" {
+			continue
+		}
+
+		row := &ImportRow{Line: s.line}
+		if err := json.Unmarshal([]byte(data), &row.DTO); err != nil {
+			row.Err = &ValidationError{Field: "", Rule: RuleInvalidFormat}
+		}
+		return row, nil
+	}
+
+	if err := s.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("не удалось прочитать NDJSON: %w", err)
+	}
+
+	return nil, io.EOF
+}
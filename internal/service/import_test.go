@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"effective-mobile-task/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const importCSV = `Пользователь;Сервис;Цена;start_date;end_date
+60601fee-2bf1-4721-ae6f-7636e79a0cba;Yandex Plus;"1 299,00";07-2025;
+60601fee-2bf1-4721-ae6f-7636e79a0cba;Netflix;399.50;2025-07-01;
+
+not-a-uuid;Spotify;199;07-2025;
+60601fee-2bf1-4721-ae6f-7636e79a0cba;Okko;299;2025-08;2025-06
+`
+
+func newImportCSVSource(t *testing.T) RowSource {
+	src, err := NewCSVSource(strings.NewReader(importCSV), CSVOptions{
+		Delimiter: ';',
+		Headers: map[string]string{
+			ImportFieldUserID:      "пользователь",
+			ImportFieldServiceName: "Сервис",
+			ImportFieldPrice:       "Цена",
+		},
+	})
+	require.NoError(t, err)
+	return src
+}
+
+func TestCSVSource_ParsesMappedColumns(t *testing.T) {
+	src := newImportCSVSource(t)
+
+	row, err := src.Next()
+	require.NoError(t, err)
+	assert.NoError(t, row.Err)
+	assert.Equal(t, 2, row.Line)
+	assert.Equal(t, "Yandex Plus", row.DTO.ServiceName)
+	assert.Equal(t, 1299, row.DTO.Price)
+	assert.Equal(t, models.NewMonth(2025, time.July), row.DTO.StartDate)
+	assert.Nil(t, row.DTO.EndDate)
+}
+
+func TestCSVSource_MissingColumn(t *testing.T) {
+	_, err := NewCSVSource(strings.NewReader("user_id,service_name,start_date\n"), CSVOptions{})
+
+	assert.ErrorIs(t, err, ErrMissingColumn)
+}
+
+func TestSubscriptionService_Import_DryRunReportsLineErrors(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+
+	mockRepo.On("ImportSubscriptions", mock.Anything).Return(nil, nil)
+
+	report, err := service.Import(context.Background(), newImportCSVSource(t), true)
+
+	require.NoError(t, err)
+	assert.False(t, report.Committed)
+	assert.Equal(t, 4, report.TotalRows)
+	assert.Equal(t, 1, report.ValidRows)
+	assert.Equal(t, 3, report.InvalidRows)
+	require.Len(t, report.Errors, 3)
+	assert.Equal(t, 3, report.Errors[0].Line)
+	assert.Equal(t, ImportFieldPrice, report.Errors[0].Field)
+	assert.Equal(t, 5, report.Errors[1].Line)
+	assert.Equal(t, ImportFieldUserID, report.Errors[1].Field)
+	assert.Equal(t, 6, report.Errors[2].Line)
+	assert.Equal(t, "end_date", report.Errors[2].Field)
+	assert.Zero(t, report.Imported)
+}
+
+func TestSubscriptionService_Import_RejectsFileWithErrors(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+
+	mockRepo.On("ImportSubscriptions", mock.Anything).Return(nil, nil)
+
+	report, err := service.Import(context.Background(), newImportCSVSource(t), false)
+
+	require.NoError(t, err)
+	assert.False(t, report.Committed)
+	assert.Zero(t, report.Imported)
+	assert.Equal(t, 3, report.InvalidRows)
+}
+
+func TestSubscriptionService_Import_NDJSON(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+
+	ndjson := `{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba","service_name":"Yandex Plus","price":400,"start_date":"07-2025"}
+
+{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba","service_name":"yandex  plus","price":400,"start_date":"2025-09"}
+{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba","service_name":"Okko","price":300,"start_date":"2025-09"}
+`
+
+	// Пересечение второй строки с первой находит запрос к временной таблице.
+	firstID := uuid.New()
+	mockRepo.On("ImportSubscriptions", mock.Anything).Return(map[int]uuid.UUID{3: firstID}, nil)
+
+	report, err := service.Import(context.Background(), NewNDJSONSource(strings.NewReader(ndjson)), false)
+
+	require.NoError(t, err)
+	assert.False(t, report.Committed)
+	assert.Equal(t, 2, report.ValidRows)
+	require.Len(t, report.Errors, 1)
+	assert.Equal(t, 3, report.Errors[0].Line)
+	assert.ErrorIs(t, report.Errors[0].Err, ErrOverlappingSubscription)
+	var overlapErr *OverlapError
+	require.ErrorAs(t, report.Errors[0].Err, &overlapErr)
+	assert.Equal(t, firstID, overlapErr.ExistingID)
+}
+
+func TestSubscriptionService_Import_ReportsOverlapsFoundInDatabase(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+
+	ndjson := `{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba","service_name":"Okko","price":300,"start_date":"2025-09"}
+`
+
+	existingID := uuid.New()
+	mockRepo.On("ImportSubscriptions", mock.Anything).Return(map[int]uuid.UUID{1: existingID}, nil)
+
+	report, err := service.Import(context.Background(), NewNDJSONSource(strings.NewReader(ndjson)), false)
+
+	require.NoError(t, err)
+	assert.False(t, report.Committed)
+	assert.Zero(t, report.ValidRows)
+	require.Len(t, report.Errors, 1)
+	var overlapErr *OverlapError
+	require.ErrorAs(t, report.Errors[0].Err, &overlapErr)
+	assert.Equal(t, existingID, overlapErr.ExistingID)
+}
+
+func TestSubscriptionService_Import_CommitsValidFile(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+
+	ndjson := `{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba","service_name":"Yandex Plus","price":400,"start_date":"07-2025"}
+{"user_id":"60601fee-2bf1-4721-ae6f-7636e79a0cba","service_name":"Okko","price":300,"start_date":"2025-09"}
+`
+
+	mockRepo.On("ImportSubscriptions", mock.Anything).Return(nil, nil)
+
+	report, err := service.Import(context.Background(), NewNDJSONSource(strings.NewReader(ndjson)), false)
+
+	require.NoError(t, err)
+	assert.True(t, report.Committed)
+	assert.Equal(t, int64(2), report.Imported)
+	assert.Empty(t, report.Errors)
+}
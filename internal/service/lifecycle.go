@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"effective-mobile-task/internal/models"
+	"effective-mobile-task/internal/repository/postgres"
+	"github.com/google/uuid"
+)
+
+var ErrInvalidTransition = errors.New("недопустимый переход состояния подписки")
+
+// LifecycleAction — действие, переводящее подписку в другое состояние.
+type LifecycleAction string
+
+const (
+	ActionPause  LifecycleAction = "pause"
+	ActionResume LifecycleAction = "resume"
+	ActionCancel LifecycleAction = "cancel"
+)
+
+type transition struct {
+	from []models.Status
+	to   models.Status
+}
+
+// lifecycle — допустимые переходы между состояниями подписки. В expired
+// подписка переходит сама, когда заканчивается месяц её окончания, и выйти
+// из него, как и из cancelled, нельзя.
+var lifecycle = map[LifecycleAction]transition{
+	ActionPause:  {from: []models.Status{models.StatusActive}, to: models.StatusPaused},
+	ActionResume: {from: []models.Status{models.StatusPaused}, to: models.StatusActive},
+	ActionCancel: {from: []models.Status{models.StatusActive, models.StatusPaused}, to: models.StatusCancelled},
+}
+
+// TransitionError — действие недопустимо в текущем состоянии подписки.
+type TransitionError struct {
+	Action LifecycleAction
+	From   models.Status
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("действие %s недопустимо для подписки в состоянии %s", e.Action, e.From)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return ErrInvalidTransition
+}
+
+// StatusChangeDTO — параметры перехода. Month — месяц, с которого действует
+// переход; по умолчанию текущий. Для отмены это последний оплачиваемый месяц.
+type StatusChangeDTO struct {
+	Month *models.Month `json:"month,omitempty" swaggertype:"string" example:"08-2025"`
+}
+
+// ChangeStatus выполняет действие жизненного цикла над подпиской и
+// возвращает подписку в новом состоянии.
+func (s *SubscriptionService) ChangeStatus(ctx context.Context, id uuid.UUID, action LifecycleAction, dto StatusChangeDTO) (*models.Subscription, error) {
+	t, ok := lifecycle[action]
+	if !ok {
+		return nil, fmt.Errorf("неизвестное действие %q", action)
+	}
+
+	sub, err := s.authorizedSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(t.from, sub.Status) {
+		return nil, &TransitionError{Action: action, From: sub.Status}
+	}
+
+	now := s.now()
+	month := models.MonthOf(now)
+	if dto.Month != nil {
+		month = *dto.Month
+	}
+
+	var endDate *models.Month
+	if action == ActionCancel {
+		if err := validatePeriod(sub.StartDate, &month); err != nil {
+			return nil, err
+		}
+		endDate = &month
+		if sub.EndDate != nil && sub.EndDate.Before(month) {
+			endDate = sub.EndDate
+		}
+	}
+
+	change := models.StatusChange{
+		ID:             uuid.New(),
+		SubscriptionID: sub.ID,
+		FromStatus:     sub.Status,
+		ToStatus:       t.to,
+		EffectiveMonth: month,
+		ChangedAt:      now,
+	}
+
+	err = s.repo.ChangeStatus(ctx, postgres.StatusTransition{Change: change, UserID: sub.UserID, EndDate: endDate})
+	if err != nil {
+		if errors.Is(err, postgres.ErrStatusChanged) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("не удалось изменить состояние подписки: %w", err)
+	}
+
+	sub.Status = t.to
+	sub.StatusChangedAt = &now
+	if endDate != nil {
+		sub.EndDate = endDate
+	}
+
+	return sub, nil
+}
+
+// StatusHistory возвращает историю переходов подписки.
+func (s *SubscriptionService) StatusHistory(ctx context.Context, id uuid.UUID) ([]models.StatusChange, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.StatusHistory(ctx, id)
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"effective-mobile-task/internal/models"
+	"effective-mobile-task/internal/repository/postgres"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var lifecycleNow = time.Date(2025, time.September, 15, 12, 0, 0, 0, time.UTC)
+
+func newLifecycleSubscription(status models.Status) *models.Subscription {
+	return &models.Subscription{
+		ID:          uuid.New(),
+		UserID:      uuid.New(),
+		ServiceName: "Test Service",
+		Price:       100,
+		StartDate:   models.NewMonth(2025, time.January),
+		Status:      status,
+	}
+}
+
+func TestSubscriptionService_ChangeStatus_Pause(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo, WithClock(func() time.Time { return lifecycleNow }))
+
+	sub := newLifecycleSubscription(models.StatusActive)
+	mockRepo.On("GetByID", mock.Anything, sub.ID).Return(sub, nil)
+	mockRepo.On("ChangeStatus", mock.Anything, mock.MatchedBy(func(tr postgres.StatusTransition) bool {
+		return tr.Change.FromStatus == models.StatusActive &&
+			tr.Change.ToStatus == models.StatusPaused &&
+			tr.Change.EffectiveMonth == models.NewMonth(2025, time.September) &&
+			tr.EndDate == nil
+	})).Return(nil)
+
+	updated, err := service.ChangeStatus(context.Background(), sub.ID, ActionPause, StatusChangeDTO{})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusPaused, updated.Status)
+	assert.Equal(t, lifecycleNow, *updated.StatusChangedAt)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSubscriptionService_ChangeStatus_InvalidTransition(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+
+	sub := newLifecycleSubscription(models.StatusExpired)
+	mockRepo.On("GetByID", mock.Anything, sub.ID).Return(sub, nil)
+
+	_, err := service.ChangeStatus(context.Background(), sub.ID, ActionResume, StatusChangeDTO{})
+
+	var transitionErr *TransitionError
+	require.ErrorAs(t, err, &transitionErr)
+	assert.Equal(t, models.StatusExpired, transitionErr.From)
+	assert.ErrorIs(t, err, ErrInvalidTransition)
+	mockRepo.AssertNotCalled(t, "ChangeStatus", mock.Anything, mock.Anything)
+}
+
+func TestSubscriptionService_ChangeStatus_CancelSetsEndDate(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo, WithClock(func() time.Time { return lifecycleNow }))
+
+	sub := newLifecycleSubscription(models.StatusPaused)
+	month := models.NewMonth(2025, time.October)
+	mockRepo.On("GetByID", mock.Anything, sub.ID).Return(sub, nil)
+	mockRepo.On("ChangeStatus", mock.Anything, mock.MatchedBy(func(tr postgres.StatusTransition) bool {
+		return tr.Change.ToStatus == models.StatusCancelled && tr.EndDate != nil && *tr.EndDate == month
+	})).Return(nil)
+
+	updated, err := service.ChangeStatus(context.Background(), sub.ID, ActionCancel, StatusChangeDTO{Month: &month})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusCancelled, updated.Status)
+	assert.Equal(t, month, *updated.EndDate)
+}
+
+func TestSubscriptionService_ChangeStatus_CancelBeforeStart(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+
+	sub := newLifecycleSubscription(models.StatusActive)
+	month := models.NewMonth(2024, time.December)
+	mockRepo.On("GetByID", mock.Anything, sub.ID).Return(sub, nil)
+
+	_, err := service.ChangeStatus(context.Background(), sub.ID, ActionCancel, StatusChangeDTO{Month: &month})
+
+	assert.ErrorIs(t, err, ErrInvalidSubscription)
+	mockRepo.AssertNotCalled(t, "ChangeStatus", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"effective-mobile-task/internal/models"
+	"github.com/google/uuid"
+)
+
+// MemberDTO — доля участника общей подписки: либо вес, либо фиксированная
+// сумма в месяц.
+type MemberDTO struct {
+	ShareWeight *int `json:"share_weight,omitempty" validate:"omitempty,gt=0" example:"1"`
+	ShareAmount *int `json:"share_amount,omitempty" validate:"omitempty,gt=0"`
+}
+
+// SetMember добавляет пользователя в участники подписки или меняет его долю.
+// Владельцу подписки можно задать только вес.
+func (s *SubscriptionService) SetMember(ctx context.Context, id, userID uuid.UUID, dto MemberDTO) (*models.SubscriptionMember, error) {
+	sub, err := s.authorizedSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if (dto.ShareWeight == nil) == (dto.ShareAmount == nil) || (userID == sub.UserID && dto.ShareAmount != nil) {
+		return nil, &ValidationError{Field: "share_weight", Rule: RuleMemberShare}
+	}
+	if (dto.ShareWeight != nil && *dto.ShareWeight <= 0) || (dto.ShareAmount != nil && *dto.ShareAmount <= 0) {
+		return nil, &ValidationError{Field: "share_weight", Rule: RulePricePositive}
+	}
+
+	m := &models.SubscriptionMember{
+		SubscriptionID: id,
+		UserID:         userID,
+		ShareWeight:    dto.ShareWeight,
+		ShareAmount:    dto.ShareAmount,
+	}
+	if err := s.repo.SetMember(ctx, m); err != nil {
+		return nil, fmt.Errorf("не удалось сохранить участника подписки: %w", err)
+	}
+
+	return m, nil
+}
+
+// ListMembers возвращает участников подписки.
+func (s *SubscriptionService) ListMembers(ctx context.Context, id uuid.UUID) ([]models.SubscriptionMember, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListMembers(ctx, id)
+}
+
+// RemoveMember исключает пользователя из участников подписки.
+func (s *SubscriptionService) RemoveMember(ctx context.Context, id, userID uuid.UUID) error {
+	if _, err := s.authorizedSubscription(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.RemoveMember(ctx, id, userID)
+}
+
+// Settlements показывает, кто кому сколько должен за месяц по общим
+// подпискам. Встречные долги двух пользователей взаимно зачитываются.
+// Без month берётся текущий месяц, с userID — только долги пользователя и
+// долги ему.
+func (s *SubscriptionService) Settlements(ctx context.Context, month *models.Month, userID *uuid.UUID) ([]models.Settlement, error) {
+	m := models.MonthOf(s.now())
+	if month != nil {
+		m = *month
+	}
+
+	shares, err := s.repo.MemberShares(ctx, m, userID)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось посчитать доли участников: %w", err)
+	}
+
+	return settle(shares), nil
+}
+
+// settle сводит доли участников в долги между парами пользователей.
+func settle(shares []models.MemberShare) []models.Settlement {
+	type pair struct{ a, b uuid.UUID }
+
+	index := make(map[pair]int)
+	settlements := make([]models.Settlement, 0)
+	for _, share := range shares {
+		debtor, creditor := share.UserID, share.PayerID
+		item := models.SettlementItem{SubscriptionID: share.SubscriptionID, ServiceName: share.ServiceName, Amount: share.Amount}
+
+		i, ok := index[pair{debtor, creditor}]
+		if !ok {
+			if j, reverse := index[pair{creditor, debtor}]; reverse {
+				i, ok = j, true
+				item.Amount = -item.Amount
+			}
+		}
+		if !ok {
+			i = len(settlements)
+			index[pair{debtor, creditor}] = i
+			settlements = append(settlements, models.Settlement{From: debtor, To: creditor})
+		}
+
+		settlements[i].Amount += item.Amount
+		settlements[i].Items = append(settlements[i].Items, item)
+	}
+
+	result := settlements[:0]
+	for _, st := range settlements {
+		switch {
+		case st.Amount == 0:
+			continue
+		case st.Amount < 0:
+			st.From, st.To, st.Amount = st.To, st.From, -st.Amount
+			for i := range st.Items {
+				st.Items[i].Amount = -st.Items[i].Amount
+			}
+		}
+		result = append(result, st)
+	}
+
+	return result
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"effective-mobile-task/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptionService_SetMember(t *testing.T) {
+	weight, amount := 2, 150
+	owner := uuid.New()
+
+	tests := []struct {
+		name    string
+		userID  uuid.UUID
+		dto     MemberDTO
+		wantErr bool
+	}{
+		{name: "weight", userID: uuid.New(), dto: MemberDTO{ShareWeight: &weight}},
+		{name: "fixed amount", userID: uuid.New(), dto: MemberDTO{ShareAmount: &amount}},
+		{name: "owner weight", userID: owner, dto: MemberDTO{ShareWeight: &weight}},
+		{name: "neither", userID: uuid.New(), dto: MemberDTO{}, wantErr: true},
+		{name: "both", userID: uuid.New(), dto: MemberDTO{ShareWeight: &weight, ShareAmount: &amount}, wantErr: true},
+		{name: "owner fixed amount", userID: owner, dto: MemberDTO{ShareAmount: &amount}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			service := NewSubscriptionService(mockRepo)
+
+			sub := &models.Subscription{ID: uuid.New(), UserID: owner, Price: 600, StartDate: models.NewMonth(2025, time.January)}
+			mockRepo.On("GetByID", mock.Anything, sub.ID).Return(sub, nil)
+			mockRepo.On("SetMember", mock.Anything, mock.AnythingOfType("*models.SubscriptionMember")).Return(nil)
+
+			_, err := service.SetMember(context.Background(), sub.ID, tt.userID, tt.dto)
+
+			if tt.wantErr {
+				var validationErr *ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, RuleMemberShare, validationErr.Rule)
+				mockRepo.AssertNotCalled(t, "SetMember", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			mockRepo.AssertCalled(t, "SetMember", mock.Anything, mock.AnythingOfType("*models.SubscriptionMember"))
+		})
+	}
+}
+
+func TestSubscriptionService_Settlements(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo, WithClock(func() time.Time {
+		return time.Date(2025, time.July, 5, 0, 0, 0, 0, time.UTC)
+	}))
+
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+	family, music, video := uuid.New(), uuid.New(), uuid.New()
+
+	mockRepo.On("MemberShares", mock.Anything, models.NewMonth(2025, time.July), (*uuid.UUID)(nil)).
+		Return([]models.MemberShare{
+			{SubscriptionID: family, ServiceName: "Family", PayerID: alice, UserID: bob, Amount: 300},
+			{SubscriptionID: music, ServiceName: "Music", PayerID: bob, UserID: alice, Amount: 100},
+			{SubscriptionID: video, ServiceName: "Video", PayerID: carol, UserID: alice, Amount: 200},
+			{SubscriptionID: video, ServiceName: "Video", PayerID: alice, UserID: carol, Amount: 200},
+		}, nil)
+
+	got, err := service.Settlements(context.Background(), nil, nil)
+
+	require.NoError(t, err)
+	require.Len(t, got, 1, "встречные долги Алисы и Кэрол взаимно зачитываются")
+	assert.Equal(t, bob, got[0].From)
+	assert.Equal(t, alice, got[0].To)
+	assert.Equal(t, 200, got[0].Amount)
+	assert.Equal(t, []models.SettlementItem{
+		{SubscriptionID: family, ServiceName: "Family", Amount: 300},
+		{SubscriptionID: music, ServiceName: "Music", Amount: -100},
+	}, got[0].Items)
+}
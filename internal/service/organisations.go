@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"effective-mobile-task/internal/models"
+	"effective-mobile-task/internal/repository/postgres"
+	"github.com/google/uuid"
+)
+
+type OrganisationRepository interface {
+	CreateOrganisation(ctx context.Context, o *models.Organisation) error
+	GetOrganisation(ctx context.Context, id uuid.UUID) (*models.Organisation, error)
+	ListOrganisations(ctx context.Context) ([]models.Organisation, error)
+	DeleteOrganisation(ctx context.Context, id uuid.UUID) error
+	CreateCostCentre(ctx context.Context, c *models.CostCentre) error
+	GetCostCentre(ctx context.Context, orgID, id uuid.UUID) (*models.CostCentre, error)
+	ListCostCentres(ctx context.Context, orgID uuid.UUID) ([]models.CostCentre, error)
+	DeleteCostCentre(ctx context.Context, orgID, id uuid.UUID) error
+	AddUser(ctx context.Context, u *models.OrganisationUser) error
+	ListUsers(ctx context.Context, orgID uuid.UUID) ([]models.OrganisationUser, error)
+	RemoveUser(ctx context.Context, orgID, userID uuid.UUID) error
+}
+
+// ChargebackRepository считает расходы на подписки по структуре организации.
+type ChargebackRepository interface {
+	Chargeback(ctx context.Context, orgID uuid.UUID, filter postgres.GetSummaryFilter) ([]models.ChargebackRow, error)
+}
+
+// OrganisationService ведёт организации, их подразделения и центры затрат
+// и распределяет между ними расходы на подписки. Менять организации, их
+// структуру и состав может только администратор: состав организации
+// определяет, чьи подписки может менять менеджер.
+type OrganisationService struct {
+	repo   OrganisationRepository
+	costs  ChargebackRepository
+	policy Authorizer
+	now    func() time.Time
+}
+
+func NewOrganisationService(repo OrganisationRepository, costs ChargebackRepository, policy Authorizer) *OrganisationService {
+	return &OrganisationService{repo: repo, costs: costs, policy: policy, now: time.Now}
+}
+
+type OrganisationDTO struct {
+	Name string `json:"name" validate:"required,min=2,max=200" example:"Acme"`
+}
+
+// CostCentreDTO — узел структуры организации. Родителем может быть только
+// подразделение той же организации.
+type CostCentreDTO struct {
+	ParentID *uuid.UUID            `json:"parent_id,omitempty"`
+	Kind     models.CostCentreKind `json:"kind" validate:"required,oneof=department cost_centre" enums:"department,cost_centre"`
+	Code     string                `json:"code" validate:"required,max=32" example:"ENG-PLT"`
+	Name     string                `json:"name" validate:"required,min=2,max=200" example:"Platform"`
+}
+
+// CostCentreAssignmentDTO — центр затрат подписки. null снимает подписку с
+// центра затрат.
+type CostCentreAssignmentDTO struct {
+	CostCentreID *uuid.UUID `json:"cost_centre_id"`
+}
+
+func (s *OrganisationService) Create(ctx context.Context, dto OrganisationDTO) (*models.Organisation, error) {
+	if err := authorize(ctx, s.policy, ActionAdminister, nil); err != nil {
+		return nil, err
+	}
+
+	o := &models.Organisation{ID: uuid.New(), Name: strings.Join(strings.Fields(dto.Name), " ")}
+	if o.Name == "" {
+		return nil, &ValidationError{Field: "name", Rule: RuleRequired}
+	}
+
+	if err := s.repo.CreateOrganisation(ctx, o); err != nil {
+		return nil, fmt.Errorf("не удалось создать организацию: %w", err)
+	}
+
+	return o, nil
+}
+
+func (s *OrganisationService) GetByID(ctx context.Context, id uuid.UUID) (*models.Organisation, error) {
+	return s.repo.GetOrganisation(ctx, id)
+}
+
+func (s *OrganisationService) List(ctx context.Context) ([]models.Organisation, error) {
+	return s.repo.ListOrganisations(ctx)
+}
+
+func (s *OrganisationService) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := authorize(ctx, s.policy, ActionAdminister, nil); err != nil {
+		return err
+	}
+	return s.repo.DeleteOrganisation(ctx, id)
+}
+
+// CreateCostCentre добавляет узел в структуру организации orgID.
+func (s *OrganisationService) CreateCostCentre(ctx context.Context, orgID uuid.UUID, dto CostCentreDTO) (*models.CostCentre, error) {
+	if err := authorize(ctx, s.policy, ActionAdminister, nil); err != nil {
+		return nil, err
+	}
+
+	c := &models.CostCentre{
+		ID:             uuid.New(),
+		OrganisationID: orgID,
+		ParentID:       dto.ParentID,
+		Kind:           dto.Kind,
+		Code:           strings.TrimSpace(dto.Code),
+		Name:           strings.Join(strings.Fields(dto.Name), " "),
+	}
+	if c.Code == "" {
+		return nil, &ValidationError{Field: "code", Rule: RuleRequired}
+	}
+	if c.Name == "" {
+		return nil, &ValidationError{Field: "name", Rule: RuleRequired}
+	}
+
+	if _, err := s.repo.GetOrganisation(ctx, orgID); err != nil {
+		return nil, err
+	}
+	if c.ParentID != nil {
+		parent, err := s.repo.GetCostCentre(ctx, orgID, *c.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.Kind != models.CostCentreDepartment {
+			return nil, &ValidationError{Field: "parent_id", Rule: RuleCostCentreParent}
+		}
+	}
+
+	if err := s.repo.CreateCostCentre(ctx, c); err != nil {
+		return nil, fmt.Errorf("не удалось создать центр затрат: %w", err)
+	}
+
+	return c, nil
+}
+
+func (s *OrganisationService) ListCostCentres(ctx context.Context, orgID uuid.UUID) ([]models.CostCentre, error) {
+	if _, err := s.repo.GetOrganisation(ctx, orgID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListCostCentres(ctx, orgID)
+}
+
+func (s *OrganisationService) DeleteCostCentre(ctx context.Context, orgID, id uuid.UUID) error {
+	if err := authorize(ctx, s.policy, ActionAdminister, nil); err != nil {
+		return err
+	}
+	return s.repo.DeleteCostCentre(ctx, orgID, id)
+}
+
+// AddUser включает пользователя в организацию orgID. Менеджер организации
+// может менять подписки её пользователей.
+func (s *OrganisationService) AddUser(ctx context.Context, orgID, userID uuid.UUID) (*models.OrganisationUser, error) {
+	if err := authorize(ctx, s.policy, ActionAdminister, nil); err != nil {
+		return nil, err
+	}
+
+	u := &models.OrganisationUser{OrganisationID: orgID, UserID: userID}
+	if err := s.repo.AddUser(ctx, u); err != nil {
+		return nil, fmt.Errorf("не удалось добавить пользователя в организацию: %w", err)
+	}
+
+	return u, nil
+}
+
+func (s *OrganisationService) ListUsers(ctx context.Context, orgID uuid.UUID) ([]models.OrganisationUser, error) {
+	if _, err := s.repo.GetOrganisation(ctx, orgID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListUsers(ctx, orgID)
+}
+
+func (s *OrganisationService) RemoveUser(ctx context.Context, orgID, userID uuid.UUID) error {
+	if err := authorize(ctx, s.policy, ActionAdminister, nil); err != nil {
+		return err
+	}
+	return s.repo.RemoveUser(ctx, orgID, userID)
+}
+
+// Chargeback распределяет расходы на подписки по узлам структуры
+// организации за каждый месяц периода фильтра. Без начала период
+// начинается с текущего месяца, без конца — заканчивается текущим месяцем
+// или месяцем начала, если тот позже.
+func (s *OrganisationService) Chargeback(ctx context.Context, orgID uuid.UUID, filter postgres.GetSummaryFilter) ([]models.ChargebackRow, error) {
+	current := models.MonthOf(s.now())
+	if filter.StartDate == nil {
+		filter.StartDate = &current
+	}
+	if filter.EndDate == nil {
+		end := current
+		if filter.StartDate.After(end) {
+			end = *filter.StartDate
+		}
+		filter.EndDate = &end
+	}
+	if filter.EndDate.Before(*filter.StartDate) {
+		return nil, &ValidationError{Field: "end_date", Rule: RuleEndBeforeStart}
+	}
+
+	if _, err := s.repo.GetOrganisation(ctx, orgID); err != nil {
+		return nil, err
+	}
+
+	report, err := s.costs.Chargeback(ctx, orgID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось распределить расходы по центрам затрат: %w", err)
+	}
+
+	return report, nil
+}
+
+// SetCostCentre относит подписку на центр затрат или снимает её с него.
+func (s *SubscriptionService) SetCostCentre(ctx context.Context, id uuid.UUID, dto CostCentreAssignmentDTO) (*models.Subscription, error) {
+	if _, err := s.authorizedSubscription(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetCostCentre(ctx, id, dto.CostCentreID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
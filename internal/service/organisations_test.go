@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"effective-mobile-task/internal/models"
+	"effective-mobile-task/internal/repository/postgres"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockOrganisationRepository struct {
+	mock.Mock
+}
+
+func (m *MockOrganisationRepository) CreateOrganisation(ctx context.Context, o *models.Organisation) error {
+	args := m.Called(ctx, o)
+	return args.Error(0)
+}
+
+func (m *MockOrganisationRepository) GetOrganisation(ctx context.Context, id uuid.UUID) (*models.Organisation, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Organisation), args.Error(1)
+}
+
+func (m *MockOrganisationRepository) ListOrganisations(ctx context.Context) ([]models.Organisation, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Organisation), args.Error(1)
+}
+
+func (m *MockOrganisationRepository) DeleteOrganisation(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockOrganisationRepository) CreateCostCentre(ctx context.Context, c *models.CostCentre) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockOrganisationRepository) GetCostCentre(ctx context.Context, orgID, id uuid.UUID) (*models.CostCentre, error) {
+	args := m.Called(ctx, orgID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CostCentre), args.Error(1)
+}
+
+func (m *MockOrganisationRepository) ListCostCentres(ctx context.Context, orgID uuid.UUID) ([]models.CostCentre, error) {
+	args := m.Called(ctx, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.CostCentre), args.Error(1)
+}
+
+func (m *MockOrganisationRepository) DeleteCostCentre(ctx context.Context, orgID, id uuid.UUID) error {
+	args := m.Called(ctx, orgID, id)
+	return args.Error(0)
+}
+
+func (m *MockOrganisationRepository) AddUser(ctx context.Context, u *models.OrganisationUser) error {
+	args := m.Called(ctx, u)
+	return args.Error(0)
+}
+
+func (m *MockOrganisationRepository) ListUsers(ctx context.Context, orgID uuid.UUID) ([]models.OrganisationUser, error) {
+	args := m.Called(ctx, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.OrganisationUser), args.Error(1)
+}
+
+func (m *MockOrganisationRepository) RemoveUser(ctx context.Context, orgID, userID uuid.UUID) error {
+	args := m.Called(ctx, orgID, userID)
+	return args.Error(0)
+}
+
+func TestOrganisationService_CreateCostCentre(t *testing.T) {
+	mockOrgs := new(MockOrganisationRepository)
+	service := NewOrganisationService(mockOrgs, new(MockRepository), nil)
+
+	org := &models.Organisation{ID: uuid.New(), Name: "Acme"}
+	parent := &models.CostCentre{ID: uuid.New(), OrganisationID: org.ID, Kind: models.CostCentreDepartment, Code: "ENG"}
+	mockOrgs.On("GetOrganisation", mock.Anything, org.ID).Return(org, nil)
+	mockOrgs.On("GetCostCentre", mock.Anything, org.ID, parent.ID).Return(parent, nil)
+	mockOrgs.On("CreateCostCentre", mock.Anything, mock.AnythingOfType("*models.CostCentre")).Return(nil)
+
+	got, err := service.CreateCostCentre(context.Background(), org.ID, CostCentreDTO{
+		ParentID: &parent.ID,
+		Kind:     models.CostCentreCostCentre,
+		Code:     " ENG-PLT ",
+		Name:     "  Platform   team ",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, org.ID, got.OrganisationID)
+	assert.Equal(t, "ENG-PLT", got.Code)
+	assert.Equal(t, "Platform team", got.Name)
+	mockOrgs.AssertExpectations(t)
+}
+
+func TestOrganisationService_CreateCostCentre_ParentNotDepartment(t *testing.T) {
+	mockOrgs := new(MockOrganisationRepository)
+	service := NewOrganisationService(mockOrgs, new(MockRepository), nil)
+
+	orgID := uuid.New()
+	parent := &models.CostCentre{ID: uuid.New(), OrganisationID: orgID, Kind: models.CostCentreCostCentre, Code: "ENG-PLT"}
+	mockOrgs.On("GetOrganisation", mock.Anything, orgID).Return(&models.Organisation{ID: orgID}, nil)
+	mockOrgs.On("GetCostCentre", mock.Anything, orgID, parent.ID).Return(parent, nil)
+
+	_, err := service.CreateCostCentre(context.Background(), orgID, CostCentreDTO{
+		ParentID: &parent.ID,
+		Kind:     models.CostCentreCostCentre,
+		Code:     "ENG-PLT-1",
+		Name:     "Platform on-call",
+	})
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, RuleCostCentreParent, validationErr.Rule)
+	mockOrgs.AssertNotCalled(t, "CreateCostCentre", mock.Anything, mock.Anything)
+}
+
+func TestOrganisationService_CreateCostCentre_ParentOfOtherOrganisation(t *testing.T) {
+	mockOrgs := new(MockOrganisationRepository)
+	service := NewOrganisationService(mockOrgs, new(MockRepository), nil)
+
+	orgID, parentID := uuid.New(), uuid.New()
+	mockOrgs.On("GetOrganisation", mock.Anything, orgID).Return(&models.Organisation{ID: orgID}, nil)
+	mockOrgs.On("GetCostCentre", mock.Anything, orgID, parentID).Return(nil, postgres.ErrCostCentreNotFound)
+
+	_, err := service.CreateCostCentre(context.Background(), orgID, CostCentreDTO{
+		ParentID: &parentID,
+		Kind:     models.CostCentreDepartment,
+		Code:     "OPS",
+		Name:     "Operations",
+	})
+
+	assert.ErrorIs(t, err, postgres.ErrCostCentreNotFound)
+	mockOrgs.AssertNotCalled(t, "CreateCostCentre", mock.Anything, mock.Anything)
+}
+
+func TestOrganisationService_Chargeback_DefaultPeriod(t *testing.T) {
+	mockOrgs := new(MockOrganisationRepository)
+	mockCosts := new(MockRepository)
+	service := NewOrganisationService(mockOrgs, mockCosts, nil)
+	service.now = func() time.Time { return time.Date(2025, time.July, 20, 12, 0, 0, 0, time.UTC) }
+
+	orgID := uuid.New()
+	july := models.NewMonth(2025, time.July)
+	report := []models.ChargebackRow{{Month: july, Code: "ENG", Direct: 0, Total: 1500}}
+	mockOrgs.On("GetOrganisation", mock.Anything, orgID).Return(&models.Organisation{ID: orgID}, nil)
+	mockCosts.On("Chargeback", mock.Anything, orgID, postgres.GetSummaryFilter{StartDate: &july, EndDate: &july}).Return(report, nil)
+
+	got, err := service.Chargeback(context.Background(), orgID, postgres.GetSummaryFilter{})
+
+	require.NoError(t, err)
+	assert.Equal(t, report, got)
+	mockCosts.AssertExpectations(t)
+}
+
+func TestOrganisationService_Chargeback_EndBeforeStart(t *testing.T) {
+	mockOrgs := new(MockOrganisationRepository)
+	mockCosts := new(MockRepository)
+	service := NewOrganisationService(mockOrgs, mockCosts, nil)
+
+	start := models.NewMonth(2025, time.July)
+	end := models.NewMonth(2025, time.June)
+
+	_, err := service.Chargeback(context.Background(), uuid.New(), postgres.GetSummaryFilter{StartDate: &start, EndDate: &end})
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, RuleEndBeforeStart, validationErr.Rule)
+	mockCosts.AssertNotCalled(t, "Chargeback", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSubscriptionService_SetCostCentre(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+
+	id, centreID := uuid.New(), uuid.New()
+	mockRepo.On("SetCostCentre", mock.Anything, id, &centreID).Return(nil)
+	mockRepo.On("GetByID", mock.Anything, id).Return(&models.Subscription{ID: id, CostCentreID: &centreID}, nil)
+
+	got, err := service.SetCostCentre(context.Background(), id, CostCentreAssignmentDTO{CostCentreID: &centreID})
+
+	require.NoError(t, err)
+	assert.Equal(t, &centreID, got.CostCentreID)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"effective-mobile-task/internal/auth"
+	"github.com/google/uuid"
+)
+
+// ErrForbidden означает, что роли не хватает прав на действие.
+var ErrForbidden = errors.New("недостаточно прав")
+
+// Action — действие, права на которое проверяет политика.
+type Action string
+
+const (
+	ActionSummary Action = "summary"
+	ActionCreate  Action = "create"
+	ActionUpdate  Action = "update"
+	ActionDelete  Action = "delete"
+	// ActionAdminister — действия, которые не относятся к подпискам одного
+	// пользователя: удаление всех данных пользователя, изменения каталога
+	// сервисов, организаций, их структуры и состава.
+	ActionAdminister Action = "administer"
+)
+
+// ForbiddenError — роли Role не разрешено действие Action.
+type ForbiddenError struct {
+	Action Action
+	Role   auth.Role
+}
+
+func (e *ForbiddenError) Error() string {
+	return fmt.Sprintf("роли %q не разрешено действие %s", e.Role, e.Action)
+}
+
+func (e *ForbiddenError) Unwrap() error {
+	return ErrForbidden
+}
+
+// Authorizer решает, разрешено ли действие тому, от чьего имени выполняется
+// запрос. ownerID — пользователь, которому принадлежат затронутые подписки,
+// или nil, если действие касается подписок всех пользователей.
+type Authorizer interface {
+	Authorize(ctx context.Context, action Action, ownerID *uuid.UUID) error
+}
+
+// OrganisationMembership знает, кто в каких организациях состоит.
+type OrganisationMembership interface {
+	SharesOrganisation(ctx context.Context, a, b uuid.UUID) (bool, error)
+}
+
+// Policy — права ролей на действия с подписками:
+//   - администратор может всё, в том числе удалять подписки, данные
+//     пользователей, менять каталог и организации;
+//   - менеджер строит сводки и меняет свои подписки и бюджеты и подписки и
+//     бюджеты пользователей организаций, в которых состоит сам;
+//   - наблюдатель только читает данные и строит сводки.
+//
+// Запрос, для которого не известно, от чьего имени он выполняется
+// (фоновые задачи, внутренние вызовы), политика не ограничивает.
+type Policy struct {
+	members OrganisationMembership
+}
+
+func NewPolicy(members OrganisationMembership) *Policy {
+	return &Policy{members: members}
+}
+
+func (p *Policy) Authorize(ctx context.Context, action Action, ownerID *uuid.UUID) error {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	switch principal.Role {
+	case auth.RoleAdmin:
+		return nil
+	case auth.RoleManager:
+		switch action {
+		case ActionSummary:
+			return nil
+		case ActionCreate, ActionUpdate:
+			allowed, err := p.manages(ctx, principal, ownerID)
+			if err != nil {
+				return err
+			}
+			if allowed {
+				return nil
+			}
+		}
+	case auth.RoleViewer:
+		if action == ActionSummary {
+			return nil
+		}
+	}
+
+	return &ForbiddenError{Action: action, Role: principal.Role}
+}
+
+// manages сообщает, может ли менеджер менять подписки пользователя ownerID.
+func (p *Policy) manages(ctx context.Context, principal auth.Principal, ownerID *uuid.UUID) (bool, error) {
+	if principal.UserID == nil || ownerID == nil {
+		return false, nil
+	}
+	if *principal.UserID == *ownerID {
+		return true, nil
+	}
+
+	shares, err := p.members.SharesOrganisation(ctx, *principal.UserID, *ownerID)
+	if err != nil {
+		return false, fmt.Errorf("не удалось проверить права менеджера: %w", err)
+	}
+	return shares, nil
+}
+
+// authorize спрашивает policy, разрешено ли действие. Без политики
+// разрешено всё.
+func authorize(ctx context.Context, policy Authorizer, action Action, ownerID *uuid.UUID) error {
+	if policy == nil {
+		return nil
+	}
+	return policy.Authorize(ctx, action, ownerID)
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"effective-mobile-task/internal/auth"
+	"effective-mobile-task/internal/models"
+	"effective-mobile-task/internal/repository/postgres"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMembership считает, что в одной организации состоят пары из shared.
+type fakeMembership struct {
+	shared map[[2]uuid.UUID]bool
+}
+
+func (f *fakeMembership) SharesOrganisation(ctx context.Context, a, b uuid.UUID) (bool, error) {
+	return f.shared[[2]uuid.UUID{a, b}], nil
+}
+
+func asPrincipal(role auth.Role, userID *uuid.UUID) context.Context {
+	return auth.WithPrincipal(context.Background(), auth.Principal{Role: role, UserID: userID})
+}
+
+func TestPolicy_Authorize(t *testing.T) {
+	manager, colleague, stranger := uuid.New(), uuid.New(), uuid.New()
+	policy := NewPolicy(&fakeMembership{shared: map[[2]uuid.UUID]bool{{manager, colleague}: true}})
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		action  Action
+		owner   *uuid.UUID
+		allowed bool
+	}{
+		{name: "no principal", ctx: context.Background(), action: ActionDelete, allowed: true},
+		{name: "admin deletes", ctx: asPrincipal(auth.RoleAdmin, nil), action: ActionDelete, allowed: true},
+		{name: "viewer runs summary", ctx: asPrincipal(auth.RoleViewer, nil), action: ActionSummary, allowed: true},
+		{name: "viewer cannot create", ctx: asPrincipal(auth.RoleViewer, &stranger), action: ActionCreate, owner: &stranger},
+		{name: "manager edits own", ctx: asPrincipal(auth.RoleManager, &manager), action: ActionUpdate, owner: &manager, allowed: true},
+		{name: "manager edits colleague", ctx: asPrincipal(auth.RoleManager, &manager), action: ActionCreate, owner: &colleague, allowed: true},
+		{name: "manager cannot edit stranger", ctx: asPrincipal(auth.RoleManager, &manager), action: ActionUpdate, owner: &stranger},
+		{name: "manager without user", ctx: asPrincipal(auth.RoleManager, nil), action: ActionUpdate, owner: &colleague},
+		{name: "manager cannot delete", ctx: asPrincipal(auth.RoleManager, &manager), action: ActionDelete},
+		{name: "admin administers", ctx: asPrincipal(auth.RoleAdmin, nil), action: ActionAdminister, allowed: true},
+		{name: "manager cannot administer", ctx: asPrincipal(auth.RoleManager, &manager), action: ActionAdminister, owner: &manager},
+		{name: "viewer cannot administer", ctx: asPrincipal(auth.RoleViewer, &stranger), action: ActionAdminister},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Authorize(tt.ctx, tt.action, tt.owner)
+			if tt.allowed {
+				assert.NoError(t, err)
+				return
+			}
+			var forbidden *ForbiddenError
+			require.ErrorAs(t, err, &forbidden)
+			assert.Equal(t, tt.action, forbidden.Action)
+			assert.ErrorIs(t, err, ErrForbidden)
+		})
+	}
+}
+
+func TestSubscriptionService_Delete_Forbidden(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := NewSubscriptionService(mockRepo, WithPolicy(NewPolicy(&fakeMembership{})))
+
+	err := svc.Delete(asPrincipal(auth.RoleManager, nil), uuid.New())
+
+	assert.ErrorIs(t, err, ErrForbidden)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestSubscriptionService_Update_ChecksOwner(t *testing.T) {
+	manager, owner := uuid.New(), uuid.New()
+	mockRepo := new(MockRepository)
+	svc := NewSubscriptionService(mockRepo, WithPolicy(NewPolicy(&fakeMembership{})))
+
+	id := uuid.New()
+	mockRepo.On("GetByID", mock.Anything, id).Return(&models.Subscription{ID: id, UserID: owner, ServiceName: "Netflix", Price: 100, UnitPrice: 100, Quantity: 1, StartDate: models.MonthOf(time.Now())}, nil)
+
+	err := svc.Update(asPrincipal(auth.RoleManager, &manager), id, UpdateSubscriptionDTO{
+		ServiceName: "Netflix",
+		Price:       200,
+		StartDate:   models.MonthOf(time.Now()),
+	})
+
+	assert.ErrorIs(t, err, ErrForbidden)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestSubscriptionService_GetSummary_Viewer(t *testing.T) {
+	mockRepo := new(MockRepository)
+	svc := NewSubscriptionService(mockRepo, WithPolicy(NewPolicy(&fakeMembership{})))
+
+	mockRepo.On("GetSummary", mock.Anything, mock.Anything).Return(&models.SummaryTotals{Net: 100}, nil)
+
+	totals, err := svc.GetSummary(asPrincipal(auth.RoleViewer, nil), postgres.GetSummaryFilter{})
+
+	require.NoError(t, err)
+	assert.Equal(t, 100, totals.Net)
+}
+
+// forbiddenPrincipals — от чьего имени нельзя менять данные пользователя
+// owner: наблюдатель, даже если это сам owner, и менеджер не из его
+// организации.
+func forbiddenPrincipals(owner uuid.UUID) map[string]context.Context {
+	manager := uuid.New()
+	return map[string]context.Context{
+		"viewer":   asPrincipal(auth.RoleViewer, &owner),
+		"stranger": asPrincipal(auth.RoleManager, &manager),
+	}
+}
+
+func TestSubscriptionService_MutationsForbidden(t *testing.T) {
+	one := 1
+	month := models.MonthOf(time.Now())
+	mutations := map[string]func(svc *SubscriptionService, ctx context.Context, id uuid.UUID) error{
+		"ChangeStatus": func(svc *SubscriptionService, ctx context.Context, id uuid.UUID) error {
+			_, err := svc.ChangeStatus(ctx, id, ActionPause, StatusChangeDTO{})
+			return err
+		},
+		"SchedulePriceChange": func(svc *SubscriptionService, ctx context.Context, id uuid.UUID) error {
+			_, err := svc.SchedulePriceChange(ctx, id, PriceChangeDTO{EffectiveFrom: month, UnitPrice: 100})
+			return err
+		},
+		"ChangeSeats": func(svc *SubscriptionService, ctx context.Context, id uuid.UUID) error {
+			_, err := svc.ChangeSeats(ctx, id, SeatChangeDTO{EffectiveFrom: month, Quantity: 2})
+			return err
+		},
+		"AddDiscount": func(svc *SubscriptionService, ctx context.Context, id uuid.UUID) error {
+			_, err := svc.AddDiscount(ctx, id, DiscountDTO{Kind: models.DiscountPercent, Value: 10})
+			return err
+		},
+		"DeleteDiscount": func(svc *SubscriptionService, ctx context.Context, id uuid.UUID) error {
+			return svc.DeleteDiscount(ctx, id, uuid.New())
+		},
+		"SetMember": func(svc *SubscriptionService, ctx context.Context, id uuid.UUID) error {
+			_, err := svc.SetMember(ctx, id, uuid.New(), MemberDTO{ShareWeight: &one})
+			return err
+		},
+		"RemoveMember": func(svc *SubscriptionService, ctx context.Context, id uuid.UUID) error {
+			return svc.RemoveMember(ctx, id, uuid.New())
+		},
+		"SetCostCentre": func(svc *SubscriptionService, ctx context.Context, id uuid.UUID) error {
+			_, err := svc.SetCostCentre(ctx, id, CostCentreAssignmentDTO{})
+			return err
+		},
+		"AddTags": func(svc *SubscriptionService, ctx context.Context, id uuid.UUID) error {
+			_, err := svc.AddTags(ctx, id, TagsDTO{Tags: []string{"work"}})
+			return err
+		},
+		"RemoveTag": func(svc *SubscriptionService, ctx context.Context, id uuid.UUID) error {
+			_, err := svc.RemoveTag(ctx, id, "work")
+			return err
+		},
+	}
+
+	owner := uuid.New()
+	for name, mutate := range mutations {
+		for who, ctx := range forbiddenPrincipals(owner) {
+			t.Run(name+"/"+who, func(t *testing.T) {
+				// Мок без других ожиданий упадёт, если сервис дойдёт до
+				// изменения данных.
+				mockRepo := new(MockRepository)
+				svc := NewSubscriptionService(mockRepo, WithPolicy(NewPolicy(&fakeMembership{})))
+
+				id := uuid.New()
+				mockRepo.On("GetByID", mock.Anything, id).Return(&models.Subscription{ID: id, UserID: owner, Status: models.StatusActive, StartDate: month}, nil)
+
+				assert.ErrorIs(t, mutate(svc, ctx, id), ErrForbidden)
+				mockRepo.AssertExpectations(t)
+			})
+		}
+	}
+}
+
+func TestBudgetService_MutationsForbidden(t *testing.T) {
+	owner := uuid.New()
+	dto := BudgetDTO{Scope: models.BudgetOverall, Amount: 1000}
+
+	for who, ctx := range forbiddenPrincipals(owner) {
+		t.Run(who, func(t *testing.T) {
+			mockBudgets := new(MockBudgetRepository)
+			svc := NewBudgetService(mockBudgets, new(MockRepository), NewPolicy(&fakeMembership{}))
+
+			_, err := svc.Create(ctx, owner, dto)
+			assert.ErrorIs(t, err, ErrForbidden)
+			_, err = svc.Update(ctx, owner, uuid.New(), dto)
+			assert.ErrorIs(t, err, ErrForbidden)
+			assert.ErrorIs(t, svc.Delete(ctx, owner, uuid.New()), ErrForbidden)
+			mockBudgets.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAdministration_AdminOnly(t *testing.T) {
+	manager := uuid.New()
+	policy := NewPolicy(&fakeMembership{})
+	orgID := uuid.New()
+
+	principals := map[string]context.Context{
+		"viewer":  asPrincipal(auth.RoleViewer, &manager),
+		"manager": asPrincipal(auth.RoleManager, &manager),
+	}
+	for who, ctx := range principals {
+		t.Run(who, func(t *testing.T) {
+			mockServices := new(MockServiceRepository)
+			mockOrgs := new(MockOrganisationRepository)
+			mockUserData := new(MockUserDataRepository)
+			catalog := NewCatalogService(mockServices, policy)
+			organisations := NewOrganisationService(mockOrgs, new(MockRepository), policy)
+			userData := NewUserDataService(mockUserData, policy)
+
+			_, err := catalog.Create(ctx, ServiceDTO{Name: "Netflix"})
+			assert.ErrorIs(t, err, ErrForbidden)
+			_, err = catalog.Update(ctx, uuid.New(), ServiceDTO{Name: "Netflix"})
+			assert.ErrorIs(t, err, ErrForbidden)
+			assert.ErrorIs(t, catalog.Delete(ctx, uuid.New()), ErrForbidden)
+
+			_, err = organisations.Create(ctx, OrganisationDTO{Name: "Acme"})
+			assert.ErrorIs(t, err, ErrForbidden)
+			assert.ErrorIs(t, organisations.Delete(ctx, orgID), ErrForbidden)
+			_, err = organisations.CreateCostCentre(ctx, orgID, CostCentreDTO{Kind: models.CostCentreDepartment, Code: "ENG", Name: "Engineering"})
+			assert.ErrorIs(t, err, ErrForbidden)
+			assert.ErrorIs(t, organisations.DeleteCostCentre(ctx, orgID, uuid.New()), ErrForbidden)
+			// Менеджер, добавивший себя в организацию, смог бы менять
+			// подписки всех её пользователей.
+			_, err = organisations.AddUser(ctx, orgID, manager)
+			assert.ErrorIs(t, err, ErrForbidden)
+			assert.ErrorIs(t, organisations.RemoveUser(ctx, orgID, uuid.New()), ErrForbidden)
+
+			_, err = userData.EraseUserData(ctx, manager)
+			assert.ErrorIs(t, err, ErrForbidden)
+
+			mockServices.AssertExpectations(t)
+			mockOrgs.AssertExpectations(t)
+			mockUserData.AssertExpectations(t)
+		})
+	}
+}
+
+func TestOrganisationService_AddUser_Admin(t *testing.T) {
+	mockOrgs := new(MockOrganisationRepository)
+	organisations := NewOrganisationService(mockOrgs, new(MockRepository), NewPolicy(&fakeMembership{}))
+	orgID, userID := uuid.New(), uuid.New()
+
+	mockOrgs.On("AddUser", mock.Anything, &models.OrganisationUser{OrganisationID: orgID, UserID: userID}).Return(nil)
+
+	_, err := organisations.AddUser(asPrincipal(auth.RoleAdmin, nil), orgID, userID)
+
+	require.NoError(t, err)
+	mockOrgs.AssertExpectations(t)
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"effective-mobile-task/internal/models"
+	"github.com/google/uuid"
+)
+
+// PriceChangeDTO — изменение цены места с указанного месяца.
+type PriceChangeDTO struct {
+	EffectiveFrom models.Month `json:"effective_from" validate:"required" swaggertype:"string" example:"01-2026"`
+	UnitPrice     int          `json:"unit_price" validate:"required,gt=0"`
+}
+
+// SeatChangeDTO — изменение числа мест с указанного месяца.
+type SeatChangeDTO struct {
+	EffectiveFrom models.Month `json:"effective_from" validate:"required" swaggertype:"string" example:"01-2026"`
+	Quantity      int          `json:"quantity" validate:"required,gt=0"`
+}
+
+// SchedulePriceChange планирует изменение цены места с месяца
+// dto.EffectiveFrom и возвращает обновлённую историю цен.
+func (s *SubscriptionService) SchedulePriceChange(ctx context.Context, id uuid.UUID, dto PriceChangeDTO) ([]models.PricePeriod, error) {
+	if dto.UnitPrice <= 0 {
+		return nil, &ValidationError{Field: "unit_price", Rule: RulePricePositive}
+	}
+
+	sub, err := s.authorizedSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateChangeMonth(sub, dto.EffectiveFrom, models.MonthOf(s.now())); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SchedulePrice(ctx, id, dto.EffectiveFrom, dto.UnitPrice); err != nil {
+		return nil, fmt.Errorf("не удалось запланировать изменение цены: %w", err)
+	}
+
+	return s.repo.PriceHistory(ctx, id)
+}
+
+// ChangeSeats меняет число мест с месяца dto.EffectiveFrom и возвращает
+// обновлённую историю цен.
+func (s *SubscriptionService) ChangeSeats(ctx context.Context, id uuid.UUID, dto SeatChangeDTO) ([]models.PricePeriod, error) {
+	if dto.Quantity <= 0 {
+		return nil, &ValidationError{Field: "quantity", Rule: RuleRequired}
+	}
+
+	sub, err := s.authorizedSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateChangeMonth(sub, dto.EffectiveFrom, models.MonthOf(s.now())); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ScheduleSeats(ctx, id, dto.EffectiveFrom, dto.Quantity); err != nil {
+		return nil, fmt.Errorf("не удалось изменить число мест: %w", err)
+	}
+
+	return s.repo.PriceHistory(ctx, id)
+}
+
+// PriceHistory возвращает периоды цены подписки, включая запланированные.
+func (s *SubscriptionService) PriceHistory(ctx context.Context, id uuid.UUID) ([]models.PricePeriod, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.PriceHistory(ctx, id)
+}
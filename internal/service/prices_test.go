@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"effective-mobile-task/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var pricesNow = time.Date(2025, time.September, 15, 12, 0, 0, 0, time.UTC)
+
+func TestSubscriptionService_SchedulePriceChange(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo, WithClock(func() time.Time { return pricesNow }))
+
+	sub := &models.Subscription{ID: uuid.New(), Price: 400, UnitPrice: 400, Quantity: 1, StartDate: models.NewMonth(2025, time.January)}
+	november := models.NewMonth(2025, time.November)
+	history := []models.PricePeriod{
+		{EffectiveFrom: sub.StartDate, UnitPrice: 400, Quantity: 1, Price: 400},
+		{EffectiveFrom: november, UnitPrice: 500, Quantity: 1, Price: 500},
+	}
+
+	mockRepo.On("GetByID", mock.Anything, sub.ID).Return(sub, nil)
+	mockRepo.On("SchedulePrice", mock.Anything, sub.ID, november, 500).Return(nil)
+	mockRepo.On("PriceHistory", mock.Anything, sub.ID).Return(history, nil)
+
+	got, err := service.SchedulePriceChange(context.Background(), sub.ID, PriceChangeDTO{
+		EffectiveFrom: november,
+		UnitPrice:     500,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, history, got)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSubscriptionService_SchedulePriceChange_Rejected(t *testing.T) {
+	end := models.NewMonth(2025, time.December)
+	tests := []struct {
+		name          string
+		effectiveFrom models.Month
+		rule          string
+	}{
+		{name: "past month", effectiveFrom: models.NewMonth(2025, time.August), rule: RuleChangeInPast},
+		{name: "after end", effectiveFrom: models.NewMonth(2026, time.January), rule: RuleChangeOutsidePeriod},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			service := NewSubscriptionService(mockRepo, WithClock(func() time.Time { return pricesNow }))
+
+			sub := &models.Subscription{ID: uuid.New(), Price: 400, StartDate: models.NewMonth(2025, time.January), EndDate: &end}
+			mockRepo.On("GetByID", mock.Anything, sub.ID).Return(sub, nil)
+
+			_, err := service.SchedulePriceChange(context.Background(), sub.ID, PriceChangeDTO{
+				EffectiveFrom: tt.effectiveFrom,
+				UnitPrice:     500,
+			})
+
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.rule, validationErr.Rule)
+			mockRepo.AssertNotCalled(t, "SchedulePrice", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestSubscriptionService_ChangeSeats(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo, WithClock(func() time.Time { return pricesNow }))
+
+	sub := &models.Subscription{ID: uuid.New(), Price: 1000, UnitPrice: 100, Quantity: 10, StartDate: models.NewMonth(2025, time.January)}
+	october := models.NewMonth(2025, time.October)
+
+	mockRepo.On("GetByID", mock.Anything, sub.ID).Return(sub, nil)
+	mockRepo.On("ScheduleSeats", mock.Anything, sub.ID, october, 12).Return(nil)
+	mockRepo.On("PriceHistory", mock.Anything, sub.ID).Return([]models.PricePeriod{}, nil)
+
+	_, err := service.ChangeSeats(context.Background(), sub.ID, SeatChangeDTO{EffectiveFrom: october, Quantity: 12})
+
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestResolvePricing(t *testing.T) {
+	tests := []struct {
+		name                        string
+		price, unitPrice, quantity  int
+		wantUnitPrice, wantQuantity int
+		wantRule                    string
+	}{
+		{name: "price only", price: 400, wantUnitPrice: 400, wantQuantity: 1},
+		{name: "unit price and seats", unitPrice: 100, quantity: 12, wantUnitPrice: 100, wantQuantity: 12},
+		{name: "price split by seats", price: 1200, quantity: 12, wantUnitPrice: 100, wantQuantity: 12},
+		{name: "consistent price", price: 1200, unitPrice: 100, quantity: 12, wantUnitPrice: 100, wantQuantity: 12},
+		{name: "inconsistent price", price: 1000, unitPrice: 100, quantity: 12, wantRule: RulePriceMismatch},
+		{name: "indivisible price", price: 1000, quantity: 3, wantRule: RulePriceMismatch},
+		{name: "no price", quantity: 3, wantRule: RulePricePositive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unitPrice, quantity, err := resolvePricing(tt.price, tt.unitPrice, tt.quantity)
+			if tt.wantRule != "" {
+				var validationErr *ValidationError
+				require.ErrorAs(t, err, &validationErr)
+				assert.Equal(t, tt.wantRule, validationErr.Rule)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantUnitPrice, unitPrice)
+			assert.Equal(t, tt.wantQuantity, quantity)
+		})
+	}
+}
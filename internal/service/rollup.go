@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"effective-mobile-task/internal/repository/postgres"
+)
+
+type MonthlySpendRepository interface {
+	RefreshMonthlySpend(ctx context.Context) (*postgres.MonthlySpendRefresh, error)
+	RebuildMonthlySpend(ctx context.Context) (*postgres.MonthlySpendRefresh, error)
+}
+
+// RollupRefresher поддерживает таблицу стоимости подписок по месяцам, из
+// которой читаются сводки: пересчитывает изменившиеся подписки и строит
+// таблицу заново с началом нового месяца.
+type RollupRefresher struct {
+	repo    MonthlySpendRepository
+	tenants TenantScope
+}
+
+func NewRollupRefresher(repo MonthlySpendRepository) *RollupRefresher {
+	return &RollupRefresher{repo: repo}
+}
+
+// SetTenants включает обновление таблицы каждого арендатора по очереди.
+func (r *RollupRefresher) SetTenants(scope TenantScope) {
+	r.tenants = scope
+}
+
+// Run обновляет таблицу раз в interval, пока не отменён ctx. Ошибка одного
+// обновления не останавливает следующие: о ней сообщается через onError.
+func (r *RollupRefresher) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	refresh := func(ctx context.Context) error {
+		_, err := r.repo.RefreshMonthlySpend(ctx)
+		if err != nil {
+			return fmt.Errorf("не удалось обновить стоимость подписок по месяцам: %w", err)
+		}
+		return nil
+	}
+
+	for {
+		if err := inTenants(ctx, r.tenants, refresh); err != nil && ctx.Err() == nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Rebuild строит таблицу заново для каждого арендатора и передаёт итог
+// в report.
+func (r *RollupRefresher) Rebuild(ctx context.Context, report func(ctx context.Context, refresh *postgres.MonthlySpendRefresh)) error {
+	return inTenants(ctx, r.tenants, func(ctx context.Context) error {
+		refresh, err := r.repo.RebuildMonthlySpend(ctx)
+		if err != nil {
+			return fmt.Errorf("не удалось построить стоимость подписок по месяцам: %w", err)
+		}
+		report(ctx, refresh)
+		return nil
+	})
+}
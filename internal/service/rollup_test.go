@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"effective-mobile-task/internal/repository/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMonthlySpendRepository struct {
+	mock.Mock
+}
+
+func (m *MockMonthlySpendRepository) RefreshMonthlySpend(ctx context.Context) (*postgres.MonthlySpendRefresh, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*postgres.MonthlySpendRefresh), args.Error(1)
+}
+
+func (m *MockMonthlySpendRepository) RebuildMonthlySpend(ctx context.Context) (*postgres.MonthlySpendRefresh, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*postgres.MonthlySpendRefresh), args.Error(1)
+}
+
+func TestRollupRefresher_Run_EachTenant(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockRepo := new(MockMonthlySpendRepository)
+	refresher := NewRollupRefresher(mockRepo)
+	refresher.SetTenants(&fakeTenants{tenants: []string{"acme", "globex"}, stop: cancel})
+
+	var seen []any
+	mockRepo.On("RefreshMonthlySpend", mock.Anything).
+		Run(func(args mock.Arguments) {
+			seen = append(seen, args.Get(0).(context.Context).Value(tenantKey{}))
+		}).
+		Return(&postgres.MonthlySpendRefresh{}, nil)
+
+	refresher.Run(ctx, time.Hour, func(err error) { t.Errorf("неожиданная ошибка: %v", err) })
+
+	assert.Equal(t, []any{"acme", "globex"}, seen)
+	mockRepo.AssertNotCalled(t, "RebuildMonthlySpend", mock.Anything)
+}
+
+func TestRollupRefresher_Rebuild(t *testing.T) {
+	mockRepo := new(MockMonthlySpendRepository)
+	refresher := NewRollupRefresher(mockRepo)
+	refresher.SetTenants(&fakeTenants{tenants: []string{"acme", "globex"}, stop: func() {}})
+
+	mockRepo.On("RebuildMonthlySpend", mock.Anything).
+		Return(&postgres.MonthlySpendRefresh{Rebuilt: true}, nil).Once()
+	mockRepo.On("RebuildMonthlySpend", mock.Anything).
+		Return(nil, errors.New("boom")).Once()
+
+	var reported []any
+	err := refresher.Rebuild(context.Background(), func(ctx context.Context, refresh *postgres.MonthlySpendRefresh) {
+		assert.True(t, refresh.Rebuilt)
+		reported = append(reported, ctx.Value(tenantKey{}))
+	})
+
+	assert.ErrorContains(t, err, "boom")
+	assert.Equal(t, []any{"acme"}, reported)
+	mockRepo.AssertNumberOfCalls(t, "RebuildMonthlySpend", 2)
+}
@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"effective-mobile-task/internal/models"
@@ -14,45 +16,187 @@ import (
 type SubscriptionRepository interface {
 	Create(ctx context.Context, sub *models.Subscription) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Subscription, error)
+	GetByIDAsOf(ctx context.Context, id uuid.UUID, asOf time.Time) (*models.Subscription, error)
 	Update(ctx context.Context, sub *models.Subscription) error
 	Delete(ctx context.Context, id uuid.UUID) error
-	GetSummary(ctx context.Context, filter postgres.GetSummaryFilter) (int, error)
+	GetSummary(ctx context.Context, filter postgres.GetSummaryFilter) (*models.SummaryTotals, error)
+	GetSummaryByCategory(ctx context.Context, filter postgres.GetSummaryFilter) ([]models.CategorySummary, error)
+	MonthlySpend(ctx context.Context, filter postgres.GetSummaryFilter) ([]models.MonthlySpend, error)
+	SearchSubscriptions(ctx context.Context, q postgres.SearchQuery) ([]models.SubscriptionMatch, error)
+	ListSubscriptions(ctx context.Context, filter postgres.GetSummaryFilter, limit, offset int) ([]models.Subscription, error)
+	AddTags(ctx context.Context, subscriptionID uuid.UUID, tags []string) ([]string, error)
+	RemoveTag(ctx context.Context, subscriptionID uuid.UUID, tag string) ([]string, error)
+	ChangeStatus(ctx context.Context, t postgres.StatusTransition) error
+	ConvertingTrials(ctx context.Context, q postgres.TrialQuery) ([]models.TrialConversion, error)
+	SchedulePrice(ctx context.Context, subscriptionID uuid.UUID, from models.Month, unitPrice int) error
+	ScheduleSeats(ctx context.Context, subscriptionID uuid.UUID, from models.Month, quantity int) error
+	PriceHistory(ctx context.Context, subscriptionID uuid.UUID) ([]models.PricePeriod, error)
+	AddDiscount(ctx context.Context, d *models.Discount) error
+	ListDiscounts(ctx context.Context, subscriptionID uuid.UUID) ([]models.Discount, error)
+	DeleteDiscount(ctx context.Context, subscriptionID, discountID uuid.UUID) error
+	SetMember(ctx context.Context, m *models.SubscriptionMember) error
+	ListMembers(ctx context.Context, subscriptionID uuid.UUID) ([]models.SubscriptionMember, error)
+	RemoveMember(ctx context.Context, subscriptionID, userID uuid.UUID) error
+	MemberShares(ctx context.Context, month models.Month, userID *uuid.UUID) ([]models.MemberShare, error)
+	SetCostCentre(ctx context.Context, id uuid.UUID, costCentreID *uuid.UUID) error
+	StatusHistory(ctx context.Context, subscriptionID uuid.UUID) ([]models.StatusChange, error)
+	FindOverlapping(ctx context.Context, q postgres.OverlapQuery) (*models.Subscription, error)
+	LockOverlaps(ctx context.Context, keys []postgres.OverlapKey, fn func(ctx context.Context) error) error
+	ApplyBatch(ctx context.Context, ops []postgres.BatchOperation, atomic bool) ([]error, error)
+	ImportSubscriptions(ctx context.Context, next func() (*postgres.ImportedSubscription, error), checks postgres.ImportChecks) (int64, error)
+	ExportSubscriptions(ctx context.Context, filter postgres.GetSummaryFilter, fn func(*models.Subscription) error) error
 }
 
 
+// ServiceResolver находит сервис каталога по названию или псевдониму.
+type ServiceResolver interface {
+	Resolve(ctx context.Context, name string) (*models.Service, error)
+}
+
 type SubscriptionService struct {
-	repo SubscriptionRepository
+	repo         SubscriptionRepository
+	catalog      ServiceResolver
+	policy       Authorizer
+	allowOverlap bool
+	now          func() time.Time
 }
 
+// Option настраивает SubscriptionService.
+type Option func(*SubscriptionService)
+
+// WithAllowOverlap разрешает пользователю иметь несколько подписок
+// на один сервис с пересекающимися периодами.
+func WithAllowOverlap(allow bool) Option {
+	return func(s *SubscriptionService) {
+		s.allowOverlap = allow
+	}
+}
 
-func NewSubscriptionService(repo SubscriptionRepository) *SubscriptionService {
-	return &SubscriptionService{
+// WithClock задаёт источник текущего времени, от которого отсчитываются
+// переходы между состояниями подписки.
+func WithClock(now func() time.Time) Option {
+	return func(s *SubscriptionService) {
+		s.now = now
+	}
+}
+
+// WithServiceCatalog включает привязку подписок к каталогу сервисов: название
+// подписки ищется среди названий и псевдонимов каталога и заменяется на
+// каноническое.
+func WithServiceCatalog(catalog ServiceResolver) Option {
+	return func(s *SubscriptionService) {
+		s.catalog = catalog
+	}
+}
+
+// WithPolicy включает проверку прав: GetSummary и все изменения подписок
+// спрашивают policy, разрешено ли действие.
+func WithPolicy(policy Authorizer) Option {
+	return func(s *SubscriptionService) {
+		s.policy = policy
+	}
+}
+
+
+func NewSubscriptionService(repo SubscriptionRepository, opts ...Option) *SubscriptionService {
+	s := &SubscriptionService{
 		repo: repo,
+		now:  time.Now,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 
 type CreateSubscriptionDTO struct {
 	UserID      uuid.UUID  `json:"user_id" validate:"required"`                 
 	ServiceName string     `json:"service_name" validate:"required,min=2,max=100"` 
-	Price       int        `json:"price" validate:"required,gt=0"`              
-	StartDate   time.Time  `json:"start_date" validate:"required"`
-	EndDate     *time.Time `json:"end_date,omitempty"`
+	// Price — цена за месяц. Для подписки с несколькими местами вместо неё
+	// можно указать UnitPrice и Quantity.
+	Price       int        `json:"price,omitempty" validate:"omitempty,gt=0"`
+	UnitPrice   int        `json:"unit_price,omitempty" validate:"omitempty,gt=0"`
+	Quantity    int        `json:"quantity,omitempty" validate:"omitempty,gt=0"`
+	StartDate   models.Month  `json:"start_date" validate:"required" swaggertype:"string" example:"07-2025"`
+	EndDate     *models.Month `json:"end_date,omitempty" swaggertype:"string" example:"12-2025"`
+	// TrialEndDate — последний месяц бесплатного пробного периода.
+	TrialEndDate *models.Month `json:"trial_end_date,omitempty" swaggertype:"string" example:"08-2025"`
 }
 
 
 func (s *SubscriptionService) Create(ctx context.Context, dto CreateSubscriptionDTO) (*models.Subscription, error) {
+	if err := s.authorize(ctx, ActionCreate, &dto.UserID); err != nil {
+		return nil, err
+	}
+
+	sub, err := s.newSubscription(ctx, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.lockOverlaps(ctx, []*models.Subscription{sub}, func(ctx context.Context) error {
+		if err := s.checkOverlap(ctx, sub); err != nil {
+			return err
+		}
+		if err := s.repo.Create(ctx, sub); err != nil {
+			return fmt.Errorf("не удалось создать подписку: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sub, nil
+}
+
+// newSubscription проверяет бизнес-правила и собирает новую подписку из DTO.
+func (s *SubscriptionService) newSubscription(ctx context.Context, dto CreateSubscriptionDTO) (*models.Subscription, error) {
+	sub, err := buildSubscription(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.resolveService(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	return sub, nil
+}
+
+// buildSubscription проверяет данные новой подписки и собирает её, не
+// обращаясь к базе.
+func buildSubscription(dto CreateSubscriptionDTO) (*models.Subscription, error) {
+	serviceName, err := canonicalServiceName(dto.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validatePeriod(dto.StartDate, dto.EndDate); err != nil {
+		return nil, err
+	}
+
+	if err := validateTrial(dto.StartDate, dto.EndDate, dto.TrialEndDate); err != nil {
+		return nil, err
+	}
+
+	unitPrice, quantity, err := resolvePricing(dto.Price, dto.UnitPrice, dto.Quantity)
+	if err != nil {
+		return nil, err
+	}
+
 	sub := &models.Subscription{
 		ID:          uuid.New(), 
 		UserID:      dto.UserID,
-		ServiceName: dto.ServiceName,
-		Price:       dto.Price,
+		ServiceName: serviceName,
+		Price:       unitPrice * quantity,
+		UnitPrice:   unitPrice,
+		Quantity:    quantity,
 		StartDate:   dto.StartDate,
 		EndDate:     dto.EndDate,
-	}
-
-	if err := s.repo.Create(ctx, sub); err != nil {
-		return nil, fmt.Errorf("не удалось создать подписку: %w", err)
+		TrialEndDate: dto.TrialEndDate,
+		Status:      models.StatusActive,
 	}
 
 	return sub, nil
@@ -63,36 +207,243 @@ func (s *SubscriptionService) GetByID(ctx context.Context, id uuid.UUID) (*model
 	return s.repo.GetByID(ctx, id)
 }
 
+// GetByIDAsOf возвращает подписку такой, какой она была в момент asOf.
+func (s *SubscriptionService) GetByIDAsOf(ctx context.Context, id uuid.UUID, asOf time.Time) (*models.Subscription, error) {
+	return s.repo.GetByIDAsOf(ctx, id, asOf)
+}
+
 
 type UpdateSubscriptionDTO struct {
 	ServiceName string     `json:"service_name" validate:"required,min=2,max=100"`
-	Price       int        `json:"price" validate:"required,gt=0"`
-	StartDate   time.Time  `json:"start_date" validate:"required"`
-	EndDate     *time.Time `json:"end_date,omitempty"`
+	// Price, UnitPrice и Quantity задаются так же, как при создании. Новая
+	// цена места и число мест действуют с текущего месяца, прошлые месяцы
+	// сохраняют прежние.
+	Price       int        `json:"price,omitempty" validate:"omitempty,gt=0"`
+	UnitPrice   int        `json:"unit_price,omitempty" validate:"omitempty,gt=0"`
+	Quantity    int        `json:"quantity,omitempty" validate:"omitempty,gt=0"`
+	StartDate   models.Month  `json:"start_date" validate:"required" swaggertype:"string" example:"07-2025"`
+	EndDate     *models.Month `json:"end_date,omitempty" swaggertype:"string" example:"12-2025"`
+	// TrialEndDate — последний месяц бесплатного пробного периода.
+	TrialEndDate *models.Month `json:"trial_end_date,omitempty" swaggertype:"string" example:"08-2025"`
 }
 
 
 func (s *SubscriptionService) Update(ctx context.Context, id uuid.UUID, dto UpdateSubscriptionDTO) error {
-	sub, err := s.repo.GetByID(ctx, id)
+	sub, err := s.applyUpdate(ctx, id, dto)
 	if err != nil {
 		return err
 	}
 
+	if err := s.authorize(ctx, ActionUpdate, &sub.UserID); err != nil {
+		return err
+	}
+
+	return s.lockOverlaps(ctx, []*models.Subscription{sub}, func(ctx context.Context) error {
+		if err := s.checkOverlap(ctx, sub); err != nil {
+			return err
+		}
+		return s.repo.Update(ctx, sub)
+	})
+}
+
+// applyUpdate проверяет бизнес-правила и применяет изменения из DTO
+// к текущему состоянию подписки.
+func (s *SubscriptionService) applyUpdate(ctx context.Context, id uuid.UUID, dto UpdateSubscriptionDTO) (*models.Subscription, error) {
+	serviceName, err := canonicalServiceName(dto.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validatePeriod(dto.StartDate, dto.EndDate); err != nil {
+		return nil, err
+	}
+
+	if err := validateTrial(dto.StartDate, dto.EndDate, dto.TrialEndDate); err != nil {
+		return nil, err
+	}
+
+	unitPrice, quantity, err := resolvePricing(dto.Price, dto.UnitPrice, dto.Quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
 	// Обновляем поля
-	sub.ServiceName = dto.ServiceName
-	sub.Price = dto.Price
+	sub.ServiceName = serviceName
+	sub.Price = unitPrice * quantity
+	sub.UnitPrice = unitPrice
+	sub.Quantity = quantity
 	sub.StartDate = dto.StartDate
 	sub.EndDate = dto.EndDate
+	sub.TrialEndDate = dto.TrialEndDate
+
+	if err := s.resolveService(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	return sub, nil
+}
+
+// resolveService привязывает подписку к сервису каталога с таким названием
+// или псевдонимом. Если сервиса в каталоге нет, подписка остаётся без
+// привязки с названием, которое указал клиент.
+func (s *SubscriptionService) resolveService(ctx context.Context, sub *models.Subscription) error {
+	sub.ServiceID = nil
+	if s.catalog == nil {
+		return nil
+	}
+
+	svc, err := s.catalog.Resolve(ctx, sub.ServiceName)
+	if err != nil {
+		if errors.Is(err, postgres.ErrServiceNotFound) {
+			return nil
+		}
+		return fmt.Errorf("не удалось найти сервис в каталоге: %w", err)
+	}
 
-	return s.repo.Update(ctx, sub)
+	sub.ServiceID = &svc.ID
+	sub.ServiceName = svc.Name
+	return nil
 }
 
 
 func (s *SubscriptionService) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := s.authorize(ctx, ActionDelete, nil); err != nil {
+		return err
+	}
+
 	return s.repo.Delete(ctx, id)
 }
 
 
-func (s *SubscriptionService) GetSummary(ctx context.Context, filter postgres.GetSummaryFilter) (int, error) {
+func (s *SubscriptionService) GetSummary(ctx context.Context, filter postgres.GetSummaryFilter) (*models.SummaryTotals, error) {
+	if err := s.authorize(ctx, ActionSummary, filter.UserID); err != nil {
+		return nil, err
+	}
+
 	return s.repo.GetSummary(ctx, filter)
 }
+
+// authorize проверяет, разрешено ли действие. Без политики разрешено всё.
+func (s *SubscriptionService) authorize(ctx context.Context, action Action, ownerID *uuid.UUID) error {
+	return authorize(ctx, s.policy, action, ownerID)
+}
+
+// authorizedSubscription возвращает подписку id, если её владельца можно
+// менять от имени запроса.
+func (s *SubscriptionService) authorizedSubscription(ctx context.Context, id uuid.UUID) (*models.Subscription, error) {
+	sub, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorize(ctx, ActionUpdate, &sub.UserID); err != nil {
+		return nil, err
+	}
+
+	return sub, nil
+}
+
+// GetSummaryByCategory считает суммарную стоимость подписок по категориям
+// каталога сервисов.
+func (s *SubscriptionService) GetSummaryByCategory(ctx context.Context, filter postgres.GetSummaryFilter) ([]models.CategorySummary, error) {
+	return s.repo.GetSummaryByCategory(ctx, filter)
+}
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+
+	defaultListLimit = 100
+	maxListLimit     = 1000
+)
+
+// List возвращает страницу подписок, подходящих под фильтр сводки.
+// limit <= 0 означает лимит по умолчанию.
+func (s *SubscriptionService) List(ctx context.Context, filter postgres.GetSummaryFilter, limit, offset int) ([]models.Subscription, error) {
+	switch {
+	case limit <= 0:
+		limit = defaultListLimit
+	case limit > maxListLimit:
+		limit = maxListLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	return s.repo.ListSubscriptions(ctx, filter, limit, offset)
+}
+
+// Search ищет подписки по неточному названию сервиса: опечатки и начало
+// названия тоже находят подписку. limit <= 0 означает лимит по умолчанию.
+func (s *SubscriptionService) Search(ctx context.Context, text string, filter postgres.GetSummaryFilter, limit int) ([]models.SubscriptionMatch, error) {
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return nil, &ValidationError{Field: "q", Rule: RuleRequired}
+	}
+
+	switch {
+	case limit <= 0:
+		limit = defaultSearchLimit
+	case limit > maxSearchLimit:
+		limit = maxSearchLimit
+	}
+
+	matches, err := s.repo.SearchSubscriptions(ctx, postgres.SearchQuery{Text: text, Filter: filter, Limit: limit})
+	if err != nil {
+		return nil, fmt.Errorf("не удалось найти подписки: %w", err)
+	}
+
+	return matches, nil
+}
+
+// Export передаёт в fn подписки, подходящие под фильтр сводки. Подписка
+// передаётся по указателю и переиспользуется между вызовами, поэтому fn
+// не должна сохранять её.
+func (s *SubscriptionService) Export(ctx context.Context, filter postgres.GetSummaryFilter, fn func(*models.Subscription) error) error {
+	return s.repo.ExportSubscriptions(ctx, filter, fn)
+}
+
+// lockOverlaps выполняет fn так, чтобы между проверкой пересечений subs и
+// их записью никто не записал пересекающуюся подписку. Если пересечения
+// разрешены, блокировать нечего.
+func (s *SubscriptionService) lockOverlaps(ctx context.Context, subs []*models.Subscription, fn func(ctx context.Context) error) error {
+	if s.allowOverlap || len(subs) == 0 {
+		return fn(ctx)
+	}
+
+	keys := make([]postgres.OverlapKey, len(subs))
+	for i, sub := range subs {
+		keys[i] = postgres.OverlapKey{UserID: sub.UserID, ServiceName: sub.ServiceName}
+	}
+
+	return s.repo.LockOverlaps(ctx, keys, fn)
+}
+
+// checkOverlap проверяет, что у пользователя нет другой подписки на тот же
+// сервис, активной в пересекающийся период.
+func (s *SubscriptionService) checkOverlap(ctx context.Context, sub *models.Subscription, exclude ...uuid.UUID) error {
+	if s.allowOverlap {
+		return nil
+	}
+
+	existing, err := s.repo.FindOverlapping(ctx, postgres.OverlapQuery{
+		UserID:      sub.UserID,
+		ServiceName: sub.ServiceName,
+		StartDate:   sub.StartDate,
+		EndDate:     sub.EndDate,
+		ExcludeIDs:  append([]uuid.UUID{sub.ID}, exclude...),
+	})
+	if err != nil {
+		if errors.Is(err, postgres.ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("не удалось проверить пересечение подписок: %w", err)
+	}
+
+	return &OverlapError{ExistingID: existing.ID}
+}
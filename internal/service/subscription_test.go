@@ -3,6 +3,8 @@ package service
 import (
 	"context"
 	"errors"
+	"maps"
+	"slices"
 	"testing"
 	"time"
 
@@ -36,6 +38,14 @@ func (m *MockRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Sub
 	return args.Get(0).(*models.Subscription), args.Error(1)
 }
 
+func (m *MockRepository) GetByIDAsOf(ctx context.Context, id uuid.UUID, asOf time.Time) (*models.Subscription, error) {
+	args := m.Called(ctx, id, asOf)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Subscription), args.Error(1)
+}
+
 func (m *MockRepository) Update(ctx context.Context, sub *models.Subscription) error {
 	args := m.Called(ctx, sub)
 	return args.Error(0)
@@ -46,10 +56,222 @@ func (m *MockRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return args.Error(0)
 }
 
-func (m *MockRepository) GetSummary(ctx context.Context, filter postgres.GetSummaryFilter) (int, error) {
+func (m *MockRepository) GetSummary(ctx context.Context, filter postgres.GetSummaryFilter) (*models.SummaryTotals, error) {
 	args := m.Called(ctx, filter)
-	
-	return args.Int(0), args.Error(1)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SummaryTotals), args.Error(1)
+}
+
+func (m *MockRepository) GetSummaryByCategory(ctx context.Context, filter postgres.GetSummaryFilter) ([]models.CategorySummary, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.CategorySummary), args.Error(1)
+}
+
+func (m *MockRepository) MonthlySpend(ctx context.Context, filter postgres.GetSummaryFilter) ([]models.MonthlySpend, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.MonthlySpend), args.Error(1)
+}
+
+func (m *MockRepository) SearchSubscriptions(ctx context.Context, q postgres.SearchQuery) ([]models.SubscriptionMatch, error) {
+	args := m.Called(ctx, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SubscriptionMatch), args.Error(1)
+}
+
+func (m *MockRepository) ListSubscriptions(ctx context.Context, filter postgres.GetSummaryFilter, limit, offset int) ([]models.Subscription, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Subscription), args.Error(1)
+}
+
+func (m *MockRepository) AddTags(ctx context.Context, subscriptionID uuid.UUID, tags []string) ([]string, error) {
+	args := m.Called(ctx, subscriptionID, tags)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockRepository) RemoveTag(ctx context.Context, subscriptionID uuid.UUID, tag string) ([]string, error) {
+	args := m.Called(ctx, subscriptionID, tag)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockRepository) ChangeStatus(ctx context.Context, t postgres.StatusTransition) error {
+	args := m.Called(ctx, t)
+	return args.Error(0)
+}
+
+func (m *MockRepository) StatusHistory(ctx context.Context, subscriptionID uuid.UUID) ([]models.StatusChange, error) {
+	args := m.Called(ctx, subscriptionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.StatusChange), args.Error(1)
+}
+
+func (m *MockRepository) ConvertingTrials(ctx context.Context, q postgres.TrialQuery) ([]models.TrialConversion, error) {
+	args := m.Called(ctx, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.TrialConversion), args.Error(1)
+}
+
+func (m *MockRepository) SchedulePrice(ctx context.Context, subscriptionID uuid.UUID, from models.Month, unitPrice int) error {
+	args := m.Called(ctx, subscriptionID, from, unitPrice)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ScheduleSeats(ctx context.Context, subscriptionID uuid.UUID, from models.Month, quantity int) error {
+	args := m.Called(ctx, subscriptionID, from, quantity)
+	return args.Error(0)
+}
+
+func (m *MockRepository) PriceHistory(ctx context.Context, subscriptionID uuid.UUID) ([]models.PricePeriod, error) {
+	args := m.Called(ctx, subscriptionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PricePeriod), args.Error(1)
+}
+
+func (m *MockRepository) AddDiscount(ctx context.Context, d *models.Discount) error {
+	args := m.Called(ctx, d)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListDiscounts(ctx context.Context, subscriptionID uuid.UUID) ([]models.Discount, error) {
+	args := m.Called(ctx, subscriptionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Discount), args.Error(1)
+}
+
+func (m *MockRepository) DeleteDiscount(ctx context.Context, subscriptionID, discountID uuid.UUID) error {
+	args := m.Called(ctx, subscriptionID, discountID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) SetMember(ctx context.Context, member *models.SubscriptionMember) error {
+	args := m.Called(ctx, member)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListMembers(ctx context.Context, subscriptionID uuid.UUID) ([]models.SubscriptionMember, error) {
+	args := m.Called(ctx, subscriptionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SubscriptionMember), args.Error(1)
+}
+
+func (m *MockRepository) RemoveMember(ctx context.Context, subscriptionID, userID uuid.UUID) error {
+	args := m.Called(ctx, subscriptionID, userID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) MemberShares(ctx context.Context, month models.Month, userID *uuid.UUID) ([]models.MemberShare, error) {
+	args := m.Called(ctx, month, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.MemberShare), args.Error(1)
+}
+
+func (m *MockRepository) SetCostCentre(ctx context.Context, id uuid.UUID, costCentreID *uuid.UUID) error {
+	args := m.Called(ctx, id, costCentreID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Chargeback(ctx context.Context, orgID uuid.UUID, filter postgres.GetSummaryFilter) ([]models.ChargebackRow, error) {
+	args := m.Called(ctx, orgID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ChargebackRow), args.Error(1)
+}
+
+func (m *MockRepository) FindOverlapping(ctx context.Context, q postgres.OverlapQuery) (*models.Subscription, error) {
+	args := m.Called(ctx, q)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Subscription), args.Error(1)
+}
+
+// LockOverlaps выполняет fn сразу: блокировки нужны только настоящей базе.
+func (m *MockRepository) LockOverlaps(ctx context.Context, keys []postgres.OverlapKey, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (m *MockRepository) ApplyBatch(ctx context.Context, ops []postgres.BatchOperation, atomic bool) ([]error, error) {
+	args := m.Called(ctx, ops, atomic)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]error), args.Error(1)
+}
+
+func (m *MockRepository) ExportSubscriptions(ctx context.Context, filter postgres.GetSummaryFilter, fn func(*models.Subscription) error) error {
+	args := m.Called(ctx, filter)
+	if subs, ok := args.Get(0).([]*models.Subscription); ok {
+		for _, sub := range subs {
+			if err := fn(sub); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+// ImportSubscriptions вычитывает все подписки из next, как это делает COPY,
+// и сообщает о пересечениях, переданных в Return как строки файла и ID
+// подписок, с которыми они пересекаются.
+func (m *MockRepository) ImportSubscriptions(ctx context.Context, next func() (*postgres.ImportedSubscription, error), checks postgres.ImportChecks) (int64, error) {
+	args := m.Called(ctx)
+	if err := args.Error(1); err != nil {
+		return 0, err
+	}
+
+	var imported int64
+	for {
+		row, err := next()
+		if err != nil {
+			return 0, err
+		}
+		if row == nil {
+			break
+		}
+		imported++
+	}
+
+	if overlaps, ok := args.Get(0).(map[int]uuid.UUID); ok && !checks.AllowOverlap {
+		for _, line := range slices.Sorted(maps.Keys(overlaps)) {
+			checks.Overlap(line, overlaps[line])
+		}
+	}
+	if checks.Commit != nil && !checks.Commit() {
+		return 0, nil
+	}
+
+	return imported, nil
 }
 
 
@@ -63,10 +285,11 @@ func TestSubscriptionService_Create_Success(t *testing.T) {
 		UserID:      uuid.New(),
 		ServiceName: "Test Service",
 		Price:       100,
-		StartDate:   time.Now(),
+		StartDate:   models.MonthOf(time.Now()),
 	}
 
 	
+	mockRepo.On("FindOverlapping", mock.Anything, mock.Anything).Return(nil, postgres.ErrNotFound)
 	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Subscription")).Return(nil)
 
 	
@@ -102,4 +325,138 @@ func TestSubscriptionService_GetByID_NotFound(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestSubscriptionService_GetByIDAsOf(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+
+	testID := uuid.New()
+	asOf := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+	version := &models.Subscription{ID: testID, ServiceName: "Netflix"}
+
+	mockRepo.On("GetByIDAsOf", mock.Anything, testID, asOf).Return(version, nil)
+
+	sub, err := service.GetByIDAsOf(context.Background(), testID, asOf)
+
+	assert.NoError(t, err)
+	assert.Same(t, version, sub)
+	mockRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+
+func TestSubscriptionService_Create_NormalizesInput(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+
+	end := models.NewMonth(2025, time.December)
+	dto := CreateSubscriptionDTO{
+		UserID:      uuid.New(),
+		ServiceName: "  Yandex   Plus ",
+		Price:       400,
+		StartDate:   models.NewMonth(2025, time.July),
+		EndDate:     &end,
+	}
+
+	mockRepo.On("FindOverlapping", mock.Anything, mock.Anything).Return(nil, postgres.ErrNotFound)
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Subscription")).Return(nil)
+
+	sub, err := service.Create(context.Background(), dto)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Yandex Plus", sub.ServiceName)
+	assert.Equal(t, models.NewMonth(2025, time.July), sub.StartDate)
+	assert.Equal(t, end, *sub.EndDate)
+	mockRepo.AssertExpectations(t)
+}
+
+
+func TestSubscriptionService_Create_EndBeforeStart(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+
+	end := models.NewMonth(2025, time.June)
+	dto := CreateSubscriptionDTO{
+		UserID:      uuid.New(),
+		ServiceName: "Test Service",
+		Price:       100,
+		StartDate:   models.NewMonth(2025, time.July),
+		EndDate:     &end,
+	}
+
+	sub, err := service.Create(context.Background(), dto)
+
+	var validationErr *ValidationError
+	assert.Nil(t, sub)
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, RuleEndBeforeStart, validationErr.Rule)
+	assert.ErrorIs(t, err, ErrInvalidSubscription)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+
+func TestSubscriptionService_Create_Overlap(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+
+	existing := &models.Subscription{ID: uuid.New()}
+	dto := CreateSubscriptionDTO{
+		UserID:      uuid.New(),
+		ServiceName: "Test Service",
+		Price:       100,
+		StartDate:   models.MonthOf(time.Now()),
+	}
+
+	mockRepo.On("FindOverlapping", mock.Anything, mock.Anything).Return(existing, nil)
+
+	sub, err := service.Create(context.Background(), dto)
+
+	var overlapErr *OverlapError
+	assert.Nil(t, sub)
+	assert.ErrorAs(t, err, &overlapErr)
+	assert.Equal(t, existing.ID, overlapErr.ExistingID)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+
+func TestSubscriptionService_Create_OverlapAllowed(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo, WithAllowOverlap(true))
+
+	dto := CreateSubscriptionDTO{
+		UserID:      uuid.New(),
+		ServiceName: "Test Service",
+		Price:       100,
+		StartDate:   models.MonthOf(time.Now()),
+	}
+
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Subscription")).Return(nil)
+
+	_, err := service.Create(context.Background(), dto)
+
+	assert.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "FindOverlapping", mock.Anything, mock.Anything)
+}
+
+
+func TestSubscriptionService_Search_NormalizesQueryAndLimit(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+
+	expected := postgres.SearchQuery{Text: "yandex plus", Limit: maxSearchLimit}
+	mockRepo.On("SearchSubscriptions", mock.Anything, expected).Return([]models.SubscriptionMatch{}, nil)
+
+	_, err := service.Search(context.Background(), "  yandex   plus ", postgres.GetSummaryFilter{}, 1000)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+
+func TestSubscriptionService_Search_EmptyQuery(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+
+	_, err := service.Search(context.Background(), "   ", postgres.GetSummaryFilter{}, 0)
 
+	assert.ErrorIs(t, err, ErrInvalidSubscription)
+	mockRepo.AssertNotCalled(t, "SearchSubscriptions", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	tagMaxLen         = 50
+	maxTagsPerRequest = 20
+)
+
+// TagsDTO — метки, которые нужно повесить на подписку.
+type TagsDTO struct {
+	Tags []string `json:"tags" validate:"required,min=1,max=20" example:"work,reimbursable"`
+}
+
+// NormalizeTags приводит метки к единому виду: без лишних пробелов, в нижнем
+// регистре и без повторов, чтобы «Work» и « work » были одной меткой.
+func NormalizeTags(raw []string) ([]string, error) {
+	if len(raw) > maxTagsPerRequest {
+		return nil, &ValidationError{Field: "tags", Rule: RuleTagLength}
+	}
+
+	seen := make(map[string]bool, len(raw))
+	tags := make([]string, 0, len(raw))
+	for _, r := range raw {
+		tag, err := normalizeTag(r)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+func normalizeTag(raw string) (string, error) {
+	if !utf8.ValidString(raw) {
+		return "", &ValidationError{Field: "tags", Rule: RuleInvalidFormat}
+	}
+	for _, r := range raw {
+		if unicode.IsControl(r) && !unicode.IsSpace(r) {
+			return "", &ValidationError{Field: "tags", Rule: RuleInvalidFormat}
+		}
+	}
+
+	tag := strings.ToLower(strings.Join(strings.Fields(raw), " "))
+	if tag == "" || utf8.RuneCountInString(tag) > tagMaxLen {
+		return "", &ValidationError{Field: "tags", Rule: RuleTagLength}
+	}
+	return tag, nil
+}
+
+// AddTags вешает метки на подписку и возвращает все её метки.
+func (s *SubscriptionService) AddTags(ctx context.Context, id uuid.UUID, dto TagsDTO) ([]string, error) {
+	tags, err := NormalizeTags(dto.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) == 0 {
+		return nil, &ValidationError{Field: "tags", Rule: RuleRequired}
+	}
+
+	if _, err := s.authorizedSubscription(ctx, id); err != nil {
+		return nil, err
+	}
+
+	result, err := s.repo.AddTags(ctx, id, tags)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось добавить метки: %w", err)
+	}
+	return result, nil
+}
+
+// RemoveTag снимает метку с подписки и возвращает оставшиеся метки.
+func (s *SubscriptionService) RemoveTag(ctx context.Context, id uuid.UUID, tag string) ([]string, error) {
+	tag, err := normalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.authorizedSubscription(ctx, id); err != nil {
+		return nil, err
+	}
+
+	result, err := s.repo.RemoveTag(ctx, id, tag)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось снять метку: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"effective-mobile-task/internal/models"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tags, err := NormalizeTags([]string{" Work ", "work", "Family  Budget"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"work", "family budget"}, tags)
+}
+
+func TestNormalizeTags_TooLong(t *testing.T) {
+	_, err := NormalizeTags([]string{strings.Repeat("a", tagMaxLen+1)})
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, RuleTagLength, validationErr.Rule)
+}
+
+func TestSubscriptionService_AddTags(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+
+	id := uuid.New()
+	mockRepo.On("GetByID", mock.Anything, id).Return(&models.Subscription{ID: id, UserID: uuid.New()}, nil)
+	mockRepo.On("AddTags", mock.Anything, id, []string{"reimbursable"}).Return([]string{"reimbursable", "work"}, nil)
+
+	tags, err := service.AddTags(context.Background(), id, TagsDTO{Tags: []string{"Reimbursable", " reimbursable"}})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"reimbursable", "work"}, tags)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,17 @@
+package service
+
+import "context"
+
+// TenantScope выполняет работу от имени каждого арендатора по очереди.
+type TenantScope interface {
+	EachTenant(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// inTenants выполняет fn от имени каждого арендатора scope, а без scope —
+// один раз с ctx.
+func inTenants(ctx context.Context, scope TenantScope, fn func(ctx context.Context) error) error {
+	if scope == nil {
+		return fn(ctx)
+	}
+	return scope.EachTenant(ctx, fn)
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"effective-mobile-task/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type tenantKey struct{}
+
+// fakeTenants выполняет работу от имени каждого арендатора из списка, а
+// после обхода отменяет контекст задачи, чтобы Run завершился.
+type fakeTenants struct {
+	tenants []string
+	stop    context.CancelFunc
+}
+
+func (f *fakeTenants) EachTenant(ctx context.Context, fn func(ctx context.Context) error) error {
+	defer f.stop()
+
+	var errs []error
+	for _, tenant := range f.tenants {
+		if err := fn(context.WithValue(ctx, tenantKey{}, tenant)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func TestTrialReminder_Run_EachTenant(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockTrials := new(MockRepository)
+	reminder := NewTrialReminder(mockTrials, new(MockNotificationRepository), new(MockNotifier), 72*time.Hour)
+	reminder.SetTenants(&fakeTenants{tenants: []string{"acme", "globex"}, stop: cancel})
+
+	var seen []any
+	mockTrials.On("ConvertingTrials", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			seen = append(seen, args.Get(0).(context.Context).Value(tenantKey{}))
+		}).
+		Return([]models.TrialConversion{}, nil)
+
+	reminder.Run(ctx, time.Hour, func(err error) { t.Errorf("неожиданная ошибка: %v", err) })
+
+	assert.Equal(t, []any{"acme", "globex"}, seen)
+}
+
+func TestBudgetAlerter_Run_ReportsTenantErrors(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockBudgets := new(MockBudgetRepository)
+	alerter := NewBudgetAlerter(mockBudgets, new(MockRepository), new(MockNotificationRepository), new(MockNotifier))
+	alerter.SetTenants(&fakeTenants{tenants: []string{"acme", "globex"}, stop: func() {}})
+
+	mockBudgets.On("List", mock.Anything, mock.Anything).Return(nil, errors.New("boom")).Twice()
+
+	var reported error
+	alerter.Run(ctx, time.Hour, func(err error) {
+		reported = err
+		cancel()
+	})
+
+	assert.ErrorContains(t, reported, "boom")
+	mockBudgets.AssertNumberOfCalls(t, "List", 2)
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"effective-mobile-task/internal/models"
+	"effective-mobile-task/internal/repository/postgres"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultTrialReportDays = 7
+	maxTrialReportDays     = 366
+)
+
+// ConvertingTrials возвращает подписки, которые станут платными в ближайшие
+// days дней. Без userID в отчёт попадают подписки всех пользователей.
+// days <= 0 означает срок по умолчанию.
+func (s *SubscriptionService) ConvertingTrials(ctx context.Context, days int, userID *uuid.UUID) ([]models.TrialConversion, error) {
+	switch {
+	case days <= 0:
+		days = defaultTrialReportDays
+	case days > maxTrialReportDays:
+		days = maxTrialReportDays
+	}
+
+	now := s.now()
+	trials, err := s.repo.ConvertingTrials(ctx, postgres.TrialQuery{
+		From:   now,
+		To:     now.AddDate(0, 0, days),
+		UserID: userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("не удалось получить подписки с заканчивающимся пробным периодом: %w", err)
+	}
+
+	return trials, nil
+}
+
+// Notifier доставляет уведомления пользователям.
+type Notifier interface {
+	Notify(ctx context.Context, n models.Notification) error
+}
+
+// NotificationRepository помнит отправленные уведомления, чтобы не
+// отправлять их повторно.
+type NotificationRepository interface {
+	Record(ctx context.Context, n *models.Notification) (bool, error)
+	Forget(ctx context.Context, n *models.Notification) error
+}
+
+// TrialRepository находит подписки, пробный период которых заканчивается.
+type TrialRepository interface {
+	ConvertingTrials(ctx context.Context, q postgres.TrialQuery) ([]models.TrialConversion, error)
+}
+
+// TrialReminder заранее предупреждает пользователей о том, что пробный
+// период подписки заканчивается и она станет платной.
+type TrialReminder struct {
+	trials        TrialRepository
+	notifications NotificationRepository
+	notifier      Notifier
+	tenants       TenantScope
+	lead          time.Duration
+	now           func() time.Time
+}
+
+// NewTrialReminder создаёт напоминание, которое срабатывает за lead до
+// перехода подписки на оплату.
+func NewTrialReminder(trials TrialRepository, notifications NotificationRepository, notifier Notifier, lead time.Duration) *TrialReminder {
+	return &TrialReminder{
+		trials:        trials,
+		notifications: notifications,
+		notifier:      notifier,
+		lead:          lead,
+		now:           time.Now,
+	}
+}
+
+// SetTenants включает проверку подписок каждого арендатора по очереди.
+func (t *TrialReminder) SetTenants(scope TenantScope) {
+	t.tenants = scope
+}
+
+// Run проверяет подписки раз в interval, пока не отменён ctx. Ошибка одной
+// проверки не останавливает следующие: о ней сообщается через onError.
+func (t *TrialReminder) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	remind := func(ctx context.Context) error {
+		_, err := t.RemindOnce(ctx)
+		return err
+	}
+
+	for {
+		if err := inTenants(ctx, t.tenants, remind); err != nil && ctx.Err() == nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RemindOnce отправляет уведомления о подписках, которые станут платными в
+// ближайшее время, и возвращает число отправленных уведомлений. О каждом
+// переходе на оплату пользователь уведомляется один раз.
+func (t *TrialReminder) RemindOnce(ctx context.Context) (int, error) {
+	now := t.now()
+	trials, err := t.trials.ConvertingTrials(ctx, postgres.TrialQuery{From: now, To: now.Add(t.lead)})
+	if err != nil {
+		return 0, fmt.Errorf("не удалось найти подписки с заканчивающимся пробным периодом: %w", err)
+	}
+
+	sent := 0
+	for i := range trials {
+		trial := &trials[i]
+		n := models.Notification{
+			ID:             uuid.New(),
+			UserID:         trial.UserID,
+			SubscriptionID: &trial.ID,
+			Kind:           models.NotificationTrialEnding,
+			DueAt:          trial.ConvertsAt,
+			CreatedAt:      now,
+			Subscription:   &trial.Subscription,
+		}
+
+		recorded, err := t.notifications.Record(ctx, &n)
+		if err != nil {
+			return sent, fmt.Errorf("не удалось сохранить уведомление: %w", err)
+		}
+		if !recorded {
+			continue
+		}
+
+		if err := t.notifier.Notify(ctx, n); err != nil {
+			// Запись убирается, чтобы уведомление ушло при следующей проверке.
+			if forgetErr := t.notifications.Forget(ctx, &n); forgetErr != nil {
+				return sent, fmt.Errorf("не удалось отменить запись неотправленного уведомления: %w", forgetErr)
+			}
+			return sent, fmt.Errorf("не удалось отправить уведомление: %w", err)
+		}
+		sent++
+	}
+
+	return sent, nil
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"effective-mobile-task/internal/models"
+	"effective-mobile-task/internal/repository/postgres"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) Record(ctx context.Context, n *models.Notification) (bool, error) {
+	args := m.Called(ctx, n)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockNotificationRepository) Forget(ctx context.Context, n *models.Notification) error {
+	args := m.Called(ctx, n)
+	return args.Error(0)
+}
+
+type MockNotifier struct {
+	mock.Mock
+}
+
+func (m *MockNotifier) Notify(ctx context.Context, n models.Notification) error {
+	args := m.Called(ctx, n)
+	return args.Error(0)
+}
+
+func TestSubscriptionService_Create_TrialOutsidePeriod(t *testing.T) {
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo)
+
+	end := models.NewMonth(2025, time.September)
+	trialEnd := models.NewMonth(2025, time.October)
+	_, err := service.Create(context.Background(), CreateSubscriptionDTO{
+		UserID:       uuid.New(),
+		ServiceName:  "Test Service",
+		Price:        100,
+		StartDate:    models.NewMonth(2025, time.July),
+		EndDate:      &end,
+		TrialEndDate: &trialEnd,
+	})
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "trial_end_date", validationErr.Field)
+	assert.Equal(t, RuleTrialOutsidePeriod, validationErr.Rule)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestSubscriptionService_ConvertingTrials_Window(t *testing.T) {
+	now := time.Date(2025, time.September, 28, 10, 0, 0, 0, time.UTC)
+	mockRepo := new(MockRepository)
+	service := NewSubscriptionService(mockRepo, WithClock(func() time.Time { return now }))
+
+	userID := uuid.New()
+	mockRepo.On("ConvertingTrials", mock.Anything, postgres.TrialQuery{
+		From:   now,
+		To:     now.AddDate(0, 0, 7),
+		UserID: &userID,
+	}).Return([]models.TrialConversion{}, nil)
+
+	trials, err := service.ConvertingTrials(context.Background(), 0, &userID)
+
+	require.NoError(t, err)
+	assert.Empty(t, trials)
+	mockRepo.AssertExpectations(t)
+}
+
+func newConvertingTrial(convertsAt time.Time) models.TrialConversion {
+	trialEnd := models.MonthOf(convertsAt).AddMonths(-1)
+	return models.TrialConversion{
+		Subscription: models.Subscription{
+			ID:           uuid.New(),
+			UserID:       uuid.New(),
+			ServiceName:  "Test Service",
+			Price:        100,
+			StartDate:    trialEnd,
+			TrialEndDate: &trialEnd,
+			Status:       models.StatusActive,
+		},
+		ConvertsAt: convertsAt,
+	}
+}
+
+func TestTrialReminder_RemindOnce_SkipsAlreadyNotified(t *testing.T) {
+	now := time.Date(2025, time.September, 28, 10, 0, 0, 0, time.UTC)
+	convertsAt := time.Date(2025, time.October, 1, 0, 0, 0, 0, time.UTC)
+	fresh, notified := newConvertingTrial(convertsAt), newConvertingTrial(convertsAt)
+
+	mockTrials := new(MockRepository)
+	mockNotifications := new(MockNotificationRepository)
+	mockNotifier := new(MockNotifier)
+	reminder := NewTrialReminder(mockTrials, mockNotifications, mockNotifier, 72*time.Hour)
+	reminder.now = func() time.Time { return now }
+
+	mockTrials.On("ConvertingTrials", mock.Anything, postgres.TrialQuery{From: now, To: now.Add(72 * time.Hour)}).
+		Return([]models.TrialConversion{fresh, notified}, nil)
+	mockNotifications.On("Record", mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return *n.SubscriptionID == fresh.ID
+	})).Return(true, nil)
+	mockNotifications.On("Record", mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return *n.SubscriptionID == notified.ID
+	})).Return(false, nil)
+	mockNotifier.On("Notify", mock.Anything, mock.MatchedBy(func(n models.Notification) bool {
+		return n.UserID == fresh.UserID &&
+			n.Kind == models.NotificationTrialEnding &&
+			n.DueAt.Equal(convertsAt)
+	})).Return(nil)
+
+	sent, err := reminder.RemindOnce(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	mockNotifier.AssertNumberOfCalls(t, "Notify", 1)
+}
+
+func TestTrialReminder_RemindOnce_ForgetsUndelivered(t *testing.T) {
+	now := time.Date(2025, time.September, 28, 10, 0, 0, 0, time.UTC)
+	trial := newConvertingTrial(time.Date(2025, time.October, 1, 0, 0, 0, 0, time.UTC))
+
+	mockTrials := new(MockRepository)
+	mockNotifications := new(MockNotificationRepository)
+	mockNotifier := new(MockNotifier)
+	reminder := NewTrialReminder(mockTrials, mockNotifications, mockNotifier, 72*time.Hour)
+	reminder.now = func() time.Time { return now }
+
+	mockTrials.On("ConvertingTrials", mock.Anything, mock.Anything).Return([]models.TrialConversion{trial}, nil)
+	mockNotifications.On("Record", mock.Anything, mock.Anything).Return(true, nil)
+	mockNotifications.On("Forget", mock.Anything, mock.Anything).Return(nil)
+	mockNotifier.On("Notify", mock.Anything, mock.Anything).Return(errors.New("smtp unavailable"))
+
+	sent, err := reminder.RemindOnce(context.Background())
+
+	require.Error(t, err)
+	assert.Equal(t, 0, sent)
+	mockNotifications.AssertCalled(t, "Forget", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"effective-mobile-task/internal/models"
+	"effective-mobile-task/internal/repository/postgres"
+	"github.com/google/uuid"
+)
+
+var ErrUserNotFound = errors.New("данные пользователя не найдены")
+
+type UserDataRepository interface {
+	HasUserData(ctx context.Context, userID uuid.UUID) (bool, error)
+	ExportUserData(ctx context.Context, userID uuid.UUID, sink postgres.UserDataSink) error
+	EraseUserData(ctx context.Context, userID uuid.UUID) (*models.ErasureReceipt, error)
+}
+
+// UserDataService выгружает и удаляет персональные данные пользователя
+// по его запросу (GDPR, 152-ФЗ). Удалять данные может только
+// администратор.
+type UserDataService struct {
+	repo   UserDataRepository
+	policy Authorizer
+}
+
+func NewUserDataService(repo UserDataRepository, policy Authorizer) *UserDataService {
+	return &UserDataService{repo: repo, policy: policy}
+}
+
+// CheckUserData возвращает ErrUserNotFound, если о пользователе ничего не хранится.
+func (s *UserDataService) CheckUserData(ctx context.Context, userID uuid.UUID) error {
+	exists, err := s.repo.HasUserData(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("не удалось проверить данные пользователя: %w", err)
+	}
+	if !exists {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// ExportUserData пишет в w ZIP-архив, в котором каждый раздел данных
+// пользователя лежит отдельным JSON-файлом, а manifest.json описывает состав.
+func (s *UserDataService) ExportUserData(ctx context.Context, userID uuid.UUID, w io.Writer) error {
+	archive := &zipDataSink{
+		zw: zip.NewWriter(w),
+		manifest: dataExportManifest{
+			UserID:      userID,
+			GeneratedAt: time.Now().UTC(),
+			Sections:    make(map[string]int),
+		},
+	}
+
+	if err := s.repo.ExportUserData(ctx, userID, archive); err != nil {
+		return fmt.Errorf("не удалось выгрузить данные пользователя: %w", err)
+	}
+
+	return archive.Close()
+}
+
+// EraseUserData удаляет данные пользователя и возвращает квитанцию.
+func (s *UserDataService) EraseUserData(ctx context.Context, userID uuid.UUID) (*models.ErasureReceipt, error) {
+	if err := authorize(ctx, s.policy, ActionAdminister, &userID); err != nil {
+		return nil, err
+	}
+
+	receipt, err := s.repo.EraseUserData(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось удалить данные пользователя: %w", err)
+	}
+	return receipt, nil
+}
+
+type dataExportManifest struct {
+	UserID      uuid.UUID      `json:"user_id"`
+	GeneratedAt time.Time      `json:"generated_at"`
+	Sections    map[string]int `json:"sections"`
+}
+
+// zipDataSink пишет каждый раздел в архив как JSON-массив, строка за строкой.
+type zipDataSink struct {
+	zw       *zip.Writer
+	section  string
+	file     io.Writer
+	rows     int
+	manifest dataExportManifest
+}
+
+func (z *zipDataSink) BeginSection(name string) error {
+	f, err := z.zw.Create(name + ".json")
+	if err != nil {
+		return err
+	}
+	z.section, z.file, z.rows = name, f, 0
+
+	_, err = io.WriteString(f, "[")
+	return err
+}
+
+func (z *zipDataSink) WriteRow(row []byte) error {
+	sep := "\n  "
+	if z.rows > 0 {
+		sep = ",\n  "
+	}
+	if _, err := io.WriteString(z.file, sep); err != nil {
+		return err
+	}
+	if _, err := z.file.Write(row); err != nil {
+		return err
+	}
+	z.rows++
+	return nil
+}
+
+func (z *zipDataSink) EndSection() error {
+	end := "]\n"
+	if z.rows > 0 {
+		end = "\n]\n"
+	}
+	z.manifest.Sections[z.section] = z.rows
+	_, err := io.WriteString(z.file, end)
+	return err
+}
+
+func (z *zipDataSink) Close() error {
+	f, err := z.zw.Create("manifest.json")
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(z.manifest); err != nil {
+		return err
+	}
+
+	return z.zw.Close()
+}
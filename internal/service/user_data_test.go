@@ -0,0 +1,106 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"effective-mobile-task/internal/models"
+	"effective-mobile-task/internal/repository/postgres"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockUserDataRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserDataRepository) HasUserData(ctx context.Context, userID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+// ExportUserData отдаёт в sink разделы из Return: список разделов и их строки.
+func (m *MockUserDataRepository) ExportUserData(ctx context.Context, userID uuid.UUID, sink postgres.UserDataSink) error {
+	args := m.Called(ctx, userID)
+	sections := args.Get(0).([]string)
+	rows := args.Get(1).(map[string][]string)
+
+	for _, section := range sections {
+		if err := sink.BeginSection(section); err != nil {
+			return err
+		}
+		for _, row := range rows[section] {
+			if err := sink.WriteRow([]byte(row)); err != nil {
+				return err
+			}
+		}
+		if err := sink.EndSection(); err != nil {
+			return err
+		}
+	}
+	return args.Error(2)
+}
+
+func (m *MockUserDataRepository) EraseUserData(ctx context.Context, userID uuid.UUID) (*models.ErasureReceipt, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ErasureReceipt), args.Error(1)
+}
+
+func TestUserDataService_ExportUserData(t *testing.T) {
+	mockRepo := new(MockUserDataRepository)
+	service := NewUserDataService(mockRepo, nil)
+	userID := uuid.New()
+
+	mockRepo.On("ExportUserData", mock.Anything, userID).Return(
+		[]string{"subscriptions", "notifications"},
+		map[string][]string{"subscriptions": {`{"price":400}`, `{"price":300}`}},
+		nil,
+	)
+
+	var buf bytes.Buffer
+	require.NoError(t, service.ExportUserData(context.Background(), userID, &buf))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		files[f.Name], err = io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+	}
+
+	var subscriptions []map[string]int
+	require.NoError(t, json.Unmarshal(files["subscriptions.json"], &subscriptions))
+	assert.Equal(t, []map[string]int{{"price": 400}, {"price": 300}}, subscriptions)
+
+	var notifications []any
+	require.NoError(t, json.Unmarshal(files["notifications.json"], &notifications))
+	assert.Empty(t, notifications)
+
+	var manifest dataExportManifest
+	require.NoError(t, json.Unmarshal(files["manifest.json"], &manifest))
+	assert.Equal(t, userID, manifest.UserID)
+	assert.Equal(t, map[string]int{"subscriptions": 2, "notifications": 0}, manifest.Sections)
+}
+
+func TestUserDataService_CheckUserData_NotFound(t *testing.T) {
+	mockRepo := new(MockUserDataRepository)
+	service := NewUserDataService(mockRepo, nil)
+	userID := uuid.New()
+
+	mockRepo.On("HasUserData", mock.Anything, userID).Return(false, nil)
+
+	assert.ErrorIs(t, service.CheckUserData(context.Background(), userID), ErrUserNotFound)
+}
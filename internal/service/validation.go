@@ -0,0 +1,201 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"effective-mobile-task/internal/models"
+	"effective-mobile-task/internal/repository/postgres"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidSubscription     = errors.New("некорректные данные подписки")
+	ErrOverlappingSubscription = errors.New("подписка пересекается с уже существующей")
+)
+
+// Правила, которые проверяет доменная валидация. Обработчик использует их,
+// чтобы вернуть клиенту понятное сообщение.
+const (
+	RuleRequired              = "required"
+	RuleEndBeforeStart        = "end_before_start"
+	RuleServiceNameLength     = "service_name_length"
+	RuleServiceNameSymbols    = "service_name_symbols"
+	RuleInvalidFormat         = "invalid_format"
+	RulePricePositive         = "price_positive"
+	RulePriceFraction         = "price_fraction"
+	RuleTagLength             = "tag_length"
+	RuleTrialOutsidePeriod    = "trial_outside_period"
+	RuleChangeInPast          = "change_in_past"
+	RuleChangeOutsidePeriod   = "change_outside_period"
+	RulePriceMismatch         = "price_mismatch"
+	RuleDiscountPercent       = "discount_percent"
+	RuleDiscountTerm          = "discount_term"
+	RuleDiscountOutsidePeriod = "discount_outside_period"
+	RuleBudgetScope           = "budget_scope"
+	RuleBudgetThreshold       = "budget_threshold"
+	RuleMemberShare           = "member_share"
+	RuleCostCentreParent      = "cost_centre_parent"
+)
+
+const (
+	serviceNameMinLen = 2
+	serviceNameMaxLen = 100
+)
+
+// ValidationError — нарушение бизнес-правила в конкретном поле.
+type ValidationError struct {
+	Field string
+	Rule  string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("поле %s нарушает правило %s", e.Field, e.Rule)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidSubscription
+}
+
+// OverlapError — у пользователя уже есть подписка на этот сервис,
+// активная в пересекающийся период.
+type OverlapError struct {
+	ExistingID uuid.UUID
+}
+
+func (e *OverlapError) Error() string {
+	return fmt.Sprintf("подписка пересекается с подпиской %s", e.ExistingID)
+}
+
+func (e *OverlapError) Unwrap() error {
+	return ErrOverlappingSubscription
+}
+
+// canonicalServiceName убирает лишние пробелы по краям и внутри названия.
+func canonicalServiceName(name string) (string, error) {
+	return canonicalName("service_name", name)
+}
+
+// canonicalName нормализует название сервиса или его псевдоним; field
+// попадает в ошибку валидации.
+func canonicalName(field, name string) (string, error) {
+	if !utf8.ValidString(name) {
+		return "", &ValidationError{Field: field, Rule: RuleServiceNameSymbols}
+	}
+	for _, r := range name {
+		if unicode.IsControl(r) && !unicode.IsSpace(r) {
+			return "", &ValidationError{Field: field, Rule: RuleServiceNameSymbols}
+		}
+	}
+
+	canonical := strings.Join(strings.Fields(name), " ")
+
+	length := utf8.RuneCountInString(canonical)
+	if length < serviceNameMinLen || length > serviceNameMaxLen {
+		return "", &ValidationError{Field: field, Rule: RuleServiceNameLength}
+	}
+
+	return canonical, nil
+}
+
+// isDomainError сообщает, что ошибка вызвана данными клиента, а не сбоем.
+func isDomainError(err error) bool {
+	return errors.Is(err, ErrInvalidSubscription) ||
+		errors.Is(err, ErrOverlappingSubscription) ||
+		errors.Is(err, postgres.ErrNotFound)
+}
+
+// validatePeriod проверяет, что конец периода подписки не раньше его начала.
+func validatePeriod(start models.Month, end *models.Month) error {
+	if end != nil && end.Before(start) {
+		return &ValidationError{Field: "end_date", Rule: RuleEndBeforeStart}
+	}
+	return nil
+}
+
+// validateTrial проверяет, что пробный период не начинается раньше подписки
+// и не продолжается после её окончания.
+func validateTrial(start models.Month, end, trialEnd *models.Month) error {
+	if trialEnd == nil {
+		return nil
+	}
+	if trialEnd.Before(start) || (end != nil && trialEnd.After(*end)) {
+		return &ValidationError{Field: "trial_end_date", Rule: RuleTrialOutsidePeriod}
+	}
+	return nil
+}
+
+// resolvePricing вычисляет цену места и число мест из цены подписки, цены
+// места и числа мест, из которых клиент указал хотя бы цену или цену места.
+// Без числа мест подписка считается на одно место.
+func resolvePricing(price, unitPrice, quantity int) (int, int, error) {
+	if quantity == 0 {
+		quantity = 1
+	}
+
+	switch {
+	case unitPrice > 0:
+		if price != 0 && price != unitPrice*quantity {
+			return 0, 0, &ValidationError{Field: "price", Rule: RulePriceMismatch}
+		}
+	case price <= 0:
+		return 0, 0, &ValidationError{Field: "price", Rule: RulePricePositive}
+	case price%quantity != 0:
+		return 0, 0, &ValidationError{Field: "unit_price", Rule: RulePriceMismatch}
+	default:
+		unitPrice = price / quantity
+	}
+
+	return unitPrice, quantity, nil
+}
+
+// validateChangeMonth проверяет месяц, с которого меняется цена или число
+// мест: он должен попадать в период подписки и не быть прошлым, чтобы не
+// исказить уже посчитанные сводки.
+func validateChangeMonth(sub *models.Subscription, from, current models.Month) error {
+	if from.Before(current) {
+		return &ValidationError{Field: "effective_from", Rule: RuleChangeInPast}
+	}
+	if from.Before(sub.StartDate) || (sub.EndDate != nil && from.After(*sub.EndDate)) {
+		return &ValidationError{Field: "effective_from", Rule: RuleChangeOutsidePeriod}
+	}
+	return nil
+}
+
+// periodIndex помнит периоды подписок из операций пакета, которые ещё не
+// сохранены в БД, чтобы найти пересечения между ними. Пересечения строк
+// файла импорта ищет ImportSubscriptions во временной таблице.
+type periodIndex map[periodKey][]period
+
+type periodKey struct {
+	userID uuid.UUID
+	name   string
+}
+
+type period struct {
+	id    uuid.UUID
+	start models.Month
+	end   *models.Month
+}
+
+func (p periodIndex) overlapping(sub *models.Subscription) (uuid.UUID, bool) {
+	for _, other := range p[periodKey{userID: sub.UserID, name: strings.ToLower(sub.ServiceName)}] {
+		if other.id == sub.ID {
+			continue
+		}
+		startsBeforeOtherEnds := other.end == nil || !sub.StartDate.After(*other.end)
+		endsAfterOtherStarts := sub.EndDate == nil || !sub.EndDate.Before(other.start)
+		if startsBeforeOtherEnds && endsAfterOtherStarts {
+			return other.id, true
+		}
+	}
+	return uuid.Nil, false
+}
+
+func (p periodIndex) add(sub *models.Subscription) {
+	k := periodKey{userID: sub.UserID, name: strings.ToLower(sub.ServiceName)}
+	p[k] = append(p[k], period{id: sub.ID, start: sub.StartDate, end: sub.EndDate})
+}
@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS idx_subscriptions_user_service
+    ON subscriptions (user_id, lower(service_name));
@@ -0,0 +1,4 @@
+ALTER TABLE subscriptions
+    DROP CONSTRAINT IF EXISTS subscriptions_end_after_start,
+    DROP CONSTRAINT IF EXISTS subscriptions_end_date_first_day,
+    DROP CONSTRAINT IF EXISTS subscriptions_start_date_first_day;
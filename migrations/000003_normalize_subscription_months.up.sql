@@ -0,0 +1,8 @@
+UPDATE subscriptions
+SET start_date = date_trunc('month', start_date)::date,
+    end_date   = date_trunc('month', end_date)::date;
+
+ALTER TABLE subscriptions
+    ADD CONSTRAINT subscriptions_start_date_first_day CHECK (EXTRACT(DAY FROM start_date) = 1),
+    ADD CONSTRAINT subscriptions_end_date_first_day CHECK (end_date IS NULL OR EXTRACT(DAY FROM end_date) = 1),
+    ADD CONSTRAINT subscriptions_end_after_start CHECK (end_date IS NULL OR end_date >= start_date);
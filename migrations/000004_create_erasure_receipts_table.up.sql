@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS erasure_receipts (
+    id UUID PRIMARY KEY,
+    subject_hash CHAR(64) NOT NULL,
+    erased_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    affected JSONB NOT NULL DEFAULT '{}'::jsonb
+);
+
+CREATE INDEX IF NOT EXISTS idx_erasure_receipts_subject_hash ON erasure_receipts (subject_hash);
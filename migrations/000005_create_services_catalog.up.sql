@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS services (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    category VARCHAR(100),
+    website VARCHAR(2048),
+    default_price INT CHECK (default_price >= 0),
+    currency CHAR(3) NOT NULL DEFAULT 'RUB'
+);
+
+-- Каноническое название и все псевдонимы сервиса хранятся в одной таблице,
+-- чтобы одно и то же написание не могло относиться к двум сервисам.
+CREATE TABLE IF NOT EXISTS service_aliases (
+    alias_key VARCHAR(255) PRIMARY KEY,
+    service_id UUID NOT NULL REFERENCES services (id) ON DELETE CASCADE,
+    alias VARCHAR(255) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_service_aliases_service_id ON service_aliases (service_id);
+CREATE INDEX IF NOT EXISTS idx_services_category ON services (category);
+
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS service_id UUID REFERENCES services (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_service_id ON subscriptions (service_id);
@@ -0,0 +1,5 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Индекс обслуживает и нечёткий поиск (оператор %), и поиск по префиксу (LIKE 'abc%').
+CREATE INDEX IF NOT EXISTS idx_subscriptions_service_name_trgm
+    ON subscriptions USING GIN (lower(service_name) gin_trgm_ops);
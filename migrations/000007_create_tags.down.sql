@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS subscription_tags;
+DROP TABLE IF EXISTS tags;
+ALTER TABLE subscriptions DROP CONSTRAINT IF EXISTS subscriptions_id_user_id_key;
@@ -0,0 +1,25 @@
+-- Метки принадлежат пользователю: у каждого свой набор («работа», «семья»).
+CREATE TABLE IF NOT EXISTS tags (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    UNIQUE (user_id, name),
+    UNIQUE (id, user_id)
+);
+
+ALTER TABLE subscriptions
+    ADD CONSTRAINT subscriptions_id_user_id_key UNIQUE (id, user_id);
+
+-- user_id входит в обе составные ссылки, поэтому метку можно повесить
+-- только на подписку того же пользователя.
+CREATE TABLE IF NOT EXISTS subscription_tags (
+    subscription_id UUID NOT NULL,
+    tag_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    PRIMARY KEY (subscription_id, tag_id),
+    FOREIGN KEY (subscription_id, user_id) REFERENCES subscriptions (id, user_id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id, user_id) REFERENCES tags (id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_tags_tag_id ON subscription_tags (tag_id);
+CREATE INDEX IF NOT EXISTS idx_subscription_tags_user_id ON subscription_tags (user_id);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS subscription_status_changes;
+DROP TABLE IF EXISTS subscription_pauses;
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS status_changed_at,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,37 @@
+-- Состояние expired не хранится: оно вычисляется из end_date при чтении.
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'paused', 'cancelled')),
+    ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMPTZ;
+
+-- Периоды приостановки: месяцы с start_month включительно по end_month
+-- не включительно не оплачиваются. У открытой паузы end_month пуст.
+CREATE TABLE IF NOT EXISTS subscription_pauses (
+    id UUID PRIMARY KEY,
+    subscription_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    start_month DATE NOT NULL CHECK (start_month = date_trunc('month', start_month)),
+    end_month DATE CHECK (end_month = date_trunc('month', end_month)),
+    CHECK (end_month IS NULL OR end_month >= start_month),
+    FOREIGN KEY (subscription_id, user_id) REFERENCES subscriptions (id, user_id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_subscription_pauses_open
+    ON subscription_pauses (subscription_id) WHERE end_month IS NULL;
+CREATE INDEX IF NOT EXISTS idx_subscription_pauses_subscription_id ON subscription_pauses (subscription_id);
+CREATE INDEX IF NOT EXISTS idx_subscription_pauses_user_id ON subscription_pauses (user_id);
+
+CREATE TABLE IF NOT EXISTS subscription_status_changes (
+    id UUID PRIMARY KEY,
+    subscription_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    from_status VARCHAR(16) NOT NULL,
+    to_status VARCHAR(16) NOT NULL,
+    effective_month DATE NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL,
+    FOREIGN KEY (subscription_id, user_id) REFERENCES subscriptions (id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_status_changes_subscription_id
+    ON subscription_status_changes (subscription_id, changed_at);
+CREATE INDEX IF NOT EXISTS idx_subscription_status_changes_user_id ON subscription_status_changes (user_id);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS notifications;
+DROP INDEX IF EXISTS idx_subscriptions_trial_end_date;
+ALTER TABLE subscriptions
+    DROP CONSTRAINT IF EXISTS subscriptions_trial_end_date_check,
+    DROP COLUMN IF EXISTS trial_end_date;
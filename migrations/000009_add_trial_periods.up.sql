@@ -0,0 +1,25 @@
+-- Пробный период: месяцы с start_date по trial_end_date включительно
+-- бесплатны, первым оплачиваемым становится следующий месяц.
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS trial_end_date DATE
+        CHECK (trial_end_date = date_trunc('month', trial_end_date)),
+    ADD CONSTRAINT subscriptions_trial_end_date_check CHECK (trial_end_date >= start_date);
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_trial_end_date
+    ON subscriptions (trial_end_date) WHERE trial_end_date IS NOT NULL;
+
+-- Отправленные уведомления. Уникальный ключ не даёт отправить одно и то же
+-- уведомление о подписке дважды, даже если проверка запускается повторно.
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    subscription_id UUID,
+    kind VARCHAR(32) NOT NULL,
+    due_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    FOREIGN KEY (subscription_id, user_id) REFERENCES subscriptions (id, user_id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_notifications_subscription_kind_due
+    ON notifications (subscription_id, kind, due_at);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications (user_id);
@@ -0,0 +1,14 @@
+-- Изменения цены подписки: с effective_from подписка стоит price, пока не
+-- начнётся следующий период. До первого изменения действует цена из
+-- subscriptions.price.
+CREATE TABLE IF NOT EXISTS subscription_prices (
+    subscription_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    effective_from DATE NOT NULL CHECK (effective_from = date_trunc('month', effective_from)),
+    price INTEGER NOT NULL CHECK (price > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (subscription_id, effective_from),
+    FOREIGN KEY (subscription_id, user_id) REFERENCES subscriptions (id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_prices_user_id ON subscription_prices (user_id);
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS subscription_seats;
+
+ALTER TABLE subscription_prices RENAME COLUMN unit_price TO price;
+
+-- Цена с несколькими местами переносится в цену подписки целиком.
+UPDATE subscriptions SET unit_price = unit_price * quantity WHERE quantity <> 1;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS quantity;
+ALTER TABLE subscriptions RENAME COLUMN unit_price TO price;
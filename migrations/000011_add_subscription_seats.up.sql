@@ -0,0 +1,22 @@
+-- Цена подписки складывается из цены места и числа мест. Прежняя цена
+-- становится ценой места при одном месте.
+ALTER TABLE subscriptions RENAME COLUMN price TO unit_price;
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS quantity INTEGER NOT NULL DEFAULT 1 CHECK (quantity > 0);
+
+ALTER TABLE subscription_prices RENAME COLUMN price TO unit_price;
+
+-- Изменения числа мест: с effective_from у подписки quantity мест, пока не
+-- начнётся следующий период. До первого изменения действует
+-- subscriptions.quantity.
+CREATE TABLE IF NOT EXISTS subscription_seats (
+    subscription_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    effective_from DATE NOT NULL CHECK (effective_from = date_trunc('month', effective_from)),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (subscription_id, effective_from),
+    FOREIGN KEY (subscription_id, user_id) REFERENCES subscriptions (id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_seats_user_id ON subscription_seats (user_id);
@@ -0,0 +1,20 @@
+-- Скидки на подписку. Процентная скидка уменьшает цену месяца на value
+-- процентов, фиксированная — на value рублей. Скидка действует с start_month
+-- по end_month включительно; без end_month — до конца подписки.
+CREATE TABLE IF NOT EXISTS subscription_discounts (
+    id UUID PRIMARY KEY,
+    subscription_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    kind VARCHAR(16) NOT NULL CHECK (kind IN ('percent', 'fixed')),
+    value INTEGER NOT NULL CHECK (value > 0 AND (kind <> 'percent' OR value <= 100)),
+    start_month DATE NOT NULL CHECK (start_month = date_trunc('month', start_month)),
+    end_month DATE CHECK (end_month = date_trunc('month', end_month)),
+    promo_code VARCHAR(64),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (end_month IS NULL OR end_month >= start_month),
+    FOREIGN KEY (subscription_id, user_id) REFERENCES subscriptions (id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_discounts_subscription_id
+    ON subscription_discounts (subscription_id, start_month);
+CREATE INDEX IF NOT EXISTS idx_subscription_discounts_user_id ON subscription_discounts (user_id);
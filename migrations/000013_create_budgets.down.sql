@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS uq_notifications_budget_threshold_due;
+
+ALTER TABLE notifications
+    DROP COLUMN IF EXISTS threshold,
+    DROP COLUMN IF EXISTS budget_id;
+
+DROP TABLE IF EXISTS budgets;
@@ -0,0 +1,28 @@
+-- Месячные бюджеты пользователя: на все подписки, на категорию каталога или
+-- на один сервис. thresholds — доли бюджета в процентах, при достижении
+-- которых пользователь получает уведомление.
+CREATE TABLE IF NOT EXISTS budgets (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    scope VARCHAR(16) NOT NULL CHECK (scope IN ('overall', 'category', 'service')),
+    category VARCHAR(100),
+    service_name VARCHAR(100),
+    amount INTEGER NOT NULL CHECK (amount > 0),
+    thresholds INTEGER[] NOT NULL DEFAULT '{80,100}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK ((scope = 'category') = (category IS NOT NULL)),
+    CHECK ((scope = 'service') = (service_name IS NOT NULL))
+);
+
+-- У пользователя не больше одного бюджета на каждую область.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_budgets_user_scope
+    ON budgets (user_id, scope, lower(COALESCE(category, service_name, '')));
+
+-- Уведомления о бюджете: одно на каждый порог в каждом месяце.
+ALTER TABLE notifications
+    ADD COLUMN IF NOT EXISTS budget_id UUID REFERENCES budgets (id) ON DELETE CASCADE,
+    ADD COLUMN IF NOT EXISTS threshold INTEGER;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_notifications_budget_threshold_due
+    ON notifications (budget_id, threshold, due_at) WHERE budget_id IS NOT NULL;
@@ -0,0 +1,18 @@
+-- Участники общей подписки. Платит владелец подписки (payer_id), а участники
+-- возмещают ему свою долю: фиксированную сумму в месяц (share_amount) или
+-- долю остатка пропорционально весу (share_weight). Строка с самим
+-- владельцем задаёт только его вес; без неё вес владельца равен 1.
+CREATE TABLE IF NOT EXISTS subscription_members (
+    subscription_id UUID NOT NULL,
+    payer_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    share_weight INTEGER CHECK (share_weight > 0),
+    share_amount INTEGER CHECK (share_amount > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (subscription_id, user_id),
+    CHECK ((share_weight IS NULL) <> (share_amount IS NULL)),
+    CHECK (user_id <> payer_id OR share_amount IS NULL),
+    FOREIGN KEY (subscription_id, payer_id) REFERENCES subscriptions (id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_members_user_id ON subscription_members (user_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_subscriptions_cost_centre_id;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS cost_centre_id;
+
+DROP TABLE IF EXISTS cost_centres;
+DROP TABLE IF EXISTS organisations;
@@ -0,0 +1,30 @@
+-- Организации и их структура: подразделения и центры затрат образуют
+-- дерево внутри организации. Подписка относится к одному узлу дерева, и её
+-- стоимость входит в расходы этого узла и всех его предков.
+CREATE TABLE IF NOT EXISTS organisations (
+    id UUID PRIMARY KEY,
+    name VARCHAR(200) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_organisations_name ON organisations (lower(name));
+
+CREATE TABLE IF NOT EXISTS cost_centres (
+    id UUID PRIMARY KEY,
+    organisation_id UUID NOT NULL REFERENCES organisations (id) ON DELETE CASCADE,
+    -- Узел с дочерними узлами удалить нельзя, пока не удалены они.
+    parent_id UUID REFERENCES cost_centres (id),
+    kind VARCHAR(16) NOT NULL CHECK (kind IN ('department', 'cost_centre')),
+    code VARCHAR(32) NOT NULL,
+    name VARCHAR(200) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (organisation_id, code)
+);
+
+CREATE INDEX IF NOT EXISTS idx_cost_centres_parent_id ON cost_centres (parent_id);
+
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS cost_centre_id UUID REFERENCES cost_centres (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_cost_centre_id
+    ON subscriptions (cost_centre_id) WHERE cost_centre_id IS NOT NULL;
@@ -0,0 +1,36 @@
+ALTER TABLE subscriptions DROP CONSTRAINT subscriptions_cost_centre_id_fkey;
+ALTER TABLE subscriptions ADD CONSTRAINT subscriptions_cost_centre_id_fkey
+    FOREIGN KEY (cost_centre_id) REFERENCES cost_centres (id) ON DELETE SET NULL;
+ALTER TABLE cost_centres DROP CONSTRAINT IF EXISTS cost_centres_id_tenant_id_key;
+
+DROP INDEX IF EXISTS uq_organisations_name;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_organisations_name ON organisations (lower(name));
+
+DROP INDEX IF EXISTS uq_budgets_user_scope;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_budgets_user_scope
+    ON budgets (user_id, scope, lower(COALESCE(category, service_name, '')));
+
+ALTER TABLE tags DROP CONSTRAINT tags_tenant_id_user_id_name_key;
+ALTER TABLE tags ADD CONSTRAINT tags_user_id_name_key UNIQUE (user_id, name);
+
+ALTER TABLE service_aliases DROP CONSTRAINT service_aliases_pkey;
+ALTER TABLE service_aliases ADD PRIMARY KEY (alias_key);
+
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'subscriptions', 'erasure_receipts', 'services', 'service_aliases',
+        'tags', 'subscription_tags', 'subscription_pauses', 'subscription_status_changes',
+        'notifications', 'subscription_prices', 'subscription_seats', 'subscription_discounts',
+        'budgets', 'subscription_members', 'organisations', 'cost_centres'
+    ] LOOP
+        EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %I', t);
+        EXECUTE format('ALTER TABLE %I NO FORCE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I DISABLE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I DROP COLUMN IF EXISTS tenant_id', t);
+    END LOOP;
+END $$;
+
+DROP TABLE IF EXISTS tenants;
@@ -0,0 +1,62 @@
+-- Арендаторы: несколько клиентов в одной базе. Каждая строка принадлежит
+-- арендатору, а политики RLS пропускают только строки арендатора из
+-- переменной сеанса app.tenant_id, которую приложение задаёт в соединении
+-- на время запроса. Без переменной не видна ни одна строка.
+CREATE TABLE IF NOT EXISTS tenants (
+    id UUID PRIMARY KEY,
+    name VARCHAR(200) NOT NULL,
+    -- SHA-256 токена доступа арендатора в hex; сам токен не хранится.
+    api_token_hash CHAR(64) UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Арендатор по умолчанию: ему принадлежат данные, созданные до появления
+-- арендаторов, и запросы без арендатора, если он не обязателен.
+INSERT INTO tenants (id, name)
+VALUES ('00000000-0000-0000-0000-000000000000', 'default')
+ON CONFLICT (id) DO NOTHING;
+
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'subscriptions', 'erasure_receipts', 'services', 'service_aliases',
+        'tags', 'subscription_tags', 'subscription_pauses', 'subscription_status_changes',
+        'notifications', 'subscription_prices', 'subscription_seats', 'subscription_discounts',
+        'budgets', 'subscription_members', 'organisations', 'cost_centres'
+    ] LOOP
+        EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS tenant_id UUID NOT NULL'
+            ' DEFAULT ''00000000-0000-0000-0000-000000000000'' REFERENCES tenants (id) ON DELETE CASCADE', t);
+        EXECUTE format('ALTER TABLE %I ALTER COLUMN tenant_id'
+            ' SET DEFAULT NULLIF(current_setting(''app.tenant_id'', true), '''')::uuid', t);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS %I ON %I (tenant_id)', 'idx_' || t || '_tenant_id', t);
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', t);
+        -- Политики действуют и на владельца таблиц, от имени которого
+        -- работает приложение.
+        EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', t);
+        EXECUTE format('CREATE POLICY tenant_isolation ON %I'
+            ' USING (tenant_id = NULLIF(current_setting(''app.tenant_id'', true), '''')::uuid)', t);
+    END LOOP;
+END $$;
+
+-- Уникальность названий и псевдонимов — в пределах арендатора.
+ALTER TABLE service_aliases DROP CONSTRAINT service_aliases_pkey;
+ALTER TABLE service_aliases ADD PRIMARY KEY (tenant_id, alias_key);
+
+ALTER TABLE tags DROP CONSTRAINT tags_user_id_name_key;
+ALTER TABLE tags ADD CONSTRAINT tags_tenant_id_user_id_name_key UNIQUE (tenant_id, user_id, name);
+
+DROP INDEX IF EXISTS uq_budgets_user_scope;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_budgets_user_scope
+    ON budgets (tenant_id, user_id, scope, lower(COALESCE(category, service_name, '')));
+
+DROP INDEX IF EXISTS uq_organisations_name;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_organisations_name ON organisations (tenant_id, lower(name));
+
+-- Подписку можно отнести только на центр затрат своего арендатора:
+-- проверка внешнего ключа политики RLS не учитывает.
+ALTER TABLE cost_centres ADD CONSTRAINT cost_centres_id_tenant_id_key UNIQUE (id, tenant_id);
+ALTER TABLE subscriptions DROP CONSTRAINT subscriptions_cost_centre_id_fkey;
+ALTER TABLE subscriptions ADD CONSTRAINT subscriptions_cost_centre_id_fkey
+    FOREIGN KEY (cost_centre_id, tenant_id) REFERENCES cost_centres (id, tenant_id) ON DELETE SET NULL (cost_centre_id);
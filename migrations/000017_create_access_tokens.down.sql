@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS organisation_users;
+DROP TABLE IF EXISTS access_tokens;
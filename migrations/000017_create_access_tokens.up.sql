@@ -0,0 +1,40 @@
+-- Токены доступа пользователей с ролью. Токен арендатора из tenants даёт
+-- права администратора без привязки к пользователю.
+CREATE TABLE IF NOT EXISTS access_tokens (
+    -- SHA-256 токена в hex; сам токен не хранится.
+    token_hash CHAR(64) PRIMARY KEY,
+    tenant_id UUID NOT NULL DEFAULT NULLIF(current_setting('app.tenant_id', true), '')::uuid
+        REFERENCES tenants (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    role VARCHAR(16) NOT NULL CHECK (role IN ('admin', 'manager', 'viewer')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_access_tokens_tenant_user ON access_tokens (tenant_id, user_id);
+
+ALTER TABLE access_tokens ENABLE ROW LEVEL SECURITY;
+ALTER TABLE access_tokens FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON access_tokens
+    USING (tenant_id = NULLIF(current_setting('app.tenant_id', true), '')::uuid);
+-- Токен ищется до того, как известен арендатор: без арендатора токены
+-- можно только читать.
+CREATE POLICY token_lookup ON access_tokens FOR SELECT
+    USING (NULLIF(current_setting('app.tenant_id', true), '') IS NULL);
+
+-- Пользователи организации. Менеджер может менять подписки пользователей
+-- организаций, в которых состоит сам.
+CREATE TABLE IF NOT EXISTS organisation_users (
+    organisation_id UUID NOT NULL REFERENCES organisations (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    tenant_id UUID NOT NULL DEFAULT NULLIF(current_setting('app.tenant_id', true), '')::uuid
+        REFERENCES tenants (id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (organisation_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_organisation_users_user_id ON organisation_users (user_id);
+
+ALTER TABLE organisation_users ENABLE ROW LEVEL SECURITY;
+ALTER TABLE organisation_users FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON organisation_users
+    USING (tenant_id = NULLIF(current_setting('app.tenant_id', true), '')::uuid);
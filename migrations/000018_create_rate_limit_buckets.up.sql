@@ -0,0 +1,12 @@
+-- Вёдра ограничения частоты запросов, общие для всех реплик сервиса.
+-- Таблица не относится к данным арендаторов и не защищена RLS.
+CREATE TABLE IF NOT EXISTS rate_limit_buckets (
+    -- Клиент, метод и шаблон маршрута.
+    key TEXT PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    -- Разрешён ли последний запрос.
+    allowed BOOLEAN NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limit_buckets_updated_at ON rate_limit_buckets (updated_at);
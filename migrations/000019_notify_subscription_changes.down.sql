@@ -0,0 +1,14 @@
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'subscriptions', 'services', 'service_aliases', 'subscription_tags',
+        'subscription_pauses', 'subscription_status_changes', 'subscription_prices',
+        'subscription_seats', 'subscription_discounts', 'subscription_members'
+    ] LOOP
+        EXECUTE format('DROP TRIGGER IF EXISTS notify_subscription_changes ON %I', t);
+    END LOOP;
+END $$;
+
+DROP FUNCTION IF EXISTS notify_subscription_changes();
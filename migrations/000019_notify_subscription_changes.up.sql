@@ -0,0 +1,26 @@
+-- Уведомления об изменении подписок для сброса кэша во всех репликах.
+-- Триггеры срабатывают раз на оператор, а одинаковые уведомления в одной
+-- транзакции Postgres объединяет, поэтому массовый импорт отправляет одно
+-- уведомление. Содержимое — арендатор из app.tenant_id или пустая строка,
+-- если изменение сделано без арендатора.
+CREATE OR REPLACE FUNCTION notify_subscription_changes() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('subscription_changes', COALESCE(current_setting('app.tenant_id', true), ''));
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'subscriptions', 'services', 'service_aliases', 'subscription_tags',
+        'subscription_pauses', 'subscription_status_changes', 'subscription_prices',
+        'subscription_seats', 'subscription_discounts', 'subscription_members'
+    ] LOOP
+        EXECUTE format('CREATE TRIGGER notify_subscription_changes'
+            ' AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON %I'
+            ' FOR EACH STATEMENT EXECUTE FUNCTION notify_subscription_changes()', t);
+    END LOOP;
+END $$;
@@ -0,0 +1,19 @@
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'subscriptions', 'subscription_pauses', 'subscription_prices', 'subscription_seats',
+        'subscription_discounts', 'subscription_members'
+    ] LOOP
+        EXECUTE format('DROP TRIGGER IF EXISTS mark_monthly_spend_dirty ON %I', t);
+    END LOOP;
+END $$;
+
+DROP FUNCTION IF EXISTS mark_monthly_spend_dirty();
+DROP TABLE IF EXISTS monthly_spend_state;
+DROP TABLE IF EXISTS monthly_spend_dirty;
+DROP TABLE IF EXISTS monthly_spend;
+
+DROP INDEX IF EXISTS idx_subscriptions_period;
+DROP INDEX IF EXISTS idx_subscriptions_service_name;
@@ -0,0 +1,91 @@
+-- Индексы для фильтров сводки по основной таблице. Фильтр по user_id
+-- использует idx_subscriptions_user_service.
+CREATE INDEX IF NOT EXISTS idx_subscriptions_service_name ON subscriptions (service_name);
+CREATE INDEX IF NOT EXISTS idx_subscriptions_period ON subscriptions (start_date, end_date);
+
+-- Стоимость подписок по месяцам: сколько каждый пользователь заплатил за
+-- подписку в каждом оплачиваемом месяце до текущего включительно. Для
+-- совместных подписок у каждого участника своя строка с его долей. Сводки
+-- без фильтров по категории, меткам и похожему названию читают эту
+-- таблицу вместо пересчёта всех подписок.
+CREATE TABLE IF NOT EXISTS monthly_spend (
+    tenant_id UUID NOT NULL DEFAULT NULLIF(current_setting('app.tenant_id', true), '')::uuid
+        REFERENCES tenants (id) ON DELETE CASCADE,
+    subscription_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    service_name VARCHAR(255) NOT NULL,
+    month DATE NOT NULL,
+    gross INT NOT NULL,
+    amount INT NOT NULL,
+    PRIMARY KEY (subscription_id, user_id, month)
+);
+
+CREATE INDEX IF NOT EXISTS idx_monthly_spend_tenant_month ON monthly_spend (tenant_id, month);
+CREATE INDEX IF NOT EXISTS idx_monthly_spend_user_month ON monthly_spend (user_id, month);
+CREATE INDEX IF NOT EXISTS idx_monthly_spend_service_month ON monthly_spend (service_name, month);
+
+-- Подписки, стоимость которых изменилась после последнего обновления
+-- monthly_spend.
+CREATE TABLE IF NOT EXISTS monthly_spend_dirty (
+    subscription_id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL DEFAULT NULLIF(current_setting('app.tenant_id', true), '')::uuid
+        REFERENCES tenants (id) ON DELETE CASCADE
+);
+
+-- Последний месяц, по который построена monthly_spend арендатора. Когда
+-- наступает новый месяц, таблица строится заново.
+CREATE TABLE IF NOT EXISTS monthly_spend_state (
+    tenant_id UUID PRIMARY KEY DEFAULT NULLIF(current_setting('app.tenant_id', true), '')::uuid
+        REFERENCES tenants (id) ON DELETE CASCADE,
+    horizon DATE NOT NULL,
+    refreshed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY['monthly_spend', 'monthly_spend_dirty', 'monthly_spend_state'] LOOP
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', t);
+        EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', t);
+        EXECUTE format('CREATE POLICY tenant_isolation ON %I'
+            ' USING (tenant_id = NULLIF(current_setting(''app.tenant_id'', true), '''')::uuid)', t);
+    END LOOP;
+END $$;
+
+-- Отмечает подписку, стоимость которой могла измениться. Аргумент
+-- триггера — колонка с ID подписки.
+CREATE OR REPLACE FUNCTION mark_monthly_spend_dirty() RETURNS trigger AS $$
+DECLARE
+    changed JSONB;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        changed := to_jsonb(OLD);
+    ELSE
+        changed := to_jsonb(NEW);
+    END IF;
+
+    INSERT INTO monthly_spend_dirty (subscription_id, tenant_id)
+    VALUES ((changed ->> TG_ARGV[0])::uuid, (changed ->> 'tenant_id')::uuid)
+    ON CONFLICT (subscription_id) DO NOTHING;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER mark_monthly_spend_dirty
+    AFTER INSERT OR UPDATE OR DELETE ON subscriptions
+    FOR EACH ROW EXECUTE FUNCTION mark_monthly_spend_dirty('id');
+
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'subscription_pauses', 'subscription_prices', 'subscription_seats',
+        'subscription_discounts', 'subscription_members'
+    ] LOOP
+        EXECUTE format('CREATE TRIGGER mark_monthly_spend_dirty'
+            ' AFTER INSERT OR UPDATE OR DELETE ON %I'
+            ' FOR EACH ROW EXECUTE FUNCTION mark_monthly_spend_dirty(''subscription_id'')', t);
+    END LOOP;
+END $$;
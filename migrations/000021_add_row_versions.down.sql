@@ -0,0 +1,17 @@
+DROP SCHEMA IF EXISTS history CASCADE;
+
+DO $$
+DECLARE
+    t TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'subscriptions', 'subscription_prices', 'subscription_seats',
+        'subscription_discounts', 'subscription_pauses', 'subscription_members'
+    ] LOOP
+        EXECUTE format('DROP TRIGGER IF EXISTS keep_row_version ON %I', t);
+        EXECUTE format('DROP TABLE IF EXISTS %I', t || '_history');
+        EXECUTE format('ALTER TABLE %I DROP COLUMN IF EXISTS sys_period', t);
+    END LOOP;
+END $$;
+
+DROP FUNCTION IF EXISTS keep_row_version();
@@ -0,0 +1,75 @@
+-- История версий строк подписок и всего, из чего складывается их стоимость.
+-- sys_period — когда версия строки была текущей. Текущая версия лежит в
+-- самой таблице с открытым периодом, прошлые — в <таблица>_history.
+-- Строки, появившиеся до включения истории, считаются текущими с начала
+-- времён.
+--
+-- Схема history содержит одноимённые представления, которые показывают
+-- версии строк на момент app.as_of. Запрос «на момент» выполняется с
+-- search_path = history, public, поэтому тот же SQL читает историю.
+-- Новая колонка в таблице с историей должна быть добавлена и в
+-- <таблица>_history, а представление — пересоздано.
+CREATE SCHEMA IF NOT EXISTS history;
+
+-- Сохраняет прежнюю версию изменённой или удалённой строки.
+CREATE OR REPLACE FUNCTION keep_row_version() RETURNS trigger AS $$
+DECLARE
+    version RECORD;
+BEGIN
+    IF TG_OP = 'UPDATE' THEN
+        IF NEW IS NOT DISTINCT FROM OLD THEN
+            RETURN NEW;
+        END IF;
+        NEW.sys_period := tstzrange(now(), NULL);
+    END IF;
+
+    -- Без арендатора строки удаляются только вместе с арендатором, и их
+    -- история не нужна. Версия, созданная в этой же транзакции, не была
+    -- видна ни в один момент.
+    IF NULLIF(current_setting('app.tenant_id', true), '') IS NOT NULL AND lower(OLD.sys_period) < now() THEN
+        version := OLD;
+        version.sys_period := tstzrange(lower(OLD.sys_period), now());
+        EXECUTE format('INSERT INTO public.%I SELECT ($1).*', TG_TABLE_NAME || '_history') USING version;
+    END IF;
+
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DO $$
+DECLARE
+    t TEXT;
+    key TEXT;
+BEGIN
+    FOREACH t IN ARRAY ARRAY[
+        'subscriptions', 'subscription_prices', 'subscription_seats',
+        'subscription_discounts', 'subscription_pauses', 'subscription_members'
+    ] LOOP
+        key := CASE WHEN t = 'subscriptions' THEN 'id' ELSE 'subscription_id' END;
+
+        EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS sys_period TSTZRANGE NOT NULL'
+            ' DEFAULT tstzrange(''-infinity'', NULL)', t);
+        EXECUTE format('ALTER TABLE %I ALTER COLUMN sys_period SET DEFAULT tstzrange(now(), NULL)', t);
+
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %I (LIKE %I)', t || '_history', t);
+        EXECUTE format('ALTER TABLE %I ADD FOREIGN KEY (tenant_id) REFERENCES tenants (id) ON DELETE CASCADE', t || '_history');
+        EXECUTE format('CREATE INDEX IF NOT EXISTS %I ON %I (%I)', 'idx_' || t || '_history_' || key, t || '_history', key);
+        EXECUTE format('CREATE INDEX IF NOT EXISTS %I ON %I USING gist (sys_period)', 'idx_' || t || '_history_sys_period', t || '_history');
+        EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', t || '_history');
+        EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', t || '_history');
+        EXECUTE format('CREATE POLICY tenant_isolation ON %I'
+            ' USING (tenant_id = NULLIF(current_setting(''app.tenant_id'', true), '''')::uuid)', t || '_history');
+
+        EXECUTE format('CREATE TRIGGER keep_row_version BEFORE UPDATE OR DELETE ON %I'
+            ' FOR EACH ROW EXECUTE FUNCTION keep_row_version()', t);
+
+        EXECUTE format('CREATE OR REPLACE VIEW history.%I WITH (security_invoker = true) AS'
+            ' SELECT * FROM public.%I WHERE sys_period @> NULLIF(current_setting(''app.as_of'', true), '''')::timestamptz'
+            ' UNION ALL'
+            ' SELECT * FROM public.%I WHERE sys_period @> NULLIF(current_setting(''app.as_of'', true), '''')::timestamptz',
+            t, t, t || '_history');
+    END LOOP;
+END $$;
@@ -0,0 +1,23 @@
+CREATE OR REPLACE FUNCTION keep_row_version() RETURNS trigger AS $$
+DECLARE
+    version RECORD;
+BEGIN
+    IF TG_OP = 'UPDATE' THEN
+        IF NEW IS NOT DISTINCT FROM OLD THEN
+            RETURN NEW;
+        END IF;
+        NEW.sys_period := tstzrange(now(), NULL);
+    END IF;
+
+    IF NULLIF(current_setting('app.tenant_id', true), '') IS NOT NULL AND lower(OLD.sys_period) < now() THEN
+        version := OLD;
+        version.sys_period := tstzrange(lower(OLD.sys_period), now());
+        EXECUTE format('INSERT INTO public.%I SELECT ($1).*', TG_TABLE_NAME || '_history') USING version;
+    END IF;
+
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,32 @@
+-- Удаление данных пользователя не должно оставлять их прошлые версии:
+-- транзакция удаления задаёт app.erasing = on, и триггер не сохраняет
+-- версии удаляемых строк в <таблица>_history.
+CREATE OR REPLACE FUNCTION keep_row_version() RETURNS trigger AS $$
+DECLARE
+    version RECORD;
+BEGIN
+    IF TG_OP = 'UPDATE' THEN
+        IF NEW IS NOT DISTINCT FROM OLD THEN
+            RETURN NEW;
+        END IF;
+        NEW.sys_period := tstzrange(now(), NULL);
+    END IF;
+
+    -- Без арендатора строки удаляются только вместе с арендатором, и их
+    -- история не нужна. Версия, созданная в этой же транзакции, не была
+    -- видна ни в один момент. При удалении данных пользователя история
+    -- удаляется вместе с ними.
+    IF NULLIF(current_setting('app.tenant_id', true), '') IS NOT NULL
+        AND lower(OLD.sys_period) < now()
+        AND COALESCE(current_setting('app.erasing', true), '') <> 'on' THEN
+        version := OLD;
+        version.sys_period := tstzrange(lower(OLD.sys_period), now());
+        EXECUTE format('INSERT INTO public.%I SELECT ($1).*', TG_TABLE_NAME || '_history') USING version;
+    END IF;
+
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
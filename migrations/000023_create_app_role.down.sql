@@ -0,0 +1,10 @@
+ALTER DEFAULT PRIVILEGES IN SCHEMA public
+    REVOKE USAGE, SELECT ON SEQUENCES FROM subscriptions_app;
+ALTER DEFAULT PRIVILEGES IN SCHEMA public
+    REVOKE SELECT, INSERT, UPDATE, DELETE ON TABLES FROM subscriptions_app;
+
+REVOKE ALL ON ALL SEQUENCES IN SCHEMA public FROM subscriptions_app;
+REVOKE ALL ON ALL TABLES IN SCHEMA public FROM subscriptions_app;
+REVOKE USAGE ON SCHEMA public FROM subscriptions_app;
+
+DROP ROLE IF EXISTS subscriptions_app;
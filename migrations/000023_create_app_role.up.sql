@@ -0,0 +1,24 @@
+-- Роль, от имени которой работает приложение. Суперпользователь и роли с
+-- BYPASSRLS обходят политики RLS даже с FORCE ROW LEVEL SECURITY, поэтому
+-- миграции выполняет владелец таблиц, а приложение подключается
+-- пользователем, входящим в эту роль:
+-- CREATE ROLE app LOGIN PASSWORD '...' IN ROLE subscriptions_app.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'subscriptions_app') THEN
+        CREATE ROLE subscriptions_app NOLOGIN NOSUPERUSER NOBYPASSRLS;
+    END IF;
+END $$;
+
+GRANT USAGE ON SCHEMA public TO subscriptions_app;
+GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public TO subscriptions_app;
+GRANT USAGE, SELECT ON ALL SEQUENCES IN SCHEMA public TO subscriptions_app;
+
+-- Версию схемы меняют только миграции.
+REVOKE ALL ON TABLE schema_migrations FROM subscriptions_app;
+
+-- Таблицы следующих миграций тоже доступны приложению.
+ALTER DEFAULT PRIVILEGES IN SCHEMA public
+    GRANT SELECT, INSERT, UPDATE, DELETE ON TABLES TO subscriptions_app;
+ALTER DEFAULT PRIVILEGES IN SCHEMA public
+    GRANT USAGE, SELECT ON SEQUENCES TO subscriptions_app;
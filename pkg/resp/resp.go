@@ -0,0 +1,187 @@
+// Package resp — минимальный клиент протокола RESP2, на котором работают
+// Redis и совместимые с ним хранилища (Valkey, KeyDB, Dragonfly). Клиент
+// умеет только отправлять команду и читать ответ; соединения переиспользуются.
+package resp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// ErrNil — ответ nil: например, GET ключа, которого нет.
+var ErrNil = errors.New("resp: nil reply")
+
+// Error — ошибка, которую вернул сервер.
+type Error string
+
+func (e Error) Error() string { return "resp: " + string(e) }
+
+// dialTimeout ограничивает подключение, если у контекста нет дедлайна.
+const dialTimeout = 5 * time.Second
+
+type conn struct {
+	nc net.Conn
+	r  *bufio.Reader
+	w  *bufio.Writer
+}
+
+// Client отправляет команды на сервер addr. Безопасен для одновременного
+// использования; держит не больше poolSize простаивающих соединений.
+type Client struct {
+	addr     string
+	password string
+	idle     chan *conn
+}
+
+// NewClient создаёт клиента. Пустой password отключает AUTH.
+func NewClient(addr, password string, poolSize int) *Client {
+	return &Client{addr: addr, password: password, idle: make(chan *conn, poolSize)}
+}
+
+// Do отправляет команду и возвращает ответ: string для простых строк и
+// bulk-строк, int64 для чисел, []any для массивов. Ответ nil возвращается
+// как ErrNil, ошибка сервера — как Error.
+func (c *Client) Do(ctx context.Context, args ...string) (any, error) {
+	cn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := cn.do(ctx, args)
+	var serverErr Error
+	if err != nil && !errors.Is(err, ErrNil) && !errors.As(err, &serverErr) {
+		// После сетевой ошибки в соединении может остаться часть ответа.
+		cn.nc.Close()
+		return nil, err
+	}
+	c.put(cn)
+
+	return reply, err
+}
+
+// Close закрывает простаивающие соединения.
+func (c *Client) Close() error {
+	for {
+		select {
+		case cn := <-c.idle:
+			cn.nc.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+func (c *Client) get(ctx context.Context) (*conn, error) {
+	select {
+	case cn := <-c.idle:
+		return cn, nil
+	default:
+	}
+
+	d := net.Dialer{Timeout: dialTimeout}
+	nc, err := d.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, fmt.Errorf("resp: подключение к %s: %w", c.addr, err)
+	}
+	cn := &conn{nc: nc, r: bufio.NewReader(nc), w: bufio.NewWriter(nc)}
+
+	if c.password != "" {
+		if _, err := cn.do(ctx, []string{"AUTH", c.password}); err != nil {
+			nc.Close()
+			return nil, err
+		}
+	}
+
+	return cn, nil
+}
+
+func (c *Client) put(cn *conn) {
+	select {
+	case c.idle <- cn:
+	default:
+		cn.nc.Close()
+	}
+}
+
+func (cn *conn) do(ctx context.Context, args []string) (any, error) {
+	deadline, _ := ctx.Deadline()
+	cn.nc.SetDeadline(deadline)
+
+	fmt.Fprintf(cn.w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(cn.w, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if err := cn.w.Flush(); err != nil {
+		return nil, err
+	}
+
+	return readReply(cn.r)
+}
+
+func readReply(r *bufio.Reader) (any, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, errors.New("resp: пустой ответ")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, Error(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("resp: неверная длина строки %q", line)
+		}
+		if n < 0 {
+			return nil, ErrNil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("resp: неверная длина массива %q", line)
+		}
+		if n < 0 {
+			return nil, ErrNil
+		}
+		items := make([]any, n)
+		for i := range items {
+			item, err := readReply(r)
+			if err != nil && !errors.Is(err, ErrNil) {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	}
+
+	return nil, fmt.Errorf("resp: неизвестный тип ответа %q", line)
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", fmt.Errorf("resp: строка без CRLF %q", line)
+	}
+	return line[:len(line)-2], nil
+}
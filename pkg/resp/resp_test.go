@@ -0,0 +1,91 @@
+package resp
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serve отвечает на команды по таблице replies: ключ — команда с
+// аргументами через пробел.
+func serve(t *testing.T, replies map[string]string) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			nc, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer nc.Close()
+				r := bufio.NewReader(nc)
+				for {
+					cmd, err := readReply(r)
+					if err != nil {
+						return
+					}
+					var args []string
+					for _, arg := range cmd.([]any) {
+						args = append(args, arg.(string))
+					}
+					reply, ok := replies[strings.Join(args, " ")]
+					if !ok {
+						reply = "-ERR unknown command\r\n"
+					}
+					nc.Write([]byte(reply))
+				}
+			}()
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+func TestClient_Do(t *testing.T) {
+	addr := serve(t, map[string]string{
+		"AUTH secret":     "+OK\r\n",
+		"SET k v PX 1000": "+OK\r\n",
+		"GET k":           "$1\r\nv\r\n",
+		"GET missing":     "$-1\r\n",
+		"INCR gen":        ":7\r\n",
+		"MGET k missing":  "*2\r\n$1\r\nv\r\n$-1\r\n",
+	})
+
+	c := NewClient(addr, "secret", 2)
+	defer c.Close()
+	ctx := context.Background()
+
+	reply, err := c.Do(ctx, "SET", "k", "v", "PX", "1000")
+	require.NoError(t, err)
+	assert.Equal(t, "OK", reply)
+
+	reply, err = c.Do(ctx, "GET", "k")
+	require.NoError(t, err)
+	assert.Equal(t, "v", reply)
+
+	_, err = c.Do(ctx, "GET", "missing")
+	assert.ErrorIs(t, err, ErrNil)
+
+	reply, err = c.Do(ctx, "INCR", "gen")
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), reply)
+
+	reply, err = c.Do(ctx, "MGET", "k", "missing")
+	require.NoError(t, err)
+	assert.Equal(t, []any{"v", nil}, reply)
+
+	_, err = c.Do(ctx, "FLUSHALL")
+	var serverErr Error
+	require.ErrorAs(t, err, &serverErr)
+	assert.Equal(t, "ERR unknown command", string(serverErr))
+}
@@ -0,0 +1,261 @@
+// Package xlsx пишет книги Excel (Office Open XML) потоком: строки сразу
+// уходят в zip-архив и не накапливаются в памяти, поэтому файл любого
+// размера можно отдавать прямо в http.ResponseWriter.
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxRowsPerSheet — ограничение Excel на число строк в листе. При его
+// достижении Writer продолжает запись на новом листе с тем же заголовком.
+const MaxRowsPerSheet = 1 << 20
+
+const maxSheetNameLen = 31
+
+type Writer struct {
+	zw       *zip.Writer
+	sheet    *bufio.Writer
+	baseName string
+	header   []string
+	sheets   []string
+	row      int
+	maxRows  int
+	closed   bool
+}
+
+// NewWriter создаёт книгу с листом sheetName. Если header не пуст, он
+// записывается первой строкой каждого листа.
+func NewWriter(w io.Writer, sheetName string, header []string) *Writer {
+	return &Writer{
+		zw:       zip.NewWriter(w),
+		baseName: sanitizeSheetName(sheetName),
+		header:   header,
+		maxRows:  MaxRowsPerSheet,
+	}
+}
+
+// WriteRow добавляет строку. Поддерживаются строки, целые и дробные числа,
+// bool, time.Time, fmt.Stringer и nil (пустая ячейка).
+func (w *Writer) WriteRow(values []any) error {
+	if w.closed {
+		return fmt.Errorf("xlsx: запись в закрытую книгу")
+	}
+
+	if w.sheet == nil || w.row >= w.maxRows {
+		if err := w.nextSheet(); err != nil {
+			return err
+		}
+	}
+
+	return w.writeRow(values)
+}
+
+// Close завершает текущий лист и дописывает служебные части книги.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	if w.sheet == nil {
+		if err := w.nextSheet(); err != nil {
+			return err
+		}
+	}
+	if err := w.finishSheet(); err != nil {
+		return err
+	}
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", w.contentTypes()},
+		{"_rels/.rels", rootRels},
+		{"xl/workbook.xml", w.workbook()},
+		{"xl/_rels/workbook.xml.rels", w.workbookRels()},
+	}
+	for _, part := range parts {
+		f, err := w.zw.Create(part.name)
+		if err != nil {
+			return fmt.Errorf("xlsx: не удалось создать %s: %w", part.name, err)
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return fmt.Errorf("xlsx: не удалось записать %s: %w", part.name, err)
+		}
+	}
+
+	return w.zw.Close()
+}
+
+func (w *Writer) nextSheet() error {
+	if w.sheet != nil {
+		if err := w.finishSheet(); err != nil {
+			return err
+		}
+	}
+
+	name := w.baseName
+	if n := len(w.sheets) + 1; n > 1 {
+		suffix := " " + strconv.Itoa(n)
+		name = truncate(w.baseName, maxSheetNameLen-len(suffix)) + suffix
+	}
+	w.sheets = append(w.sheets, name)
+
+	f, err := w.zw.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", len(w.sheets)))
+	if err != nil {
+		return fmt.Errorf("xlsx: не удалось создать лист: %w", err)
+	}
+	w.sheet = bufio.NewWriter(f)
+	w.row = 0
+
+	if _, err := w.sheet.WriteString(xml.Header + `<worksheet xmlns="` + nsMain + `"><sheetData>`); err != nil {
+		return err
+	}
+
+	if len(w.header) > 0 {
+		values := make([]any, len(w.header))
+		for i, h := range w.header {
+			values[i] = h
+		}
+		return w.writeRow(values)
+	}
+
+	return nil
+}
+
+func (w *Writer) finishSheet() error {
+	if _, err := w.sheet.WriteString(`</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	return w.sheet.Flush()
+}
+
+func (w *Writer) writeRow(values []any) error {
+	w.row++
+
+	b := w.sheet
+	b.WriteString(`<row r="`)
+	b.WriteString(strconv.Itoa(w.row))
+	b.WriteString(`">`)
+
+	for _, v := range values {
+		switch v := v.(type) {
+		case nil:
+			b.WriteString(`<c/>`)
+		case string:
+			writeString(b, v)
+		case int:
+			writeNumber(b, strconv.Itoa(v))
+		case int64:
+			writeNumber(b, strconv.FormatInt(v, 10))
+		case float64:
+			writeNumber(b, strconv.FormatFloat(v, 'f', -1, 64))
+		case bool:
+			if v {
+				b.WriteString(`<c t="b"><v>1</v></c>`)
+			} else {
+				b.WriteString(`<c t="b"><v>0</v></c>`)
+			}
+		case time.Time:
+			writeString(b, v.Format(time.RFC3339))
+		case fmt.Stringer:
+			writeString(b, v.String())
+		default:
+			writeString(b, fmt.Sprint(v))
+		}
+	}
+
+	_, err := b.WriteString(`</row>`)
+	return err
+}
+
+func writeString(b *bufio.Writer, s string) {
+	b.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+	xml.EscapeText(b, []byte(s))
+	b.WriteString(`</t></is></c>`)
+}
+
+func writeNumber(b *bufio.Writer, n string) {
+	b.WriteString(`<c><v>`)
+	b.WriteString(n)
+	b.WriteString(`</v></c>`)
+}
+
+const (
+	nsMain    = "http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+	nsRels    = "http://schemas.openxmlformats.org/package/2006/relationships"
+	nsDocRels = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+)
+
+const rootRels = xml.Header + `<Relationships xmlns="` + nsRels + `">` +
+	`<Relationship Id="rId1" Type="` + nsDocRels + `/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+func (w *Writer) contentTypes() string {
+	var sb strings.Builder
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	sb.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	sb.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	sb.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	for i := range w.sheets {
+		fmt.Fprintf(&sb, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+	}
+	sb.WriteString(`</Types>`)
+	return sb.String()
+}
+
+func (w *Writer) workbook() string {
+	var sb strings.Builder
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<workbook xmlns="` + nsMain + `" xmlns:r="` + nsDocRels + `"><sheets>`)
+	for i, name := range w.sheets {
+		sb.WriteString(`<sheet name="`)
+		xml.EscapeText(&sb, []byte(name))
+		fmt.Fprintf(&sb, `" sheetId="%d" r:id="rId%d"/>`, i+1, i+1)
+	}
+	sb.WriteString(`</sheets></workbook>`)
+	return sb.String()
+}
+
+func (w *Writer) workbookRels() string {
+	var sb strings.Builder
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<Relationships xmlns="` + nsRels + `">`)
+	for i := range w.sheets {
+		fmt.Fprintf(&sb, `<Relationship Id="rId%d" Type="%s/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, nsDocRels, i+1)
+	}
+	sb.WriteString(`</Relationships>`)
+	return sb.String()
+}
+
+// sanitizeSheetName убирает символы, запрещённые Excel в названиях листов.
+func sanitizeSheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if name == "" {
+		name = "Sheet"
+	}
+	return truncate(name, maxSheetNameLen)
+}
+
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
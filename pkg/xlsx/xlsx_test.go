@@ -0,0 +1,74 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readParts(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	parts := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		parts[f.Name] = string(content)
+	}
+	return parts
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, "Подписки", []string{"service_name", "price"})
+
+	require.NoError(t, w.WriteRow([]any{"Yandex <Plus> & Co", 400}))
+	require.NoError(t, w.WriteRow([]any{nil, int64(100)}))
+	require.NoError(t, w.Close())
+
+	parts := readParts(t, buf.Bytes())
+	assert.Contains(t, parts, "[Content_Types].xml")
+	assert.Contains(t, parts, "_rels/.rels")
+	assert.Contains(t, parts["xl/workbook.xml"], `<sheet name="Подписки" sheetId="1" r:id="rId1"/>`)
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<row r="1"><c t="inlineStr"><is><t xml:space="preserve">service_name</t>`)
+	assert.Contains(t, sheet, `Yandex &lt;Plus&gt; &amp; Co`)
+	assert.Contains(t, sheet, `<c><v>400</v></c>`)
+	assert.Contains(t, sheet, `<row r="3"><c/><c><v>100</v></c></row>`)
+}
+
+func TestWriter_RollsOverToNewSheet(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, "Data", []string{"n"})
+	w.maxRows = 3
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, w.WriteRow([]any{i}))
+	}
+	require.NoError(t, w.Close())
+
+	parts := readParts(t, buf.Bytes())
+	assert.Contains(t, parts["xl/workbook.xml"], `<sheet name="Data 3" sheetId="3" r:id="rId3"/>`)
+	assert.Contains(t, parts["[Content_Types].xml"], `/xl/worksheets/sheet3.xml`)
+	assert.Contains(t, parts["xl/worksheets/sheet2.xml"], `<row r="1"><c t="inlineStr"><is><t xml:space="preserve">n</t></is></c></row>`)
+}
+
+func TestWriter_EmptyWorkbook(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, NewWriter(&buf, "a/b", nil).Close())
+
+	parts := readParts(t, buf.Bytes())
+	assert.Contains(t, parts["xl/workbook.xml"], `name="a_b"`)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+`<worksheet xmlns="`+nsMain+`"><sheetData></sheetData></worksheet>`, parts["xl/worksheets/sheet1.xml"])
+}